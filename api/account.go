@@ -3,11 +3,12 @@ package api
 import (
 	"database/sql"
 	"errors"
+	"math"
 	"net/http"
+	"time"
 
 	db "github.com/awakim/immoblock-backend/db/sqlc"
 	"github.com/awakim/immoblock-backend/token"
-	"github.com/go-playground/validator/v10"
 	"github.com/lib/pq"
 
 	"github.com/gin-gonic/gin"
@@ -20,12 +21,7 @@ type createAccountRequest struct {
 func (server *Server) createAccount(ctx *gin.Context) {
 	var req createAccountRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		var verr validator.ValidationErrors
-		if errors.As(err, &verr) {
-			ctx.JSON(http.StatusBadRequest, gin.H{"errors": ValidationError(verr)})
-			return
-		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errorResponse(err)})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
@@ -41,11 +37,11 @@ func (server *Server) createAccount(ctx *gin.Context) {
 		if pqErr, ok := err.(*pq.Error); ok {
 			switch pqErr.Code.Name() {
 			case "foreign_key_violation", "unique_violation":
-				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				respondError(ctx, http.StatusForbidden, err)
 				return
 			}
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -59,25 +55,12 @@ type getAccountRequest struct {
 func (server *Server) getAccount(ctx *gin.Context) {
 	var req getAccountRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	account, err := server.Store.GetAccount(ctx, req.ID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if account.UserID != authPayload.UserID {
-		err := errors.New("account does not belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	account, valid := server.ownedAccount(ctx, req.ID)
+	if !valid {
 		return
 	}
 
@@ -92,7 +75,7 @@ type listAccountsRequest struct {
 func (server *Server) listAccounts(ctx *gin.Context) {
 	var req listAccountsRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
@@ -106,9 +89,159 @@ func (server *Server) listAccounts(ctx *gin.Context) {
 	accounts, err := server.Store.ListAccounts(ctx, arg)
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, accounts)
 }
+
+type listAccountHistoryRequest struct {
+	Cursor    int64     `form:"cursor" binding:"omitempty,min=1"`
+	PageSize  int32     `form:"page_size" binding:"required,min=5,max=10"`
+	StartTime time.Time `form:"start_time"`
+	EndTime   time.Time `form:"end_time"`
+}
+
+// bounds returns the keyset cursor and the [start, end) time range of the request,
+// with open ends replaced by values matching every row.
+func (req listAccountHistoryRequest) bounds() (int64, time.Time, time.Time, error) {
+	cursor := req.Cursor
+	if cursor == 0 {
+		cursor = math.MaxInt64
+	}
+
+	end := req.EndTime
+	if end.IsZero() {
+		end = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+	}
+
+	if !req.StartTime.Before(end) {
+		return 0, req.StartTime, end, errors.New("start_time must be before end_time")
+	}
+
+	return cursor, req.StartTime, end, nil
+}
+
+type listEntriesResponse struct {
+	Entries    []db.Entry `json:"entries"`
+	NextCursor int64      `json:"next_cursor,omitempty"`
+}
+
+func (server *Server) listAccountEntries(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var req listAccountHistoryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	cursor, start, end, err := req.bounds()
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	account, valid := server.ownedAccount(ctx, uri.ID)
+	if !valid {
+		return
+	}
+
+	arg := db.ListAccountEntriesParams{
+		AccountID: account.ID,
+		Cursor:    cursor,
+		StartTime: start,
+		EndTime:   end,
+		Limit:     req.PageSize,
+	}
+
+	entries, err := server.Store.ListAccountEntries(ctx, arg)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	rsp := listEntriesResponse{Entries: entries}
+	if len(entries) == int(req.PageSize) {
+		rsp.NextCursor = entries[len(entries)-1].ID
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type listTransfersResponse struct {
+	Transfers  []db.Transfer `json:"transfers"`
+	NextCursor int64         `json:"next_cursor,omitempty"`
+}
+
+func (server *Server) listAccountTransfers(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var req listAccountHistoryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	cursor, start, end, err := req.bounds()
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	account, valid := server.ownedAccount(ctx, uri.ID)
+	if !valid {
+		return
+	}
+
+	arg := db.ListAccountTransfersParams{
+		AccountID: account.ID,
+		Cursor:    cursor,
+		StartTime: start,
+		EndTime:   end,
+		Limit:     req.PageSize,
+	}
+
+	transfers, err := server.Store.ListAccountTransfers(ctx, arg)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	rsp := listTransfersResponse{Transfers: transfers}
+	if len(transfers) == int(req.PageSize) {
+		rsp.NextCursor = transfers[len(transfers)-1].ID
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// ownedAccount fetches an account and checks it belongs to the authenticated user,
+// writing the error response when it does not.
+func (server *Server) ownedAccount(ctx *gin.Context, accountID int64) (db.Account, bool) {
+	account, err := server.Store.GetAccount(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, err)
+			return account, false
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return account, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.UserID != authPayload.UserID {
+		err := errors.New("account does not belong to the authenticated user")
+		respondError(ctx, http.StatusUnauthorized, err)
+		return account, false
+	}
+
+	return account, true
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// activityCursor is the keyset position of the last item of an activity page.
+type activityCursor struct {
+	CreatedAt time.Time
+	Kind      string
+	ID        int64
+	AccountID int64
+}
+
+func (c activityCursor) encode() string {
+	raw := fmt.Sprintf("%s|%s|%d|%d", c.CreatedAt.UTC().Format(time.RFC3339Nano), c.Kind, c.ID, c.AccountID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeActivityCursor(s string) (activityCursor, error) {
+	var c activityCursor
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, errInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 4 {
+		return c, errInvalidCursor
+	}
+
+	c.CreatedAt, err = time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return c, errInvalidCursor
+	}
+	c.Kind = parts[1]
+	c.ID, err = strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return c, errInvalidCursor
+	}
+	c.AccountID, err = strconv.ParseInt(parts[3], 10, 64)
+	if err != nil {
+		return c, errInvalidCursor
+	}
+
+	return c, nil
+}
+
+type activityItem struct {
+	Kind                  string    `json:"kind"`
+	ID                    int64     `json:"id"`
+	AccountID             int64     `json:"account_id"`
+	PropertyID            int64     `json:"property_id"`
+	Amount                int64     `json:"amount"`
+	Direction             string    `json:"direction"`
+	CounterpartyAccountID *int64    `json:"counterparty_account_id,omitempty"`
+	CreatedAt             time.Time `json:"created_at"`
+}
+
+func newActivityItem(row db.ListUserActivityRow) activityItem {
+	item := activityItem{
+		Kind:       row.Kind,
+		ID:         row.ID,
+		AccountID:  row.AccountID,
+		PropertyID: row.PropertyID,
+		Amount:     row.Amount,
+		Direction:  row.Direction,
+		CreatedAt:  row.CreatedAt,
+	}
+	if row.CounterpartyAccountID.Valid {
+		counterparty := row.CounterpartyAccountID.Int64
+		item.CounterpartyAccountID = &counterparty
+	}
+	return item
+}
+
+type listActivityRequest struct {
+	Cursor   string `form:"cursor"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+type listActivityResponse struct {
+	Items      []activityItem `json:"items"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// listActivity returns the transfers and purchases of every account
+// of the authenticated user, newest first.
+func (server *Server) listActivity(ctx *gin.Context) {
+	var req listActivityRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	// the first page starts after every possible item
+	cursor := activityCursor{
+		CreatedAt: time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC),
+		ID:        math.MaxInt64,
+		AccountID: math.MaxInt64,
+	}
+	if req.Cursor != "" {
+		var err error
+		cursor, err = decodeActivityCursor(req.Cursor)
+		if err != nil {
+			respondError(ctx, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.ListUserActivityParams{
+		UserID:          authPayload.UserID,
+		CursorTime:      cursor.CreatedAt,
+		CursorKind:      cursor.Kind,
+		CursorID:        cursor.ID,
+		CursorAccountID: cursor.AccountID,
+		Limit:           req.PageSize,
+	}
+
+	rows, err := server.Store.ListUserActivity(ctx, arg)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	rsp := listActivityResponse{Items: make([]activityItem, len(rows))}
+	for i, row := range rows {
+		rsp.Items[i] = newActivityItem(row)
+	}
+	if len(rows) == int(req.PageSize) {
+		last := rows[len(rows)-1]
+		rsp.NextCursor = activityCursor{
+			CreatedAt: last.CreatedAt,
+			Kind:      last.Kind,
+			ID:        last.ID,
+			AccountID: last.AccountID,
+		}.encode()
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
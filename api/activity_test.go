@@ -0,0 +1,142 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockcache "github.com/awakim/immoblock-backend/cache/mock"
+	mockdb "github.com/awakim/immoblock-backend/db/mock"
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	mockidentity "github.com/awakim/immoblock-backend/identity/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListActivityAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	createdAt := time.Date(2021, time.June, 1, 12, 0, 0, 123456000, time.UTC)
+
+	n := 5
+	rows := make([]db.ListUserActivityRow, n)
+	for i := 0; i < n; i++ {
+		rows[i] = db.ListUserActivityRow{
+			Kind:       "transfer",
+			ID:         int64(n - i),
+			AccountID:  1,
+			PropertyID: 1,
+			Amount:     -10,
+			Direction:  "out",
+			CounterpartyAccountID: sql.NullInt64{
+				Int64: 2,
+				Valid: true,
+			},
+			CreatedAt: createdAt.Add(-time.Duration(i) * time.Minute),
+		}
+	}
+	last := rows[n-1]
+	cursor := activityCursor{
+		CreatedAt: last.CreatedAt,
+		Kind:      last.Kind,
+		ID:        last.ID,
+		AccountID: last.AccountID,
+	}.encode()
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "FirstPage",
+			query: fmt.Sprintf("page_size=%d", n),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListUserActivity(gomock.Any(), gomock.Any()).Times(1).Return(rows, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp listActivityResponse
+				err := json.NewDecoder(recorder.Body).Decode(&rsp)
+				require.NoError(t, err)
+				require.Len(t, rsp.Items, n)
+				require.Equal(t, "out", rsp.Items[0].Direction)
+				require.Equal(t, int64(2), *rsp.Items[0].CounterpartyAccountID)
+				require.Equal(t, cursor, rsp.NextCursor)
+			},
+		},
+		{
+			name:  "NextPage",
+			query: fmt.Sprintf("page_size=%d&cursor=%s", n, cursor),
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListUserActivityParams{
+					UserID:          user.ID,
+					CursorTime:      last.CreatedAt,
+					CursorKind:      last.Kind,
+					CursorID:        last.ID,
+					CursorAccountID: last.AccountID,
+					Limit:           int32(n),
+				}
+				store.EXPECT().ListUserActivity(gomock.Any(), gomock.Eq(arg)).Times(1).Return(rows[:1], nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp listActivityResponse
+				err := json.NewDecoder(recorder.Body).Decode(&rsp)
+				require.NoError(t, err)
+				require.Len(t, rsp.Items, 1)
+				require.Empty(t, rsp.NextCursor)
+			},
+		},
+		{
+			name:  "InvalidCursor",
+			query: fmt.Sprintf("page_size=%d&cursor=not-a-cursor", n),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListUserActivity(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidPageSize",
+			query: "page_size=50",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListUserActivity(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/users/activity?"+tc.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,385 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	identity "github.com/awakim/immoblock-backend/identity/auth0"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var (
+	errUserNotFound     = errors.New("user not found")
+	errUserLocked       = errors.New("user is locked, please contact support")
+	errLockSelf         = errors.New("staff members cannot lock themselves")
+	errUserInfoNotFound = errors.New("user information not found")
+)
+
+// userLocked tells if the user has been locked by the support team
+func userLocked(user db.User) bool {
+	return !user.LockedAt.IsZero()
+}
+
+// auditAdminAction records an action of the authenticated staff member on a user.
+func (server *Server) auditAdminAction(ctx *gin.Context, userID uuid.UUID, kind string, details string) error {
+	actor := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	ip, _ := getIP(ctx)
+
+	_, err := server.Store.CreateAuditEvent(ctx, db.CreateAuditEventParams{
+		UserID:  userID,
+		Kind:    kind,
+		Ip:      ip,
+		Details: details,
+		ActorID: uuid.NullUUID{UUID: actor.UserID, Valid: true},
+	})
+	return err
+}
+
+type adminUserRequest struct {
+	UserID string `uri:"id" binding:"required,uuid"`
+}
+
+// getAdminUser reads the user of the request, answering and returning false when it cannot.
+func (server *Server) getAdminUser(ctx *gin.Context) (db.User, bool) {
+	var req adminUserRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return db.User{}, false
+	}
+
+	user, err := server.Store.GetUserByID(ctx, uuid.MustParse(req.UserID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, errUserNotFound)
+			return db.User{}, false
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return db.User{}, false
+	}
+	return user, true
+}
+
+type adminUserResponse struct {
+	userResponse
+	LockedAt     time.Time `json:"locked_at"`
+	LockedReason string    `json:"locked_reason"`
+}
+
+func newAdminUserResponse(user db.User) adminUserResponse {
+	return adminUserResponse{
+		userResponse: newUserResponse(user),
+		LockedAt:     user.LockedAt,
+		LockedReason: user.LockedReason,
+	}
+}
+
+type adminPageRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+type searchUsersRequest struct {
+	Query string `form:"query" binding:"required,min=2"`
+	adminPageRequest
+}
+
+// searchUsers finds the users whose email address or nickname contains the query.
+func (server *Server) searchUsers(ctx *gin.Context) {
+	var req searchUsersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	users, err := server.Store.SearchUsers(ctx, db.SearchUsersParams{
+		Query:  req.Query,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	rsp := make([]adminUserResponse, len(users))
+	for i, user := range users {
+		rsp[i] = newAdminUserResponse(user)
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type adminUserDetailsResponse struct {
+	User        adminUserResponse `json:"user"`
+	Roles       []string          `json:"roles"`
+	Information *userInfoResponse `json:"information"`
+}
+
+// getUser shows a user along with their roles and the information they provided, if any.
+func (server *Server) getUser(ctx *gin.Context) {
+	user, ok := server.getAdminUser(ctx)
+	if !ok {
+		return
+	}
+
+	roles, err := server.Store.ListUserRoles(ctx, user.ID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	rsp := adminUserDetailsResponse{
+		User:  newAdminUserResponse(user),
+		Roles: roles,
+	}
+
+	userInfo, err := server.Store.GetUserInfo(ctx, user.ID)
+	if err != nil && err != sql.ErrNoRows {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if err == nil {
+		info := newUserInfoResponse(userInfo)
+		rsp.Information = &info
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+func (server *Server) listUserAccounts(ctx *gin.Context) {
+	user, ok := server.getAdminUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req adminPageRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	accounts, err := server.Store.ListAccounts(ctx, db.ListAccountsParams{
+		UserID: user.ID,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, accounts)
+}
+
+// listUserTransfers lists the transfers from or to any account of the user, the latest first.
+func (server *Server) listUserTransfers(ctx *gin.Context) {
+	user, ok := server.getAdminUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req adminPageRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	transfers, err := server.Store.ListUserTransfers(ctx, db.ListUserTransfersParams{
+		UserID: user.ID,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, transfers)
+}
+
+func (server *Server) listUserAuditEvents(ctx *gin.Context) {
+	user, ok := server.getAdminUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req adminPageRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	events, err := server.Store.ListAuditEvents(ctx, db.ListAuditEventsParams{
+		UserID: user.ID,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, events)
+}
+
+// listUserInfoChanges lists the changes of the information of the user, the latest first.
+func (server *Server) listUserInfoChanges(ctx *gin.Context) {
+	user, ok := server.getAdminUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req adminPageRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	changes, err := server.Store.ListUserInfoChanges(ctx, db.ListUserInfoChangesParams{
+		UserID: user.ID,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, changes)
+}
+
+type adminReasonRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// bindAdminReason reads the reason of a staff action, answering and returning false when it is invalid.
+func bindAdminReason(ctx *gin.Context) (string, bool) {
+	var req adminReasonRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return "", false
+	}
+	return req.Reason, true
+}
+
+// lockUser prevents the user from logging in, here and with Auth0, and signs them out everywhere.
+func (server *Server) lockUser(ctx *gin.Context) {
+	user, ok := server.getAdminUser(ctx)
+	if !ok {
+		return
+	}
+
+	reason, ok := bindAdminReason(ctx)
+	if !ok {
+		return
+	}
+
+	actor := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if actor.UserID == user.ID {
+		respondError(ctx, http.StatusForbidden, errLockSelf)
+		return
+	}
+
+	err := identity.Block(server.UserManager, user.ID, true)
+	if err != nil {
+		respondError(ctx, http.StatusBadGateway, identityError(err))
+		return
+	}
+
+	user, err = server.Store.LockUser(ctx, db.LockUserParams{
+		ID:           user.ID,
+		LockedReason: reason,
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = server.Cache.RevokeUserTokens(ctx, user.ID.String(), time.Now().UTC(), server.Config.RefreshTokenDuration)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = server.auditAdminAction(ctx, user.ID, db.AuditUserLocked, reason)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAdminUserResponse(user))
+}
+
+func (server *Server) unlockUser(ctx *gin.Context) {
+	user, ok := server.getAdminUser(ctx)
+	if !ok {
+		return
+	}
+
+	err := identity.Block(server.UserManager, user.ID, false)
+	if err != nil {
+		respondError(ctx, http.StatusBadGateway, identityError(err))
+		return
+	}
+
+	user, err = server.Store.UnlockUser(ctx, user.ID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = server.auditAdminAction(ctx, user.ID, db.AuditUserUnlocked, "")
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAdminUserResponse(user))
+}
+
+// signOutUser signs the user out everywhere, the user can log in again right away.
+func (server *Server) signOutUser(ctx *gin.Context) {
+	user, ok := server.getAdminUser(ctx)
+	if !ok {
+		return
+	}
+
+	err := server.Cache.RevokeUserTokens(ctx, user.ID.String(), time.Now().UTC(), server.Config.RefreshTokenDuration)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = server.auditAdminAction(ctx, user.ID, db.AuditUserSignedOut, "")
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "user has been logged out of all sessions",
+	})
+}
+
+// getAdminUserInfo reads the information of the user of the request, answering and returning false when it cannot.
+func (server *Server) getAdminUserInfo(ctx *gin.Context) (db.UserInformation, bool) {
+	user, ok := server.getAdminUser(ctx)
+	if !ok {
+		return db.UserInformation{}, false
+	}
+
+	userInfo, err := server.Store.GetUserInfo(ctx, user.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, errUserInfoNotFound)
+			return db.UserInformation{}, false
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return db.UserInformation{}, false
+	}
+	return userInfo, true
+}
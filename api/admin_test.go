@@ -0,0 +1,558 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/auth0/go-auth0/management"
+	mockcache "github.com/awakim/immoblock-backend/cache/mock"
+	mockdb "github.com/awakim/immoblock-backend/db/mock"
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	identity "github.com/awakim/immoblock-backend/identity/auth0"
+	mockidentity "github.com/awakim/immoblock-backend/identity/mock"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+var supportAccess = token.Access{
+	Roles:       []string{db.RoleInvestor, db.RoleSupport},
+	Permissions: []string{db.PermissionAdminAccess, db.PermissionUsersRead, db.PermissionUsersWrite},
+}
+
+// serveAdminRequest sends a request authenticated as the staff member with the access given,
+// once the server has been set up by the functions given if any.
+func serveAdminRequest(
+	t *testing.T,
+	method string,
+	url string,
+	body gin.H,
+	staffID uuid.UUID,
+	access token.Access,
+	buildStubs func(store *mockdb.MockStore, cache *mockcache.MockCache),
+	setupServer ...func(server *Server),
+) *httptest.ResponseRecorder {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	cache := mockcache.NewMockCache(ctrl)
+	userManager := mockidentity.NewMockUserManagement(ctrl)
+	cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+	buildStubs(store, cache)
+
+	server := newTestServer(t, store, cache, userManager)
+	for _, setup := range setupServer {
+		setup(server)
+	}
+	recorder := httptest.NewRecorder()
+
+	var data []byte
+	if body != nil {
+		var err error
+		data, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+
+	request, err := http.NewRequest(method, url, bytes.NewReader(data))
+	require.NoError(t, err)
+	request.RemoteAddr = "127.0.0.1:12345"
+	addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, staffID, access, time.Minute)
+
+	server.Router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+// expectAdminAudit expects an audit event of the kind to be recorded for the user on behalf of the staff member
+func expectAdminAudit(store *mockdb.MockStore, userID uuid.UUID, staffID uuid.UUID, kind string) {
+	store.EXPECT().
+		CreateAuditEvent(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ interface{}, arg db.CreateAuditEventParams) (db.AuditEvent, error) {
+			if arg.UserID != userID || arg.Kind != kind || arg.ActorID != (uuid.NullUUID{UUID: staffID, Valid: true}) {
+				return db.AuditEvent{}, fmt.Errorf("unexpected audit event %+v", arg)
+			}
+			return db.AuditEvent{ID: 1, UserID: arg.UserID, Kind: arg.Kind, ActorID: arg.ActorID}, nil
+		})
+}
+
+func TestSearchUsersAPI(t *testing.T) {
+	staff, _ := randomUser(t)
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		query         string
+		access        token.Access
+		buildStubs    func(store *mockdb.MockStore, cache *mockcache.MockCache)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			query:  "query=" + user.Nickname + "&page_id=2&page_size=5",
+			access: supportAccess,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				arg := db.SearchUsersParams{Query: user.Nickname, Limit: 5, Offset: 5}
+				store.EXPECT().SearchUsers(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.User{user}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp []adminUserResponse
+				err := json.NewDecoder(recorder.Body).Decode(&rsp)
+				require.NoError(t, err)
+				require.Len(t, rsp, 1)
+				require.Equal(t, user.ID, rsp[0].UserID)
+				require.Equal(t, user.Email, rsp[0].Email)
+				require.NotContains(t, recorder.Body.String(), user.HashedPassword)
+			},
+		},
+		{
+			name:   "QueryTooShort",
+			query:  "query=a&page_id=1&page_size=5",
+			access: supportAccess,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().SearchUsers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "MissingPermission",
+			query:  "query=" + user.Nickname + "&page_id=1&page_size=5",
+			access: propertyManagerAccess,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().SearchUsers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:  "MissingAdminAccess",
+			query: "query=" + user.Nickname + "&page_id=1&page_size=5",
+			access: token.Access{
+				Roles:       []string{db.RoleInvestor, db.RoleSupport},
+				Permissions: []string{db.PermissionUsersRead, db.PermissionUsersWrite},
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().SearchUsers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "InternalError",
+			query:  "query=" + user.Nickname + "&page_id=1&page_size=5",
+			access: supportAccess,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().SearchUsers(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveAdminRequest(t, http.MethodGet, "/admin/users?"+tc.query, nil, staff.ID, tc.access, tc.buildStubs)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestAdminRoutesRequireAdminAccess(t *testing.T) {
+	staff, _ := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	cache := mockcache.NewMockCache(ctrl)
+	cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).AnyTimes().Return(false, nil)
+
+	server := newTestServer(t, store, cache, mockidentity.NewMockUserManagement(ctrl))
+
+	// every route of the back-office is guarded, whatever the permission it checks itself
+	n := 0
+	for _, route := range server.Router.Routes() {
+		if !strings.HasPrefix(route.Path, "/admin/") {
+			continue
+		}
+		n++
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(route.Method, route.Path, nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, staff.ID, propertyManagerAccess, time.Minute)
+
+		server.Router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusForbidden, recorder.Code, "%s %s", route.Method, route.Path)
+	}
+	require.NotZero(t, n)
+}
+
+func TestGetUserAdminAPI(t *testing.T) {
+	staff, _ := randomUser(t)
+	user, _ := randomUser(t)
+	userInfo := randomUserInfo(user.ID)
+
+	testCases := []struct {
+		name          string
+		userID        string
+		buildStubs    func(store *mockdb.MockStore, cache *mockcache.MockCache)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			userID: user.ID.String(),
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().ListUserRoles(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]string{db.RoleInvestor}, nil)
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(userInfo, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp adminUserDetailsResponse
+				err := json.NewDecoder(recorder.Body).Decode(&rsp)
+				require.NoError(t, err)
+				require.Equal(t, user.ID, rsp.User.UserID)
+				require.Equal(t, []string{db.RoleInvestor}, rsp.Roles)
+				require.NotNil(t, rsp.Information)
+				require.Equal(t, newUserInfoResponse(userInfo), *rsp.Information)
+			},
+		},
+		{
+			name:   "NoInformation",
+			userID: user.ID.String(),
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().ListUserRoles(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]string{db.RoleInvestor}, nil)
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.UserInformation{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp adminUserDetailsResponse
+				err := json.NewDecoder(recorder.Body).Decode(&rsp)
+				require.NoError(t, err)
+				require.Nil(t, rsp.Information)
+			},
+		},
+		{
+			name:   "NotFound",
+			userID: user.ID.String(),
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "InvalidID",
+			userID: "invalid",
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveAdminRequest(t, http.MethodGet, "/admin/users/"+tc.userID, nil, staff.ID, supportAccess, tc.buildStubs)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListUserAccountsAndTransfersAPI(t *testing.T) {
+	staff, _ := randomUser(t)
+	user, _ := randomUser(t)
+	account := randomAccount(user.ID)
+	transfer := db.Transfer{ID: 1, FromAccountID: account.ID, ToAccountID: account.ID + 1, Amount: 10}
+
+	recorder := serveAdminRequest(t, http.MethodGet, fmt.Sprintf("/admin/users/%s/accounts?page_id=1&page_size=5", user.ID), nil, staff.ID, supportAccess,
+		func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+			arg := db.ListAccountsParams{UserID: user.ID, Limit: 5, Offset: 0}
+			store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+			store.EXPECT().ListAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.Account{account}, nil)
+		})
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var accounts []db.Account
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&accounts))
+	require.Equal(t, []db.Account{account}, accounts)
+
+	recorder = serveAdminRequest(t, http.MethodGet, fmt.Sprintf("/admin/users/%s/transfers?page_id=1&page_size=5", user.ID), nil, staff.ID, supportAccess,
+		func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+			arg := db.ListUserTransfersParams{UserID: user.ID, Limit: 5, Offset: 0}
+			store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+			store.EXPECT().ListUserTransfers(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.Transfer{transfer}, nil)
+		})
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var transfers []db.Transfer
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&transfers))
+	require.Len(t, transfers, 1)
+	require.Equal(t, transfer.ID, transfers[0].ID)
+
+	recorder = serveAdminRequest(t, http.MethodGet, fmt.Sprintf("/admin/users/%s/transfers?page_id=0&page_size=5", user.ID), nil, staff.ID, supportAccess,
+		func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+			store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+			store.EXPECT().ListUserTransfers(gomock.Any(), gomock.Any()).Times(0)
+		})
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+// expectAuth0Block expects the user to be blocked or unblocked in Auth0.
+func expectAuth0Block(userManager *mockidentity.MockUserManagement, userID uuid.UUID, blocked bool, err error) {
+	userManager.EXPECT().
+		Update(gomock.Eq(identity.UserID(userID)), gomock.Eq(&management.User{Blocked: &blocked})).
+		Times(1).
+		Return(err)
+}
+
+func TestLockUserAPI(t *testing.T) {
+	staff, _ := randomUser(t)
+	user, _ := randomUser(t)
+	reason := "fraud suspicion"
+
+	testCases := []struct {
+		name          string
+		userID        uuid.UUID
+		body          gin.H
+		access        token.Access
+		buildStubs    func(store *mockdb.MockStore, cache *mockcache.MockCache)
+		buildIdentity func(userManager *mockidentity.MockUserManagement)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			userID: user.ID,
+			body:   gin.H{"reason": reason},
+			access: supportAccess,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				locked := user
+				locked.LockedAt = time.Now().UTC()
+				locked.LockedReason = reason
+
+				arg := db.LockUserParams{ID: user.ID, LockedReason: reason}
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().LockUser(gomock.Any(), gomock.Eq(arg)).Times(1).Return(locked, nil)
+				cache.EXPECT().RevokeUserTokens(gomock.Any(), gomock.Eq(user.ID.String()), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+				expectAdminAudit(store, user.ID, staff.ID, db.AuditUserLocked)
+			},
+			buildIdentity: func(userManager *mockidentity.MockUserManagement) {
+				expectAuth0Block(userManager, user.ID, true, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp adminUserResponse
+				err := json.NewDecoder(recorder.Body).Decode(&rsp)
+				require.NoError(t, err)
+				require.False(t, rsp.LockedAt.IsZero())
+				require.Equal(t, reason, rsp.LockedReason)
+			},
+		},
+		{
+			name:   "MissingReason",
+			userID: user.ID,
+			body:   gin.H{},
+			access: supportAccess,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().LockUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildIdentity: func(userManager *mockidentity.MockUserManagement) {
+				userManager.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Self",
+			userID: staff.ID,
+			body:   gin.H{"reason": reason},
+			access: supportAccess,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(staff.ID)).Times(1).Return(staff, nil)
+				store.EXPECT().LockUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildIdentity: func(userManager *mockidentity.MockUserManagement) {
+				userManager.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "MissingPermission",
+			userID: user.ID,
+			body:   gin.H{"reason": reason},
+			access: investorAccess,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().LockUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildIdentity: func(userManager *mockidentity.MockUserManagement) {
+				userManager.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "RevokeTokensError",
+			userID: user.ID,
+			body:   gin.H{"reason": reason},
+			access: supportAccess,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().LockUser(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+				cache.EXPECT().RevokeUserTokens(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(sql.ErrConnDone)
+				store.EXPECT().CreateAuditEvent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildIdentity: func(userManager *mockidentity.MockUserManagement) {
+				expectAuth0Block(userManager, user.ID, true, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:   "Auth0Error",
+			userID: user.ID,
+			body:   gin.H{"reason": reason},
+			access: supportAccess,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().LockUser(gomock.Any(), gomock.Any()).Times(0)
+				cache.EXPECT().RevokeUserTokens(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildIdentity: func(userManager *mockidentity.MockUserManagement) {
+				expectAuth0Block(userManager, user.ID, true, errors.New("auth0 is unavailable"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadGateway, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			tc.buildIdentity(userManager)
+
+			url := fmt.Sprintf("/admin/users/%s/lock", tc.userID)
+			recorder := serveAdminRequest(t, http.MethodPost, url, tc.body, staff.ID, tc.access, tc.buildStubs, func(server *Server) {
+				server.UserManager = userManager
+			})
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestUnlockUserAPI(t *testing.T) {
+	staff, _ := randomUser(t)
+	user, _ := randomUser(t)
+	user.LockedAt = time.Now().UTC()
+	user.LockedReason = "fraud suspicion"
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userManager := mockidentity.NewMockUserManagement(ctrl)
+	expectAuth0Block(userManager, user.ID, false, nil)
+
+	recorder := serveAdminRequest(t, http.MethodPost, fmt.Sprintf("/admin/users/%s/unlock", user.ID), nil, staff.ID, supportAccess,
+		func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+			unlocked := user
+			unlocked.LockedAt = time.Time{}
+			unlocked.LockedReason = ""
+
+			store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+			store.EXPECT().UnlockUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(unlocked, nil)
+			expectAdminAudit(store, user.ID, staff.ID, db.AuditUserUnlocked)
+		}, func(server *Server) {
+			server.UserManager = userManager
+		})
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp adminUserResponse
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&rsp))
+	require.True(t, rsp.LockedAt.IsZero())
+	require.Empty(t, rsp.LockedReason)
+}
+
+func TestSignOutUserAPI(t *testing.T) {
+	staff, _ := randomUser(t)
+	user, _ := randomUser(t)
+
+	recorder := serveAdminRequest(t, http.MethodPost, fmt.Sprintf("/admin/users/%s/logout", user.ID), nil, staff.ID, supportAccess,
+		func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+			store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+			cache.EXPECT().RevokeUserTokens(gomock.Any(), gomock.Eq(user.ID.String()), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			expectAdminAudit(store, user.ID, staff.ID, db.AuditUserSignedOut)
+		})
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestLoginLockedUserAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	password := util.RandomString(10)
+	hashedPassword, err := util.HashPassword(password)
+	require.NoError(t, err)
+	user.HashedPassword = hashedPassword
+	user.LockedAt = time.Now().UTC()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	cache := mockcache.NewMockCache(ctrl)
+	userManager := mockidentity.NewMockUserManagement(ctrl)
+	cache.EXPECT().IsRateLimited(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+	store.EXPECT().GetMFASecret(gomock.Any(), gomock.Any()).Times(0)
+	cache.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store, cache, userManager)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{"email": user.Email, "password": password})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+	require.NoError(t, err)
+	request.RemoteAddr = "127.0.0.1:12345"
+
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+	require.Contains(t, recorder.Body.String(), errUserLocked.Error())
+}
@@ -22,20 +22,20 @@ func auth(tokenMaker token.Maker) gin.HandlerFunc {
 
 		if len(authorizationHeader) == 0 {
 			err := errors.New("authorization header is not provided")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			abortWithError(ctx, http.StatusUnauthorized, err)
 			return
 		}
 
 		accessToken := ""
 		_, err := fmt.Sscanf(authorizationHeader, "Bearer %s", &accessToken)
 		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(errors.New("invalid Token")))
+			abortWithError(ctx, http.StatusUnauthorized, errors.New("invalid Token"))
 			return
 		}
 
-		payload, err := tokenMaker.VerifyToken(accessToken)
+		payload, err := tokenMaker.VerifyTokenFor(accessToken, token.TypeAccess)
 		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			abortWithError(ctx, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -50,13 +50,29 @@ func (server *Server) revoked(ctx *gin.Context) {
 
 	b, err := server.Cache.IsRevoked(ctx, *payload)
 	if err != nil {
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+		abortWithError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 	if b {
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(errors.New("unauthorized")))
+		abortWithError(ctx, http.StatusUnauthorized, errors.New("unauthorized"))
 		return
 	}
 	ctx.Set(authorizationPayloadKey, payload)
 	ctx.Next()
 }
+
+// requirePermission creates a gin middleware which aborts the request unless the authenticated user
+// has been granted every permission, through any of their roles.
+func requirePermission(permissions ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		for _, permission := range permissions {
+			if !payload.HasPermission(permission) {
+				err := fmt.Errorf("permission %s required", permission)
+				abortWithError(ctx, http.StatusForbidden, err)
+				return
+			}
+		}
+		ctx.Next()
+	}
+}
@@ -7,22 +7,48 @@ import (
 	"testing"
 	"time"
 
+	db "github.com/awakim/immoblock-backend/db/sqlc"
 	"github.com/awakim/immoblock-backend/token"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
+// Access of the tokens of the tests, as granted by the roles of the initial migration
+var (
+	investorAccess = token.Access{
+		Roles: []string{db.RoleInvestor},
+	}
+	propertyManagerAccess = token.Access{
+		Roles:       []string{db.RoleInvestor, db.RolePropertyManager},
+		Permissions: []string{db.PermissionDistributionsWrite, db.PermissionPropertiesWrite},
+	}
+	adminAccess = token.Access{
+		Roles: []string{db.RoleAdmin, db.RoleInvestor},
+		Permissions: []string{
+			db.PermissionAdminAccess,
+			db.PermissionAuditRead,
+			db.PermissionDistributionsWrite,
+			db.PermissionKYCDocumentsRead,
+			db.PermissionKYCReview,
+			db.PermissionPropertiesWrite,
+			db.PermissionRolesWrite,
+			db.PermissionUsersRead,
+			db.PermissionUsersWrite,
+		},
+	}
+)
+
 func addAuthorization(
 	t *testing.T,
 	request *http.Request,
 	tokenMaker token.Maker,
 	authorizationType string,
 	userID uuid.UUID,
-	isAdmin bool,
+	access token.Access,
 	duration time.Duration,
 ) {
-	_, ss, err := tokenMaker.CreateToken(userID, isAdmin, duration)
+	_, ss, err := tokenMaker.CreateToken(token.TypeAccess, userID, access, duration)
 	require.NoError(t, err)
 
 	authorizationHeader := fmt.Sprintf("%s %s", authorizationType, ss)
@@ -40,7 +66,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "OK",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, userID, false, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, userID, investorAccess, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -57,7 +83,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "UnsupportedAuthorization",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "unsupported", userID, false, time.Minute)
+				addAuthorization(t, request, tokenMaker, "unsupported", userID, investorAccess, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -66,7 +92,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "InvalidAuthorizationFormat",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "", userID, false, time.Minute)
+				addAuthorization(t, request, tokenMaker, "", userID, investorAccess, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -75,7 +101,18 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "ExpiredToken",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, userID, false, -time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, userID, investorAccess, -time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "RefreshToken",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				_, refreshToken, err := tokenMaker.CreateToken(token.TypeRefresh, userID, token.Access{}, time.Minute)
+				require.NoError(t, err)
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, refreshToken))
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -9,7 +9,8 @@ func CORS(corsOrigins []string) gin.HandlerFunc {
 	return cors.New(cors.Config{
 		AllowOrigins:     corsOrigins,
 		AllowCredentials: true,
-		AllowHeaders:     []string{"Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "Accept", "Origin", "Cache-Control", "X-Requested-With"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE"},
+		AllowHeaders:     []string{"Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "Accept", "Origin", "Cache-Control", "X-Requested-With", "Idempotency-Key", "X-MFA-Code", "X-Request-ID"},
+		ExposeHeaders:    []string{"X-Request-ID", "Idempotent-Replayed"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
 	})
 }
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+const distributionPeriodLayout = "2006-01-02"
+
+type createDistributionRequest struct {
+	Amount      int64     `json:"amount" binding:"required,gt=0"`
+	PeriodStart string    `json:"period_start" binding:"required,datetime=2006-01-02"`
+	PeriodEnd   string    `json:"period_end" binding:"required,datetime=2006-01-02"`
+	SnapshotAt  time.Time `json:"snapshot_at"`
+}
+
+func (server *Server) createDistribution(ctx *gin.Context) {
+	var uri getPropertyRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var req createDistributionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	// the layout was already checked by the validator
+	periodStart, _ := time.Parse(distributionPeriodLayout, req.PeriodStart)
+	periodEnd, _ := time.Parse(distributionPeriodLayout, req.PeriodEnd)
+	if periodEnd.Before(periodStart) {
+		err := errors.New("period_end must not be before period_start")
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	now := time.Now()
+	snapshotAt := req.SnapshotAt
+	if snapshotAt.IsZero() {
+		snapshotAt = now
+	}
+	if snapshotAt.After(now) {
+		err := errors.New("snapshot_at must not be in the future")
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	arg := db.DistributionTxParams{
+		PropertyID:  uri.ID,
+		Amount:      req.Amount,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		SnapshotAt:  snapshotAt,
+	}
+
+	result, err := server.Store.DistributionTx(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				respondError(ctx, http.StatusForbidden, err)
+				return
+			}
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type listDistributionsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listDistributions(ctx *gin.Context) {
+	var uri getPropertyRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var req listDistributionsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	arg := db.ListDistributionsParams{
+		PropertyID: uri.ID,
+		Limit:      req.PageSize,
+		Offset:     (req.PageID - 1) * req.PageSize,
+	}
+
+	distributions, err := server.Store.ListDistributions(ctx, arg)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, distributions)
+}
+
+type listPayoutsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listPayouts(ctx *gin.Context) {
+	var req listPayoutsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.ListPayoutsParams{
+		UserID: authPayload.UserID,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	}
+
+	payouts, err := server.Store.ListPayouts(ctx, arg)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, payouts)
+}
@@ -0,0 +1,241 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockcache "github.com/awakim/immoblock-backend/cache/mock"
+	mockdb "github.com/awakim/immoblock-backend/db/mock"
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	mockidentity "github.com/awakim/immoblock-backend/identity/mock"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDistributionAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	user, _ := randomUser(t)
+	property := randomProperty(t)
+	amount := int64(100000)
+
+	periodStart := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := time.Date(2021, time.March, 31, 0, 0, 0, 0, time.UTC)
+	snapshotAt := time.Date(2021, time.March, 31, 23, 59, 59, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore, cache *mockcache.MockCache)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"amount":       amount,
+				"period_start": "2021-01-01",
+				"period_end":   "2021-03-31",
+				"snapshot_at":  snapshotAt,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.ID, propertyManagerAccess, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				arg := db.DistributionTxParams{
+					PropertyID:  property.ID,
+					Amount:      amount,
+					PeriodStart: periodStart,
+					PeriodEnd:   periodEnd,
+					SnapshotAt:  snapshotAt,
+				}
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().DistributionTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.DistributionTxResult{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "MissingPermission",
+			body: gin.H{
+				"amount":       amount,
+				"period_start": "2021-01-01",
+				"period_end":   "2021-03-31",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().DistributionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidPeriod",
+			body: gin.H{
+				"amount":       amount,
+				"period_start": "2021-03-31",
+				"period_end":   "2021-01-01",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.ID, propertyManagerAccess, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().DistributionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidDate",
+			body: gin.H{
+				"amount":       amount,
+				"period_start": "01/01/2021",
+				"period_end":   "2021-03-31",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.ID, propertyManagerAccess, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().DistributionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "SnapshotInFuture",
+			body: gin.H{
+				"amount":       amount,
+				"period_start": "2021-01-01",
+				"period_end":   "2021-03-31",
+				"snapshot_at":  time.Now().Add(time.Hour),
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.ID, propertyManagerAccess, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().DistributionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "PropertyNotFound",
+			body: gin.H{
+				"amount":       amount,
+				"period_start": "2021-01-01",
+				"period_end":   "2021-03-31",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.ID, propertyManagerAccess, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().DistributionTx(gomock.Any(), gomock.Any()).Times(1).Return(db.DistributionTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyDistributed",
+			body: gin.H{
+				"amount":       amount,
+				"period_start": "2021-01-01",
+				"period_end":   "2021-03-31",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.ID, propertyManagerAccess, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().DistributionTx(gomock.Any(), gomock.Any()).Times(1).Return(db.DistributionTxResult{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			tc.buildStubs(store, cache)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/properties/%d/distributions", property.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.TokenMaker)
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListPayoutsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	payouts := []db.ListPayoutsRow{
+		{ID: 1, DistributionID: 1, PropertyID: 1, AccountID: 1, Blocks: 10, Amount: 500, Currency: "EUR"},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	cache := mockcache.NewMockCache(ctrl)
+	userManager := mockidentity.NewMockUserManagement(ctrl)
+
+	arg := db.ListPayoutsParams{
+		UserID: user.ID,
+		Limit:  5,
+		Offset: 5,
+	}
+	cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+	store.EXPECT().ListPayouts(gomock.Any(), gomock.Eq(arg)).Times(1).Return(payouts, nil)
+
+	server := newTestServer(t, store, cache, userManager)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/users/payouts?page_id=2&page_size=5", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var gotPayouts []db.ListPayoutsRow
+	err = json.NewDecoder(recorder.Body).Decode(&gotPayouts)
+	require.NoError(t, err)
+	require.Equal(t, payouts, gotPayouts)
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	identity "github.com/awakim/immoblock-backend/identity/auth0"
+	mail "github.com/awakim/immoblock-backend/mail/local"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errEmailNotVerified = errors.New("email address is not verified")
+	errTokenAlreadyUsed = errors.New("token has already been used")
+)
+
+// sendVerificationEmail mails a single-use email verification link to the user.
+func (server *Server) sendVerificationEmail(ctx context.Context, user db.User) error {
+	_, verificationToken, err := server.EmailTokenMaker.CreateToken(token.TypeEmailVerification, user.ID, token.Access{}, server.Config.EmailVerificationTokenDuration)
+	if err != nil {
+		return err
+	}
+
+	msg := mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nPlease verify your email address by following this link:\n%s?token=%s\n",
+			user.Nickname,
+			server.Config.EmailVerificationURL,
+			verificationToken,
+		),
+	}
+	return server.Mailer.Send(ctx, msg)
+}
+
+type verifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+func (server *Server) verifyEmail(ctx *gin.Context) {
+	var req verifyEmailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	payload, err := server.EmailTokenMaker.VerifyTokenFor(req.Token, token.TypeEmailVerification)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	fresh, err := server.Cache.ConsumeToken(ctx, *payload)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if !fresh {
+		respondError(ctx, http.StatusUnauthorized, errTokenAlreadyUsed)
+		return
+	}
+
+	// the token is released when the email cannot be verified, so that the link can be followed again
+	err = identity.SetEmailVerified(server.UserManager, payload.UserID)
+	if err != nil {
+		_ = server.Cache.ReleaseToken(ctx, *payload)
+		respondError(ctx, http.StatusBadGateway, identityError(err))
+		return
+	}
+
+	user, err := server.Store.VerifyUserEmail(ctx, payload.UserID)
+	if err != nil {
+		_ = server.Cache.ReleaseToken(ctx, *payload)
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+type resendVerificationEmailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// resendVerificationEmail sends a new verification link. The response is the same whether
+// the email is unknown, already verified or not, so that it cannot be used to list users.
+func (server *Server) resendVerificationEmail(ctx *gin.Context) {
+	var req resendVerificationEmailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	rsp := gin.H{
+		"message": "a verification email has been sent if the address needs to be verified",
+	}
+
+	user, err := server.Store.GetUser(ctx, req.Email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusOK, rsp)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	if user.EmailVerifiedAt.IsZero() {
+		if err := server.sendVerificationEmail(ctx, user); err != nil {
+			respondError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// requireVerifiedEmail aborts the request unless the authenticated user has verified their email address.
+func (server *Server) requireVerifiedEmail(ctx *gin.Context) {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	user, err := server.Store.GetUserByID(ctx, payload.UserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			abortWithError(ctx, http.StatusUnauthorized, errors.New("unauthorized"))
+			return
+		}
+		abortWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	if user.EmailVerifiedAt.IsZero() {
+		abortWithError(ctx, http.StatusForbidden, errEmailNotVerified)
+		return
+	}
+
+	ctx.Next()
+}
@@ -0,0 +1,295 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/auth0/go-auth0/management"
+	mockcache "github.com/awakim/immoblock-backend/cache/mock"
+	mockdb "github.com/awakim/immoblock-backend/db/mock"
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	identity "github.com/awakim/immoblock-backend/identity/auth0"
+	mockidentity "github.com/awakim/immoblock-backend/identity/mock"
+	mail "github.com/awakim/immoblock-backend/mail/local"
+	mockmail "github.com/awakim/immoblock-backend/mail/mock"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyEmailAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	emailVerified := true
+
+	testCases := []struct {
+		name          string
+		token         func(server *Server) string
+		buildStubs    func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			token: func(server *Server) string {
+				_, verificationToken, err := server.EmailTokenMaker.CreateToken(token.TypeEmailVerification, user.ID, token.Access{}, time.Minute)
+				require.NoError(t, err)
+				return verificationToken
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				cache.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(1).Return(true, nil)
+				store.EXPECT().VerifyUserEmail(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				userManager.EXPECT().Update(gomock.Eq(identity.UserID(user.ID)), gomock.Eq(&management.User{EmailVerified: &emailVerified})).Times(1).Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp userResponse
+				err := json.NewDecoder(recorder.Body).Decode(&rsp)
+				require.NoError(t, err)
+				require.Equal(t, user.ID, rsp.UserID)
+				require.False(t, rsp.EmailVerifiedAt.IsZero())
+			},
+		},
+		{
+			name: "AlreadyUsed",
+			token: func(server *Server) string {
+				_, verificationToken, err := server.EmailTokenMaker.CreateToken(token.TypeEmailVerification, user.ID, token.Access{}, time.Minute)
+				require.NoError(t, err)
+				return verificationToken
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				cache.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().VerifyUserEmail(gomock.Any(), gomock.Any()).Times(0)
+				userManager.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "AccessTokenRejected",
+			token: func(server *Server) string {
+				_, accessToken, err := server.TokenMaker.CreateToken(token.TypeAccess, user.ID, token.Access{}, time.Minute)
+				require.NoError(t, err)
+				return accessToken
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				cache.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().VerifyUserEmail(gomock.Any(), gomock.Any()).Times(0)
+				userManager.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ExpiredToken",
+			token: func(server *Server) string {
+				_, verificationToken, err := server.EmailTokenMaker.CreateToken(token.TypeEmailVerification, user.ID, token.Access{}, -time.Minute)
+				require.NoError(t, err)
+				return verificationToken
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				cache.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().VerifyUserEmail(gomock.Any(), gomock.Any()).Times(0)
+				userManager.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "UserNotFound",
+			token: func(server *Server) string {
+				_, verificationToken, err := server.EmailTokenMaker.CreateToken(token.TypeEmailVerification, user.ID, token.Access{}, time.Minute)
+				require.NoError(t, err)
+				return verificationToken
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				cache.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(1).Return(true, nil)
+				store.EXPECT().VerifyUserEmail(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, sql.ErrNoRows)
+				userManager.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+				cache.EXPECT().ReleaseToken(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Auth0Error",
+			token: func(server *Server) string {
+				_, verificationToken, err := server.EmailTokenMaker.CreateToken(token.TypeEmailVerification, user.ID, token.Access{}, time.Minute)
+				require.NoError(t, err)
+				return verificationToken
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				cache.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(1).Return(true, nil)
+				userManager.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).Return(errors.New("auth0 is unavailable"))
+				store.EXPECT().VerifyUserEmail(gomock.Any(), gomock.Any()).Times(0)
+				cache.EXPECT().ReleaseToken(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadGateway, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			tc.buildStubs(store, cache, userManager)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"token": tc.token(server)})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/verify-email", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestResendVerificationEmailAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	user.EmailVerifiedAt = time.Time{}
+	verified, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		email         string
+		buildStubs    func(store *mockdb.MockStore, mailer *mockmail.MockMailer)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			email: user.Email,
+			buildStubs: func(store *mockdb.MockStore, mailer *mockmail.MockMailer) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				mailer.EXPECT().
+					Send(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, msg mail.Message) error {
+						require.Equal(t, user.Email, msg.To)
+						require.True(t, strings.Contains(msg.Body, "http://localhost:4200/verify-email?token="))
+						return nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "AlreadyVerified",
+			email: verified.Email,
+			buildStubs: func(store *mockdb.MockStore, mailer *mockmail.MockMailer) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(verified.Email)).Times(1).Return(verified, nil)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "UnknownEmail",
+			email: "unknown@email.com",
+			buildStubs: func(store *mockdb.MockStore, mailer *mockmail.MockMailer) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, sql.ErrNoRows)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidEmail",
+			email: "invalid",
+			buildStubs: func(store *mockdb.MockStore, mailer *mockmail.MockMailer) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			mailer := mockmail.NewMockMailer(ctrl)
+			cache.EXPECT().IsRateLimited(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			tc.buildStubs(store, mailer)
+
+			server := newTestServer(t, store, cache, userManager)
+			server.Mailer = mailer
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"email": tc.email})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/verify-email/resend", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.RemoteAddr = "127.0.0.1:12345"
+
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestLoginUserEmailNotVerifiedAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	password := "secret-password"
+	hashedPassword, err := util.HashPassword(password)
+	require.NoError(t, err)
+	user.HashedPassword = hashedPassword
+	user.EmailVerifiedAt = time.Time{}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	cache := mockcache.NewMockCache(ctrl)
+	userManager := mockidentity.NewMockUserManagement(ctrl)
+	cache.EXPECT().IsRateLimited(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+	cache.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store, cache, userManager)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{"email": user.Email, "password": password})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+	require.NoError(t, err)
+	request.RemoteAddr = "127.0.0.1:12345"
+
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+}
@@ -1,25 +1,373 @@
 package api
 
 import (
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/auth0/go-auth0/management"
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	"github.com/awakim/immoblock-backend/token"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
 	"github.com/go-playground/validator/v10"
+	"github.com/lib/pq"
 )
 
-func errorResponse(err error) gin.H {
-	return gin.H{"error": err.Error()}
+// problemContentType is the media type of the error responses, see RFC 7807.
+const problemContentType = "application/problem+json"
+
+// Codes of the API errors. They are stable, clients can rely on them rather than on the messages.
+const (
+	CodeInvalidRequest       = "invalid_request"
+	CodeValidationFailed     = "validation_failed"
+	CodeUnauthorized         = "unauthorized"
+	CodeInvalidToken         = "invalid_token"
+	CodeTokenExpired         = "token_expired"
+	CodeForbidden            = "forbidden"
+	CodeNotFound             = "not_found"
+	CodeConflict             = "conflict"
+	CodeAlreadyExists        = "already_exists"
+	CodeInvalidReference     = "invalid_reference"
+	CodePayloadTooLarge      = "payload_too_large"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeUnprocessable        = "unprocessable_entity"
+	CodeTooManyRequests      = "too_many_requests"
+	CodeInternal             = "internal_error"
+	CodeUpstreamFailed       = "upstream_failed"
+	CodeIdentityRejected     = "identity_rejected"
+)
+
+// statusCodes are the codes of the errors which are not mapped to a more specific one, by HTTP status.
+var statusCodes = map[int]string{
+	http.StatusBadRequest:            CodeInvalidRequest,
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusConflict:              CodeConflict,
+	http.StatusRequestEntityTooLarge: CodePayloadTooLarge,
+	http.StatusUnsupportedMediaType:  CodeUnsupportedMediaType,
+	http.StatusUnprocessableEntity:   CodeUnprocessable,
+	http.StatusTooManyRequests:       CodeTooManyRequests,
+	http.StatusInternalServerError:   CodeInternal,
+	http.StatusBadGateway:            CodeUpstreamFailed,
+}
+
+// storeErrorCodes are the codes of the business rules enforced by the store.
+var storeErrorCodes = map[error]string{
+	db.ErrPropertyNotForSale:            "property_not_for_sale",
+	db.ErrInsufficientBlocks:            "insufficient_blocks",
+	db.ErrInsufficientBalance:           "insufficient_balance",
+	db.ErrInsufficientFunds:             "insufficient_funds",
+	db.ErrInvalidVerificationTransition: "invalid_verification_transition",
+}
+
+// APIError is an error answered to a client. Err is the cause, it is logged but never sent.
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+	Details map[string]string
+	Err     error
+}
+
+func (e *APIError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// identityError wraps an error of Auth0. The requests it rejects are answered as such, with the
+// message of Auth0, the other errors are failures of an upstream service.
+func identityError(err error) *APIError {
+	apiErr := &APIError{
+		Status:  http.StatusBadGateway,
+		Code:    CodeUpstreamFailed,
+		Message: "the identity provider failed, please try again later",
+		Err:     err,
+	}
+
+	var mgmtErr management.Error
+	if errors.As(err, &mgmtErr) {
+		switch mgmtErr.Status() {
+		case http.StatusBadRequest:
+			apiErr.Status = http.StatusBadRequest
+			apiErr.Code = CodeIdentityRejected
+			apiErr.Message = mgmtErr.Error()
+		case http.StatusConflict:
+			apiErr.Status = http.StatusConflict
+			apiErr.Code = CodeAlreadyExists
+			apiErr.Message = "the user already exists"
+		}
+	}
+	return apiErr
+}
+
+// problem is the body of an error response, the problem details of RFC 7807 extended with the
+// code of the error, the invalid fields and the ID of the request.
+type problem struct {
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Status    int               `json:"status"`
+	Detail    string            `json:"detail"`
+	Instance  string            `json:"instance"`
+	Code      string            `json:"code"`
+	Errors    map[string]string `json:"errors,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+}
+
+// newAPIError maps an error to the error answered with the status given. Server errors only expose a
+// generic message, the errors of the database, the tokens and the validation get a specific code and
+// a message which does not leak their internals.
+func newAPIError(ctx *gin.Context, status int, err error) *APIError {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	apiErr = &APIError{
+		Status:  status,
+		Code:    statusCodes[status],
+		Message: err.Error(),
+		Err:     err,
+	}
+	if apiErr.Code == "" {
+		apiErr.Code = strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+	}
+
+	if status >= http.StatusInternalServerError {
+		switch status {
+		case http.StatusBadGateway:
+			apiErr.Message = "an upstream service failed, please try again later"
+		default:
+			apiErr.Message = "an internal error occurred"
+		}
+		return apiErr
+	}
+
+	for storeErr, code := range storeErrorCodes {
+		if errors.Is(err, storeErr) {
+			apiErr.Code = code
+			return apiErr
+		}
+	}
+
+	var verr validator.ValidationErrors
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var numErr *strconv.NumError
+	var pqErr *pq.Error
+	switch {
+	case errors.As(err, &verr):
+		apiErr.Code = CodeValidationFailed
+		apiErr.Message = "some fields of the request are invalid"
+		apiErr.Details = ValidationError(ctx, verr)
+	case errors.As(err, &typeErr):
+		apiErr.Message = fmt.Sprintf("%s must be a %s", typeErr.Field, typeErr.Type)
+		apiErr.Details = map[string]string{typeErr.Field: "must be a " + typeErr.Type.String()}
+	case errors.As(err, &syntaxErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		apiErr.Message = "the request body is not valid JSON"
+	case errors.As(err, &numErr):
+		apiErr.Message = fmt.Sprintf("%q is not a valid number", numErr.Num)
+	case errors.Is(err, sql.ErrNoRows):
+		apiErr.Code = CodeNotFound
+		apiErr.Message = "resource not found"
+	case errors.Is(err, token.ErrExpiredToken):
+		apiErr.Code = CodeTokenExpired
+	case errors.Is(err, token.ErrInvalidToken), errors.Is(err, token.ErrInvalidTokenType):
+		apiErr.Code = CodeInvalidToken
+	case errors.As(err, &pqErr):
+		switch pqErr.Code.Name() {
+		case "unique_violation":
+			apiErr.Code = CodeAlreadyExists
+			apiErr.Message = "the resource already exists"
+		case "foreign_key_violation":
+			apiErr.Code = CodeInvalidReference
+			apiErr.Message = "a resource referenced by the request does not exist"
+		default:
+			apiErr.Message = "the request conflicts with the stored data"
+		}
+	}
+	return apiErr
+}
+
+// respondError answers the request with the problem details of the error.
+func respondError(ctx *gin.Context, status int, err error) {
+	apiErr := newAPIError(ctx, status, err)
+	if apiErr.Status >= http.StatusInternalServerError && apiErr.Err != nil {
+		// the cause is hidden from the client, it shows in the request log
+		_ = ctx.Error(apiErr.Err)
+	}
+
+	ctx.Header("Content-Type", problemContentType)
+	ctx.Render(apiErr.Status, render.JSON{Data: problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(apiErr.Status),
+		Status:    apiErr.Status,
+		Detail:    apiErr.Message,
+		Instance:  ctx.Request.URL.Path,
+		Code:      apiErr.Code,
+		Errors:    apiErr.Details,
+		RequestID: ctx.GetString(requestIDKey),
+	}})
+}
+
+// abortWithError answers the request with the problem details of the error and stops the handlers chain.
+func abortWithError(ctx *gin.Context, status int, err error) {
+	ctx.Abort()
+	respondError(ctx, status, err)
+}
+
+// Languages of the validation error messages, English by default
+const (
+	languageEnglish = "en"
+	languageFrench  = "fr"
+)
+
+// validationMessages are the validation error messages by language and tag. The tags whose
+// message depends on the kind of the field have a "_string" variant for strings. A message
+// with a verb is formatted with the parameter of the tag.
+var validationMessages = map[string]map[string]string{
+	languageEnglish: {
+		"required":    "is required",
+		"min":         "must be at least %s",
+		"min_string":  "must be at least %s characters long",
+		"max":         "must be at most %s",
+		"max_string":  "must be at most %s characters long",
+		"len":         "must be %s",
+		"len_string":  "must be %s characters long",
+		"gt":          "must be greater than %s",
+		"gte":         "must be greater than or equal to %s",
+		"lt":          "must be less than %s",
+		"lte":         "must be less than or equal to %s",
+		"oneof":       "must be one of: %s",
+		"nefield":     "must be different from %s",
+		"email":       "must be a valid email address",
+		"e164":        "must be a phone number in international format, such as +33612345678",
+		"uuid":        "must be a valid UUID",
+		"datetime":    "must be a date formatted as %s",
+		"alpha":       "must only contain letters",
+		"alphanum":    "must only contain letters and digits",
+		"ascii":       "must only contain ASCII characters",
+		"numeric":     "must be a number",
+		"uppercase":   "must be in upper case",
+		"personname":  "must be a name made of letters, spaces, hyphens and apostrophes",
+		"placename":   "must be a place name made of letters, digits, spaces, hyphens, apostrophes and dots",
+		"country":     "must be an ISO 3166 country code, such as FR",
+		"nationality": "must be the ISO 3166 code of the country of nationality, such as FR",
+		"postcode":    "is not a valid postal code for the country",
+		"default":     "is invalid",
+	},
+	languageFrench: {
+		"required":    "est obligatoire",
+		"min":         "doit être au moins %s",
+		"min_string":  "doit contenir au moins %s caractères",
+		"max":         "doit être au plus %s",
+		"max_string":  "doit contenir au plus %s caractères",
+		"len":         "doit être %s",
+		"len_string":  "doit contenir %s caractères",
+		"gt":          "doit être supérieur à %s",
+		"gte":         "doit être supérieur ou égal à %s",
+		"lt":          "doit être inférieur à %s",
+		"lte":         "doit être inférieur ou égal à %s",
+		"oneof":       "doit être l'une des valeurs : %s",
+		"nefield":     "doit être différent de %s",
+		"email":       "doit être une adresse email valide",
+		"e164":        "doit être un numéro de téléphone au format international, par exemple +33612345678",
+		"uuid":        "doit être un UUID valide",
+		"datetime":    "doit être une date au format %s",
+		"alpha":       "ne doit contenir que des lettres",
+		"alphanum":    "ne doit contenir que des lettres et des chiffres",
+		"ascii":       "ne doit contenir que des caractères ASCII",
+		"numeric":     "doit être un nombre",
+		"uppercase":   "doit être en majuscules",
+		"personname":  "doit être un nom composé de lettres, d'espaces, de traits d'union et d'apostrophes",
+		"placename":   "doit être un nom de lieu composé de lettres, de chiffres, d'espaces, de traits d'union, d'apostrophes et de points",
+		"country":     "doit être un code pays ISO 3166, par exemple FR",
+		"nationality": "doit être le code ISO 3166 du pays de nationalité, par exemple FR",
+		"postcode":    "n'est pas un code postal valide pour le pays",
+		"default":     "est invalide",
+	},
+}
+
+// requestLanguage returns the language of the validation error messages preferred by the client
+// in its Accept-Language header, English when none is supported.
+func requestLanguage(ctx *gin.Context) string {
+	type preference struct {
+		language string
+		quality  float64
+	}
+
+	var preferences []preference
+	for _, part := range strings.Split(ctx.GetHeader("Accept-Language"), ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		language := strings.ToLower(strings.SplitN(fields[0], "-", 2)[0])
+		quality := 1.0
+		for _, param := range fields[1:] {
+			if q := strings.TrimPrefix(strings.TrimSpace(param), "q="); q != param {
+				if v, err := strconv.ParseFloat(q, 64); err == nil {
+					quality = v
+				}
+			}
+		}
+		preferences = append(preferences, preference{language, quality})
+	}
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].quality > preferences[j].quality
+	})
+
+	for _, p := range preferences {
+		if _, ok := validationMessages[p.language]; ok && p.quality > 0 {
+			return p.language
+		}
+	}
+	return languageEnglish
+}
+
+// validationMessage returns the message of a validation error in the language given.
+func validationMessage(language string, f validator.FieldError) string {
+	messages := validationMessages[language]
+
+	// aliases such as country report the tag they stand for as their actual tag
+	tag := f.Tag()
+	if _, ok := messages[tag]; !ok {
+		tag = f.ActualTag()
+	}
+	if f.Kind() == reflect.String {
+		if _, ok := messages[tag+"_string"]; ok {
+			tag += "_string"
+		}
+	}
+
+	message, ok := messages[tag]
+	if !ok {
+		message = messages["default"]
+	}
+	if strings.Contains(message, "%s") {
+		return fmt.Sprintf(message, f.Param())
+	}
+	return message
 }
 
-func ValidationError(vErrs validator.ValidationErrors) map[string]string {
+// ValidationError returns the validation error messages by field, in the language preferred by the client.
+func ValidationError(ctx *gin.Context, vErrs validator.ValidationErrors) map[string]string {
+	language := requestLanguage(ctx)
 	errs := make(map[string]string)
 
 	for _, f := range vErrs {
-		err := f.ActualTag()
-		if f.Param() != "" {
-			err = fmt.Sprintf("%s=%s", err, f.Param())
-		}
-		errs[f.Field()] = err
+		errs[f.Field()] = validationMessage(language, f)
 	}
 
 	return errs
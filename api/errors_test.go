@@ -0,0 +1,180 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorResponse(t *testing.T) {
+	type request struct {
+		Amount int64 `validate:"required,gt=0"`
+	}
+
+	testCases := []struct {
+		name    string
+		status  int
+		err     func(ctx *gin.Context) error
+		problem problem
+	}{
+		{
+			name:   "Message",
+			status: http.StatusForbidden,
+			err: func(ctx *gin.Context) error {
+				return errors.New("permission users:read required")
+			},
+			problem: problem{Status: http.StatusForbidden, Code: CodeForbidden, Detail: "permission users:read required"},
+		},
+		{
+			name:   "Validation",
+			status: http.StatusBadRequest,
+			err: func(ctx *gin.Context) error {
+				return validator.New().Struct(request{})
+			},
+			problem: problem{
+				Status: http.StatusBadRequest,
+				Code:   CodeValidationFailed,
+				Detail: "some fields of the request are invalid",
+				Errors: map[string]string{"Amount": "is required"},
+			},
+		},
+		{
+			name:   "Typed",
+			status: http.StatusConflict,
+			err: func(ctx *gin.Context) error {
+				return &APIError{Status: http.StatusConflict, Code: "insufficient_funds", Message: "not enough funds"}
+			},
+			problem: problem{Status: http.StatusConflict, Code: "insufficient_funds", Detail: "not enough funds"},
+		},
+		{
+			name:   "StoreRule",
+			status: http.StatusConflict,
+			err: func(ctx *gin.Context) error {
+				return fmt.Errorf("withdraw: %w", db.ErrInsufficientFunds)
+			},
+			problem: problem{Status: http.StatusConflict, Code: "insufficient_funds", Detail: "withdraw: wallet balance is insufficient"},
+		},
+		{
+			name:   "NoRows",
+			status: http.StatusNotFound,
+			err: func(ctx *gin.Context) error {
+				return sql.ErrNoRows
+			},
+			problem: problem{Status: http.StatusNotFound, Code: CodeNotFound, Detail: "resource not found"},
+		},
+		{
+			name:   "ExpiredToken",
+			status: http.StatusUnauthorized,
+			err: func(ctx *gin.Context) error {
+				return token.ErrExpiredToken
+			},
+			problem: problem{Status: http.StatusUnauthorized, Code: CodeTokenExpired, Detail: token.ErrExpiredToken.Error()},
+		},
+		{
+			name:   "InvalidToken",
+			status: http.StatusUnauthorized,
+			err: func(ctx *gin.Context) error {
+				return fmt.Errorf("cannot verify token: %w", token.ErrInvalidToken)
+			},
+			problem: problem{Status: http.StatusUnauthorized, Code: CodeInvalidToken, Detail: "cannot verify token: token is invalid"},
+		},
+		{
+			name:   "ForeignKeyViolation",
+			status: http.StatusForbidden,
+			err: func(ctx *gin.Context) error {
+				return &pq.Error{Code: "23503", Message: `insert or update on table "accounts" violates foreign key constraint "accounts_property_id_fkey"`}
+			},
+			problem: problem{Status: http.StatusForbidden, Code: CodeInvalidReference, Detail: "a resource referenced by the request does not exist"},
+		},
+		{
+			name:   "UniqueViolation",
+			status: http.StatusForbidden,
+			err: func(ctx *gin.Context) error {
+				return &pq.Error{Code: "23505", Message: `duplicate key value violates unique constraint "accounts_pkey"`}
+			},
+			problem: problem{Status: http.StatusForbidden, Code: CodeAlreadyExists, Detail: "the resource already exists"},
+		},
+		{
+			name:   "InternalError",
+			status: http.StatusInternalServerError,
+			err: func(ctx *gin.Context) error {
+				return errors.New("dial tcp 127.0.0.1:5432: connect: connection refused")
+			},
+			problem: problem{Status: http.StatusInternalServerError, Code: CodeInternal, Detail: "an internal error occurred"},
+		},
+		{
+			name:   "UpstreamError",
+			status: http.StatusBadGateway,
+			err: func(ctx *gin.Context) error {
+				return errors.New("payment provider: card declined by issuer 0x51")
+			},
+			problem: problem{Status: http.StatusBadGateway, Code: CodeUpstreamFailed, Detail: "an upstream service failed, please try again later"},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(requestID)
+			router.POST("/test", func(ctx *gin.Context) {
+				respondError(ctx, tc.status, tc.err(ctx))
+			})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/test", nil)
+			require.NoError(t, err)
+			request.Header.Set(requestIDHeader, "req-42")
+
+			router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.status, recorder.Code)
+			require.Equal(t, problemContentType, recorder.Header().Get("Content-Type"))
+			require.Equal(t, "req-42", recorder.Header().Get(requestIDHeader))
+
+			var rsp problem
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(&rsp))
+
+			expected := tc.problem
+			expected.Type = "about:blank"
+			expected.Title = http.StatusText(tc.status)
+			expected.Instance = "/test"
+			expected.RequestID = "req-42"
+			require.Equal(t, expected, rsp)
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	router := gin.New()
+	router.Use(requestID)
+	router.GET("/test", func(ctx *gin.Context) {
+		abortWithError(ctx, http.StatusNotFound, errors.New("not here"))
+	})
+
+	for _, header := range []string{"", "not a valid id", "<script>"} {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/test", nil)
+		require.NoError(t, err)
+		request.Header.Set(requestIDHeader, header)
+
+		router.ServeHTTP(recorder, request)
+
+		var rsp problem
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&rsp))
+		require.Len(t, rsp.RequestID, 36)
+		require.NotEqual(t, header, rsp.RequestID)
+		require.Equal(t, rsp.RequestID, recorder.Header().Get(requestIDHeader))
+	}
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	cache "github.com/awakim/immoblock-backend/cache/redis"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	defaultIdempotencyKeyTTL  = 24 * time.Hour
+	idempotencyResponseFormat = "application/json; charset=utf-8"
+	idempotencyRetrySafeKey   = "X-Idempotency-Retry-Safe"
+)
+
+// responseRecorder keeps a copy of the response body written by the handlers.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// idempotent makes a money-moving route safe to retry with an Idempotency-Key header.
+// The first response for a key is saved and replayed to later requests with the same payload,
+// a different payload for the same key is rejected. Server errors are saved as well since the
+// money may have moved before the failure, unless the handler marked the request as safe to
+// retry with markRetrySafe. Requests without the header are not affected.
+func (server *Server) idempotent(ctx *gin.Context) {
+	key := ctx.GetHeader(idempotencyKeyHeader)
+	if key == "" {
+		ctx.Next()
+		return
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		err := errors.New("idempotency key is too long")
+		abortWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	body, err := ioutil.ReadAll(ctx.Request.Body)
+	if err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	ctx.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	hash := sha256.New()
+	hash.Write([]byte(ctx.Request.Method + " " + ctx.Request.URL.Path + "\n"))
+	hash.Write(body)
+	fingerprint := hex.EncodeToString(hash.Sum(nil))
+
+	ttl := server.Config.IdempotencyKeyDuration
+	if ttl <= 0 {
+		ttl = defaultIdempotencyKeyTTL
+	}
+
+	userID := ctx.MustGet(authorizationPayloadKey).(*token.Payload).UserID.String()
+	record, acquired, err := server.Cache.LockIdempotencyKey(ctx, userID, key, fingerprint, ttl)
+	if err != nil {
+		abortWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	if !acquired {
+		switch {
+		case record.Fingerprint != fingerprint:
+			err := errors.New("idempotency key was already used with a different request")
+			abortWithError(ctx, http.StatusUnprocessableEntity, err)
+		case !record.Completed():
+			err := errors.New("a request with this idempotency key is still being processed")
+			abortWithError(ctx, http.StatusConflict, err)
+		default:
+			format := idempotencyResponseFormat
+			if record.Status >= http.StatusBadRequest {
+				format = problemContentType
+			}
+			ctx.Header(idempotentReplayedHeader, "true")
+			ctx.Data(record.Status, format, record.Body)
+			ctx.Abort()
+		}
+		return
+	}
+
+	recorder := &responseRecorder{ResponseWriter: ctx.Writer}
+	ctx.Writer = recorder
+	ctx.Next()
+
+	status := recorder.Status()
+	if status >= http.StatusInternalServerError && ctx.GetBool(idempotencyRetrySafeKey) {
+		_ = server.Cache.ReleaseIdempotencyKey(ctx, userID, key)
+		return
+	}
+
+	record = cache.IdempotencyRecord{
+		Fingerprint: fingerprint,
+		Status:      status,
+		Body:        recorder.body.Bytes(),
+	}
+	// the response is already written, if it cannot be saved the key stays locked
+	// until it expires rather than letting a retry move the money twice
+	_ = server.Cache.SaveIdempotentResponse(ctx, userID, key, record, ttl)
+}
+
+// markRetrySafe tells the idempotent middleware that the failed request left nothing behind,
+// so its key is released and the client can retry it instead of getting the error replayed.
+func markRetrySafe(ctx *gin.Context) {
+	ctx.Set(idempotencyRetrySafeKey, true)
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockcache "github.com/awakim/immoblock-backend/cache/mock"
+	cache "github.com/awakim/immoblock-backend/cache/redis"
+	mockdb "github.com/awakim/immoblock-backend/db/mock"
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	mockidentity "github.com/awakim/immoblock-backend/identity/mock"
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	user, _ := randomUser(t)
+	property := randomProperty(t)
+	key := util.RandomString(16)
+
+	result := db.PurchaseTxResult{
+		Purchase: db.Purchase{
+			ID:         1,
+			PropertyID: property.ID,
+			Amount:     10,
+		},
+	}
+	savedBody, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	lockWith := func(record cache.IdempotencyRecord, sameFingerprint bool) interface{} {
+		return func(_ context.Context, _ string, _ string, fingerprint string, _ time.Duration) (cache.IdempotencyRecord, bool, error) {
+			if sameFingerprint {
+				record.Fingerprint = fingerprint
+			}
+			return record, false, nil
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		key           string
+		buildStubs    func(store *mockdb.MockStore, cache *mockcache.MockCache)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "FirstRequest",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore, c *mockcache.MockCache) {
+				c.EXPECT().
+					LockIdempotencyKey(gomock.Any(), gomock.Eq(user.ID.String()), gomock.Eq(key), gomock.Any(), gomock.Eq(defaultIdempotencyKeyTTL)).
+					Times(1).
+					Return(cache.IdempotencyRecord{}, true, nil)
+				store.EXPECT().PurchaseTx(gomock.Any(), gomock.Any()).Times(1).Return(result, nil)
+				c.EXPECT().
+					SaveIdempotentResponse(gomock.Any(), gomock.Eq(user.ID.String()), gomock.Eq(key), gomock.Any(), gomock.Eq(defaultIdempotencyKeyTTL)).
+					Times(1).
+					DoAndReturn(func(_ context.Context, _ string, _ string, record cache.IdempotencyRecord, _ time.Duration) error {
+						require.NotEmpty(t, record.Fingerprint)
+						require.Equal(t, http.StatusOK, record.Status)
+						require.JSONEq(t, string(savedBody), string(record.Body))
+						return nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, recorder.Header().Get(idempotentReplayedHeader))
+			},
+		},
+		{
+			name: "Replay",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore, c *mockcache.MockCache) {
+				record := cache.IdempotencyRecord{Status: http.StatusOK, Body: savedBody}
+				c.EXPECT().LockIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(lockWith(record, true))
+				store.EXPECT().PurchaseTx(gomock.Any(), gomock.Any()).Times(0)
+				c.EXPECT().SaveIdempotentResponse(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "true", recorder.Header().Get(idempotentReplayedHeader))
+				require.JSONEq(t, string(savedBody), recorder.Body.String())
+			},
+		},
+		{
+			name: "DifferentPayload",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore, c *mockcache.MockCache) {
+				record := cache.IdempotencyRecord{Fingerprint: "other", Status: http.StatusOK, Body: savedBody}
+				c.EXPECT().LockIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(lockWith(record, false))
+				store.EXPECT().PurchaseTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "InProgress",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore, c *mockcache.MockCache) {
+				c.EXPECT().LockIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(lockWith(cache.IdempotencyRecord{}, true))
+				store.EXPECT().PurchaseTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "ServerErrorKeepsKey",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore, c *mockcache.MockCache) {
+				c.EXPECT().LockIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(cache.IdempotencyRecord{}, true, nil)
+				store.EXPECT().PurchaseTx(gomock.Any(), gomock.Any()).Times(1).Return(db.PurchaseTxResult{}, sql.ErrConnDone)
+				c.EXPECT().ReleaseIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				c.EXPECT().
+					SaveIdempotentResponse(gomock.Any(), gomock.Eq(user.ID.String()), gomock.Eq(key), gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, _ string, _ string, record cache.IdempotencyRecord, _ time.Duration) error {
+						require.Equal(t, http.StatusInternalServerError, record.Status)
+						return nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "NoKey",
+			key:  "",
+			buildStubs: func(store *mockdb.MockStore, c *mockcache.MockCache) {
+				c.EXPECT().LockIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().PurchaseTx(gomock.Any(), gomock.Any()).Times(1).Return(result, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			tc.buildStubs(store, cache)
+			expectApprovedIdentity(store, user.ID)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"amount": 10})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/properties/%d/purchases", property.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			if tc.key != "" {
+				request.Header.Set(idempotencyKeyHeader, tc.key)
+			}
+
+			addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestIdempotencyRetrySafe(t *testing.T) {
+	user, _ := randomUser(t)
+	key := util.RandomString(16)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mockcache.NewMockCache(ctrl)
+	c.EXPECT().LockIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(cache.IdempotencyRecord{}, true, nil)
+	c.EXPECT().ReleaseIdempotencyKey(gomock.Any(), gomock.Eq(user.ID.String()), gomock.Eq(key)).Times(1).Return(nil)
+	c.EXPECT().SaveIdempotentResponse(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, nil, c, nil)
+	retryPath := "/retry"
+	server.Router.POST(
+		retryPath,
+		auth(server.TokenMaker),
+		server.idempotent,
+		func(ctx *gin.Context) {
+			markRetrySafe(ctx)
+			respondError(ctx, http.StatusBadGateway, errors.New("provider unavailable"))
+		},
+	)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, retryPath, bytes.NewReader([]byte("{}")))
+	require.NoError(t, err)
+	request.Header.Set(idempotencyKeyHeader, key)
+
+	addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadGateway, recorder.Code)
+}
@@ -0,0 +1,259 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var (
+	errDocumentNotFound        = errors.New("identity document not found")
+	errDocumentTooLarge        = errors.New("identity document is too large")
+	errDocumentEmpty           = errors.New("identity document is empty")
+	errDocumentTypeUnsupported = errors.New("identity documents must be JPEG or PNG images or PDF files")
+	errDocumentsLocked         = errors.New("identity documents cannot be changed during or after the review")
+)
+
+// documentContentTypes are the types of identity documents accepted, as detected from their content
+var documentContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"application/pdf": true,
+}
+
+// multipartOverhead is the room left for the other fields and the boundaries of an upload
+const multipartOverhead = 64 << 10
+
+type uploadIdentityDocumentRequest struct {
+	Kind string                `form:"kind" binding:"required,oneof=id_card passport proof_of_address"`
+	File *multipart.FileHeader `form:"file" binding:"required"`
+}
+
+type identityDocumentResponse struct {
+	ID          int64     `json:"id"`
+	Kind        string    `json:"kind"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func newIdentityDocumentResponse(document db.IdentityDocument) identityDocumentResponse {
+	return identityDocumentResponse{
+		ID:          document.ID,
+		Kind:        document.Kind,
+		Filename:    document.Filename,
+		ContentType: document.ContentType,
+		Size:        document.Size,
+		CreatedAt:   document.CreatedAt,
+	}
+}
+
+func newIdentityDocumentsResponse(documents []db.IdentityDocument) []identityDocumentResponse {
+	rsp := make([]identityDocumentResponse, len(documents))
+	for i, document := range documents {
+		rsp[i] = newIdentityDocumentResponse(document)
+	}
+	return rsp
+}
+
+// detectDocumentContentType reads the beginning of the file to find its type, whatever the client
+// declared, and returns the file rewound.
+func detectDocumentContentType(file multipart.File) (string, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	if err != nil {
+		return "", err
+	}
+	return contentType, nil
+}
+
+// uploadIdentityDocument stores an identity document of the user, encrypted, and moves their
+// verification to documents uploaded. Documents can be added until the review starts, and again
+// once the verification has been rejected or has expired.
+func (server *Server) uploadIdentityDocument(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, server.Config.DocumentMaxSize+multipartOverhead)
+
+	var req uploadIdentityDocumentRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		if err.Error() == "http: request body too large" {
+			respondError(ctx, http.StatusRequestEntityTooLarge, errDocumentTooLarge)
+			return
+		}
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if req.File.Size > server.Config.DocumentMaxSize {
+		respondError(ctx, http.StatusRequestEntityTooLarge, errDocumentTooLarge)
+		return
+	}
+	if req.File.Size == 0 {
+		respondError(ctx, http.StatusBadRequest, errDocumentEmpty)
+		return
+	}
+
+	userInfo, err := server.Store.GetUserInfo(ctx, authPayload.UserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, errUserInfoNotFound)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if !db.CanTransitionVerification(userInfo.VerificationStep, db.VerificationDocumentsUploaded) {
+		respondError(ctx, http.StatusConflict, errDocumentsLocked)
+		return
+	}
+
+	file, err := req.File.Open()
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	defer file.Close()
+
+	contentType, err := detectDocumentContentType(file)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if !documentContentTypes[contentType] {
+		respondError(ctx, http.StatusUnsupportedMediaType, errDocumentTypeUnsupported)
+		return
+	}
+
+	storageKey := fmt.Sprintf("users/%s/documents/%s", authPayload.UserID, uuid.New())
+	err = server.DocumentStorage.Put(ctx, storageKey, file)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	result, err := server.Store.CreateIdentityDocumentTx(ctx, db.CreateIdentityDocumentParams{
+		UserID:      authPayload.UserID,
+		Kind:        req.Kind,
+		Filename:    filepath.Base(req.File.Filename),
+		ContentType: contentType,
+		Size:        req.File.Size,
+		StorageKey:  storageKey,
+	})
+	if err != nil {
+		// the document is not referenced, it must not be kept
+		_ = server.DocumentStorage.Delete(ctx, storageKey)
+
+		if errors.Is(err, db.ErrInvalidVerificationTransition) {
+			respondError(ctx, http.StatusConflict, errDocumentsLocked)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newIdentityDocumentResponse(result.Document))
+}
+
+func (server *Server) listIdentityDocuments(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	documents, err := server.Store.ListIdentityDocuments(ctx, authPayload.UserID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newIdentityDocumentsResponse(documents))
+}
+
+func (server *Server) listUserIdentityDocuments(ctx *gin.Context) {
+	user, ok := server.getAdminUser(ctx)
+	if !ok {
+		return
+	}
+
+	documents, err := server.Store.ListIdentityDocuments(ctx, user.ID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newIdentityDocumentsResponse(documents))
+}
+
+type downloadIdentityDocumentRequest struct {
+	DocumentID int64 `uri:"document_id" binding:"required,min=1"`
+}
+
+// downloadIdentityDocument sends the decrypted content of an identity document of the user to the
+// staff member. Every download is recorded in the audit events of the user.
+func (server *Server) downloadIdentityDocument(ctx *gin.Context) {
+	user, ok := server.getAdminUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req downloadIdentityDocumentRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	document, err := server.Store.GetIdentityDocument(ctx, db.GetIdentityDocumentParams{
+		ID:     req.DocumentID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, errDocumentNotFound)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	blob, err := server.DocumentStorage.Get(ctx, document.StorageKey)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	defer blob.Close()
+
+	content, err := ioutil.ReadAll(blob)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = server.auditAdminAction(ctx, user.ID, db.AuditIdentityDocumentRead, fmt.Sprintf("document %d (%s)", document.ID, document.Kind))
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": document.Filename}))
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Data(http.StatusOK, document.ContentType, content)
+}
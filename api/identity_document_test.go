@@ -0,0 +1,352 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockcache "github.com/awakim/immoblock-backend/cache/mock"
+	mockdb "github.com/awakim/immoblock-backend/db/mock"
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	mockidentity "github.com/awakim/immoblock-backend/identity/mock"
+	mockstorage "github.com/awakim/immoblock-backend/storage/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+var pdfContent = []byte("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\n%%EOF\n")
+
+// newDocumentUpload builds a multipart body with the kind and the file given.
+func newDocumentUpload(t *testing.T, kind string, filename string, content []byte) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	if kind != "" {
+		require.NoError(t, writer.WriteField("kind", kind))
+	}
+	if filename != "" {
+		part, err := writer.CreateFormFile("file", filename)
+		require.NoError(t, err)
+		_, err = part.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+func TestUploadIdentityDocumentAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	userInfo := randomUserInfo(user.ID)
+
+	testCases := []struct {
+		name          string
+		kind          string
+		filename      string
+		content       []byte
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, server *Server)
+	}{
+		{
+			name:     "OK",
+			kind:     db.IdentityDocumentIDCard,
+			filename: "../id card.pdf",
+			content:  pdfContent,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(userInfo, nil)
+				store.EXPECT().
+					CreateIdentityDocumentTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateIdentityDocumentParams) (db.CreateIdentityDocumentTxResult, error) {
+						if arg.UserID != user.ID || arg.Kind != db.IdentityDocumentIDCard || arg.Filename != "id card.pdf" ||
+							arg.ContentType != "application/pdf" || arg.Size != int64(len(pdfContent)) ||
+							!strings.HasPrefix(arg.StorageKey, fmt.Sprintf("users/%s/documents/", user.ID)) {
+							return db.CreateIdentityDocumentTxResult{}, fmt.Errorf("unexpected document %+v", arg)
+						}
+						document := db.IdentityDocument{
+							ID:          1,
+							UserID:      arg.UserID,
+							Kind:        arg.Kind,
+							Filename:    arg.Filename,
+							ContentType: arg.ContentType,
+							Size:        arg.Size,
+							StorageKey:  arg.StorageKey,
+							CreatedAt:   time.Now().UTC(),
+						}
+						return db.CreateIdentityDocumentTxResult{Document: document}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, server *Server) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp identityDocumentResponse
+				require.NoError(t, json.NewDecoder(recorder.Body).Decode(&rsp))
+				require.Equal(t, int64(1), rsp.ID)
+				require.Equal(t, "application/pdf", rsp.ContentType)
+				require.NotContains(t, recorder.Body.String(), "storage_key")
+			},
+		},
+		{
+			name:     "UnsupportedType",
+			kind:     db.IdentityDocumentPassport,
+			filename: "passport.pdf",
+			content:  []byte("#!/bin/sh\necho pwned\n"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(userInfo, nil)
+				store.EXPECT().CreateIdentityDocumentTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, server *Server) {
+				require.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+			},
+		},
+		{
+			name:     "TooLarge",
+			kind:     db.IdentityDocumentPassport,
+			filename: "passport.pdf",
+			content:  append(pdfContent, make([]byte, 1024)...),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateIdentityDocumentTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, server *Server) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+			},
+		},
+		{
+			name:     "BodyTooLarge",
+			kind:     db.IdentityDocumentPassport,
+			filename: "passport.pdf",
+			content:  append(pdfContent, make([]byte, 1024+multipartOverhead)...),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateIdentityDocumentTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, server *Server) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidKind",
+			kind:     "selfie",
+			filename: "selfie.pdf",
+			content:  pdfContent,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, server *Server) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "MissingFile",
+			kind: db.IdentityDocumentIDCard,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, server *Server) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "NoInformation",
+			kind:     db.IdentityDocumentIDCard,
+			filename: "id.pdf",
+			content:  pdfContent,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.UserInformation{}, sql.ErrNoRows)
+				store.EXPECT().CreateIdentityDocumentTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, server *Server) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "UnderReview",
+			kind:     db.IdentityDocumentIDCard,
+			filename: "id.pdf",
+			content:  pdfContent,
+			buildStubs: func(store *mockdb.MockStore) {
+				underReview := userInfo
+				underReview.VerificationStep = db.VerificationUnderReview
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(underReview, nil)
+				store.EXPECT().CreateIdentityDocumentTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, server *Server) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "TransitionRaced",
+			kind:     db.IdentityDocumentIDCard,
+			filename: "id.pdf",
+			content:  pdfContent,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(userInfo, nil)
+				store.EXPECT().
+					CreateIdentityDocumentTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateIdentityDocumentTxResult{}, fmt.Errorf("%w: from under_review to documents_uploaded", db.ErrInvalidVerificationTransition))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, server *Server) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			body, contentType := newDocumentUpload(t, tc.kind, tc.filename, tc.content)
+			request, err := http.NewRequest(http.MethodPost, "/users/info/documents", body)
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", contentType)
+
+			addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder, server)
+		})
+	}
+}
+
+func TestUploadIdentityDocumentStorageCleanup(t *testing.T) {
+	user, _ := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	cache := mockcache.NewMockCache(ctrl)
+	userManager := mockidentity.NewMockUserManagement(ctrl)
+	documentStorage := mockstorage.NewMockStorage(ctrl)
+
+	var storageKey string
+	cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+	store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(randomUserInfo(user.ID), nil)
+	documentStorage.EXPECT().
+		Put(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, key string, _ interface{}) error {
+			storageKey = key
+			return nil
+		})
+	store.EXPECT().CreateIdentityDocumentTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CreateIdentityDocumentTxResult{}, sql.ErrConnDone)
+	documentStorage.EXPECT().
+		Delete(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, key string) error {
+			require.Equal(t, storageKey, key)
+			return nil
+		})
+
+	server := newTestServer(t, store, cache, userManager)
+	server.DocumentStorage = documentStorage
+	recorder := httptest.NewRecorder()
+
+	body, contentType := newDocumentUpload(t, db.IdentityDocumentIDCard, "id.pdf", pdfContent)
+	request, err := http.NewRequest(http.MethodPost, "/users/info/documents", body)
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", contentType)
+
+	addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
+
+func TestDownloadIdentityDocumentAPI(t *testing.T) {
+	staff, _ := randomUser(t)
+	user, _ := randomUser(t)
+
+	document := db.IdentityDocument{
+		ID:          7,
+		UserID:      user.ID,
+		Kind:        db.IdentityDocumentPassport,
+		Filename:    "passport.pdf",
+		ContentType: "application/pdf",
+		Size:        int64(len(pdfContent)),
+		StorageKey:  fmt.Sprintf("users/%s/documents/passport", user.ID),
+		CreatedAt:   time.Now().UTC(),
+	}
+	arg := db.GetIdentityDocumentParams{ID: document.ID, UserID: user.ID}
+	url := fmt.Sprintf("/admin/users/%s/documents/%d", user.ID, document.ID)
+
+	t.Run("OK", func(t *testing.T) {
+		var server *Server
+		recorder := serveAdminRequest(t, http.MethodGet, url, nil, staff.ID, complianceAccess,
+			func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetIdentityDocument(gomock.Any(), gomock.Eq(arg)).Times(1).Return(document, nil)
+				expectAdminAudit(store, user.ID, staff.ID, db.AuditIdentityDocumentRead)
+			},
+			func(s *Server) {
+				server = s
+				err := server.DocumentStorage.Put(context.Background(), document.StorageKey, bytes.NewReader(pdfContent))
+				require.NoError(t, err)
+			})
+		require.NotNil(t, server)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Equal(t, pdfContent, recorder.Body.Bytes())
+		require.Equal(t, "application/pdf", recorder.Header().Get("Content-Type"))
+		require.Equal(t, `attachment; filename=passport.pdf`, recorder.Header().Get("Content-Disposition"))
+		require.Equal(t, "no-store", recorder.Header().Get("Cache-Control"))
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		recorder := serveAdminRequest(t, http.MethodGet, url, nil, staff.ID, complianceAccess,
+			func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetIdentityDocument(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.IdentityDocument{}, sql.ErrNoRows)
+				store.EXPECT().CreateAuditEvent(gomock.Any(), gomock.Any()).Times(0)
+			})
+		require.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("MissingPermission", func(t *testing.T) {
+		recorder := serveAdminRequest(t, http.MethodGet, url, nil, staff.ID, supportAccess,
+			func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetIdentityDocument(gomock.Any(), gomock.Any()).Times(0)
+			})
+		require.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+}
+
+func TestListUserIdentityDocumentsAPI(t *testing.T) {
+	staff, _ := randomUser(t)
+	user, _ := randomUser(t)
+
+	documents := []db.IdentityDocument{
+		{ID: 2, UserID: user.ID, Kind: db.IdentityDocumentProofOfAddress, StorageKey: "users/x/documents/2"},
+		{ID: 1, UserID: user.ID, Kind: db.IdentityDocumentIDCard, StorageKey: "users/x/documents/1"},
+	}
+
+	recorder := serveAdminRequest(t, http.MethodGet, fmt.Sprintf("/admin/users/%s/documents", user.ID), nil, staff.ID, complianceAccess,
+		func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+			store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+			store.EXPECT().ListIdentityDocuments(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(documents, nil)
+		})
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp []identityDocumentResponse
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&rsp))
+	require.Len(t, rsp, 2)
+	require.Equal(t, db.IdentityDocumentProofOfAddress, rsp[0].Kind)
+	require.NotContains(t, recorder.Body.String(), "storage_key")
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	"github.com/awakim/immoblock-backend/kyc"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errVerificationNotReady = errors.New("identity documents must be uploaded before the verification")
+	errApplicantNotFound    = errors.New("KYC applicant not found")
+)
+
+// maxWebhookSize is the largest webhook body accepted from the KYC provider
+const maxWebhookSize = 1 << 20
+
+func newKYCApplicant(userInfo db.UserInformation) kyc.Applicant {
+	return kyc.Applicant{
+		UserID:      userInfo.UserID,
+		Firstname:   userInfo.Firstname,
+		Lastname:    userInfo.Lastname,
+		PhoneNumber: userInfo.PhoneNumber,
+		Nationality: userInfo.Nationality,
+		Address:     userInfo.Address,
+		PostalCode:  userInfo.PostalCode,
+		City:        userInfo.City,
+		Country:     userInfo.Country,
+	}
+}
+
+// submitVerification sends the information of the user to the KYC provider once their documents
+// are uploaded. The verification stays under review until the provider decides.
+func (server *Server) submitVerification(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	userInfo, err := server.Store.GetUserInfo(ctx, authPayload.UserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, errUserInfoNotFound)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if !db.CanTransitionVerification(userInfo.VerificationStep, db.VerificationUnderReview) {
+		respondError(ctx, http.StatusConflict, errVerificationNotReady)
+		return
+	}
+
+	applicantID, err := server.KYCProvider.SubmitApplicant(ctx, newKYCApplicant(userInfo))
+	if err != nil {
+		respondError(ctx, http.StatusBadGateway, err)
+		return
+	}
+
+	result, err := server.Store.SubmitKYCApplicantTx(ctx, db.SubmitKYCApplicantTxParams{
+		UserID:      userInfo.UserID,
+		ApplicantID: applicantID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrInvalidVerificationTransition) {
+			respondError(ctx, http.StatusConflict, errVerificationNotReady)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserInfoResponse(result.UserInfo))
+}
+
+// kycEventStep returns the verification step a decision of the provider leads to, none when the
+// compliance team has to decide. A screening hit always goes to the compliance team.
+func kycEventStep(event kyc.Event) int16 {
+	switch {
+	case event.Decision == kyc.DecisionRejected:
+		return db.VerificationRejected
+	case event.Decision == kyc.DecisionApproved && !event.Screening.Hit():
+		return db.VerificationApproved
+	}
+	return 0
+}
+
+// kycWebhook receives the decisions of the KYC provider. The webhooks are authenticated by their
+// signature, and a webhook delivered twice is only applied once.
+func (server *Server) kycWebhook(ctx *gin.Context) {
+	body, err := ioutil.ReadAll(io.LimitReader(ctx.Request.Body, maxWebhookSize))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	event, err := server.KYCProvider.ParseWebhook(ctx.Request.Header, body)
+	if err != nil {
+		if errors.Is(err, kyc.ErrInvalidSignature) {
+			respondError(ctx, http.StatusUnauthorized, err)
+			return
+		}
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	userInfo, err := server.Store.GetUserInfoByKYCApplicant(ctx, event.ApplicantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, errApplicantNotFound)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	_, err = server.Store.RecordKYCCheckTx(ctx, db.RecordKYCCheckTxParams{
+		CreateKYCCheckParams: db.CreateKYCCheckParams{
+			UserID:       userInfo.UserID,
+			EventID:      event.ID,
+			ApplicantID:  event.ApplicantID,
+			Decision:     event.Decision,
+			Reason:       event.Reason,
+			SanctionsHit: event.Screening.SanctionsHit,
+			PepHit:       event.Screening.PEPHit,
+			Matches:      strings.Join(event.Screening.Matches, "\n"),
+		},
+		ToStep: kycEventStep(event),
+	})
+	if err != nil && !errors.Is(err, db.ErrKYCCheckRecorded) {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "event received",
+	})
+}
+
+// listKYCChecks lists the decisions of the KYC provider on the user, the latest first.
+func (server *Server) listKYCChecks(ctx *gin.Context) {
+	user, ok := server.getAdminUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req adminPageRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	checks, err := server.Store.ListKYCChecks(ctx, db.ListKYCChecksParams{
+		UserID: user.ID,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, checks)
+}
@@ -0,0 +1,278 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockcache "github.com/awakim/immoblock-backend/cache/mock"
+	mockdb "github.com/awakim/immoblock-backend/db/mock"
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	mockidentity "github.com/awakim/immoblock-backend/identity/mock"
+	"github.com/awakim/immoblock-backend/kyc"
+	mockkyc "github.com/awakim/immoblock-backend/kyc/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitVerificationAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	userInfo := randomUserInfo(user.ID)
+	userInfo.VerificationStep = db.VerificationDocumentsUploaded
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore, provider *mockkyc.MockProvider)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore, provider *mockkyc.MockProvider) {
+				underReview := userInfo
+				underReview.VerificationStep = db.VerificationUnderReview
+				underReview.KycApplicantID = "app_42"
+
+				arg := db.SubmitKYCApplicantTxParams{UserID: user.ID, ApplicantID: "app_42"}
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(userInfo, nil)
+				provider.EXPECT().SubmitApplicant(gomock.Any(), gomock.Eq(newKYCApplicant(userInfo))).Times(1).Return("app_42", nil)
+				store.EXPECT().SubmitKYCApplicantTx(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.TransitionVerificationTxResult{UserInfo: underReview}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"verification_status":"under_review"`)
+			},
+		},
+		{
+			name: "DocumentsMissing",
+			buildStubs: func(store *mockdb.MockStore, provider *mockkyc.MockProvider) {
+				infoSubmitted := userInfo
+				infoSubmitted.VerificationStep = db.VerificationInfoSubmitted
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(infoSubmitted, nil)
+				provider.EXPECT().SubmitApplicant(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "NoInformation",
+			buildStubs: func(store *mockdb.MockStore, provider *mockkyc.MockProvider) {
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.UserInformation{}, sql.ErrNoRows)
+				provider.EXPECT().SubmitApplicant(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "ProviderError",
+			buildStubs: func(store *mockdb.MockStore, provider *mockkyc.MockProvider) {
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(userInfo, nil)
+				provider.EXPECT().SubmitApplicant(gomock.Any(), gomock.Any()).Times(1).Return("", errors.New("provider unavailable"))
+				store.EXPECT().SubmitKYCApplicantTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadGateway, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			provider := mockkyc.NewMockProvider(ctrl)
+			cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			tc.buildStubs(store, provider)
+
+			server := newTestServer(t, store, cache, userManager)
+			server.KYCProvider = provider
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/users/info/verification", nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+// expectKYCCheck expects the decision of the provider to be recorded, leading to the step given
+func expectKYCCheck(store *mockdb.MockStore, userInfo db.UserInformation, decision string, toStep int16, err error) {
+	store.EXPECT().
+		RecordKYCCheckTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.RecordKYCCheckTxParams) (db.RecordKYCCheckTxResult, error) {
+			if arg.UserID != userInfo.UserID || arg.ApplicantID != userInfo.KycApplicantID ||
+				arg.Decision != decision || arg.ToStep != toStep || !strings.HasPrefix(arg.EventID, "evt_") {
+				return db.RecordKYCCheckTxResult{}, fmt.Errorf("unexpected KYC check %+v", arg)
+			}
+			return db.RecordKYCCheckTxResult{}, err
+		})
+}
+
+func TestKYCWebhookAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	userInfo := randomUserInfo(user.ID)
+	userInfo.VerificationStep = db.VerificationUnderReview
+
+	testCases := []struct {
+		name          string
+		webhook       func(t *testing.T, provider *kyc.LocalProvider, applicantID string) ([]byte, http.Header)
+		buildStubs    func(store *mockdb.MockStore, userInfo db.UserInformation)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Approved",
+			webhook: func(t *testing.T, provider *kyc.LocalProvider, applicantID string) ([]byte, http.Header) {
+				body, header, err := provider.Decide(applicantID, kyc.DecisionApproved, "", kyc.Screening{})
+				require.NoError(t, err)
+				return body, header
+			},
+			buildStubs: func(store *mockdb.MockStore, userInfo db.UserInformation) {
+				store.EXPECT().GetUserInfoByKYCApplicant(gomock.Any(), gomock.Eq(userInfo.KycApplicantID)).Times(1).Return(userInfo, nil)
+				expectKYCCheck(store, userInfo, kyc.DecisionApproved, db.VerificationApproved, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "ApprovedWithScreeningHit",
+			webhook: func(t *testing.T, provider *kyc.LocalProvider, applicantID string) ([]byte, http.Header) {
+				screening := kyc.Screening{PEPHit: true, Matches: []string{"John Doe, mayor"}}
+				body, header, err := provider.Decide(applicantID, kyc.DecisionApproved, "", screening)
+				require.NoError(t, err)
+				return body, header
+			},
+			buildStubs: func(store *mockdb.MockStore, userInfo db.UserInformation) {
+				store.EXPECT().GetUserInfoByKYCApplicant(gomock.Any(), gomock.Eq(userInfo.KycApplicantID)).Times(1).Return(userInfo, nil)
+				expectKYCCheck(store, userInfo, kyc.DecisionApproved, 0, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Rejected",
+			webhook: func(t *testing.T, provider *kyc.LocalProvider, applicantID string) ([]byte, http.Header) {
+				body, header, err := provider.Decide(applicantID, kyc.DecisionRejected, "document expired", kyc.Screening{})
+				require.NoError(t, err)
+				return body, header
+			},
+			buildStubs: func(store *mockdb.MockStore, userInfo db.UserInformation) {
+				store.EXPECT().GetUserInfoByKYCApplicant(gomock.Any(), gomock.Eq(userInfo.KycApplicantID)).Times(1).Return(userInfo, nil)
+				expectKYCCheck(store, userInfo, kyc.DecisionRejected, db.VerificationRejected, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Redelivered",
+			webhook: func(t *testing.T, provider *kyc.LocalProvider, applicantID string) ([]byte, http.Header) {
+				body, header, err := provider.Decide(applicantID, kyc.DecisionApproved, "", kyc.Screening{})
+				require.NoError(t, err)
+				return body, header
+			},
+			buildStubs: func(store *mockdb.MockStore, userInfo db.UserInformation) {
+				store.EXPECT().GetUserInfoByKYCApplicant(gomock.Any(), gomock.Eq(userInfo.KycApplicantID)).Times(1).Return(userInfo, nil)
+				expectKYCCheck(store, userInfo, kyc.DecisionApproved, db.VerificationApproved, db.ErrKYCCheckRecorded)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidSignature",
+			webhook: func(t *testing.T, provider *kyc.LocalProvider, applicantID string) ([]byte, http.Header) {
+				body, header, err := provider.Decide(applicantID, kyc.DecisionApproved, "", kyc.Screening{})
+				require.NoError(t, err)
+				return bytes.Replace(body, []byte(kyc.DecisionApproved), []byte(kyc.DecisionRejected), 1), header
+			},
+			buildStubs: func(store *mockdb.MockStore, userInfo db.UserInformation) {
+				store.EXPECT().GetUserInfoByKYCApplicant(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().RecordKYCCheckTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "MissingSignature",
+			webhook: func(t *testing.T, provider *kyc.LocalProvider, applicantID string) ([]byte, http.Header) {
+				body, _, err := provider.Decide(applicantID, kyc.DecisionApproved, "", kyc.Screening{})
+				require.NoError(t, err)
+				return body, http.Header{}
+			},
+			buildStubs: func(store *mockdb.MockStore, userInfo db.UserInformation) {
+				store.EXPECT().RecordKYCCheckTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownApplicant",
+			webhook: func(t *testing.T, provider *kyc.LocalProvider, applicantID string) ([]byte, http.Header) {
+				body, header, err := provider.Decide(applicantID, kyc.DecisionApproved, "", kyc.Screening{})
+				require.NoError(t, err)
+				return body, header
+			},
+			buildStubs: func(store *mockdb.MockStore, userInfo db.UserInformation) {
+				store.EXPECT().GetUserInfoByKYCApplicant(gomock.Any(), gomock.Eq(userInfo.KycApplicantID)).Times(1).Return(db.UserInformation{}, sql.ErrNoRows)
+				store.EXPECT().RecordKYCCheckTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+
+			server := newTestServer(t, store, cache, userManager)
+			provider := server.KYCProvider.(*kyc.LocalProvider)
+
+			applicantID, err := provider.SubmitApplicant(context.Background(), newKYCApplicant(userInfo))
+			require.NoError(t, err)
+			applicantInfo := userInfo
+			applicantInfo.KycApplicantID = applicantID
+			tc.buildStubs(store, applicantInfo)
+
+			recorder := httptest.NewRecorder()
+			body, header := tc.webhook(t, provider, applicantID)
+			request, err := http.NewRequest(http.MethodPost, "/webhooks/kyc", bytes.NewReader(body))
+			require.NoError(t, err)
+			request.Header = header
+
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -9,15 +9,32 @@ import (
 	"github.com/awakim/immoblock-backend/config"
 	db "github.com/awakim/immoblock-backend/db/sqlc"
 	identity "github.com/awakim/immoblock-backend/identity/auth0"
+	"github.com/awakim/immoblock-backend/kyc"
+	mail "github.com/awakim/immoblock-backend/mail/local"
+	payment "github.com/awakim/immoblock-backend/payment/local"
+	storage "github.com/awakim/immoblock-backend/storage/local"
 	"github.com/awakim/immoblock-backend/util"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
 )
 
 func newTestServer(t *testing.T, store db.Store, cache cache.Cache, userManager identity.UserManager) *Server {
-	config := config.Config{
-		TokenSymmetricKey:   util.RandomString(32),
-		AccessTokenDuration: time.Minute,
+	return newTestServerWithConfig(t, testConfig(), store, cache, userManager)
+}
+
+func testConfig() config.Config {
+	return config.Config{
+		TokenSymmetricKey:              util.RandomString(32),
+		TokenIssuer:                    "immoblock-backend",
+		TokenAudience:                  "immoblock",
+		AccessTokenDuration:            time.Minute,
+		EmailVerificationTokenDuration: time.Minute,
+		EmailVerificationURL:           "http://localhost:4200/verify-email",
+		PasswordResetTokenDuration:     time.Minute,
+		PasswordResetURL:               "http://localhost:4200/reset-password",
+		MFAIssuer:                      "Immoblock",
+		MFAChallengeTokenDuration:      time.Minute,
+		DocumentMaxSize:                1024,
 		CorsOrigins: []string{
 			"http://localhost:4200",
 			"https://localhost:4200",
@@ -25,7 +42,18 @@ func newTestServer(t *testing.T, store db.Store, cache cache.Cache, userManager
 			"https://localhost:8080",
 		},
 	}
-	server, err := NewServer(config, store, cache, userManager)
+}
+
+func newTestServerWithConfig(t *testing.T, config config.Config, store db.Store, cache cache.Cache, userManager identity.UserManager) *Server {
+	mailer, err := mail.NewLocalMailer("")
+	require.NoError(t, err)
+
+	localStorage, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	documentStorage, err := storage.NewEncryptedStorage(localStorage, []byte(util.RandomString(32)))
+	require.NoError(t, err)
+
+	server, err := NewServer(config, store, cache, userManager, payment.NewLocalProvider(), mailer, documentStorage, kyc.NewLocalProvider(util.RandomString(32)))
 	require.NoError(t, err)
 
 	return server
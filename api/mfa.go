@@ -0,0 +1,358 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	mfaCodeHeader             = "X-MFA-Code"
+	recoveryCodeCount         = 10
+	defaultMFAMaxAttempts     = 5
+	defaultMFALockoutDuration = 15 * time.Minute
+)
+
+var (
+	errInvalidMFACode    = errors.New("invalid two-factor authentication code")
+	errMFALockedOut      = errors.New("too many invalid two-factor authentication codes, try again later")
+	errMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	errMFANotEnabled     = errors.New("two-factor authentication is not enabled")
+)
+
+// mfaEnabled tells whether the enrollment of the secret has been confirmed.
+func mfaEnabled(secret db.MfaSecret) bool {
+	return !secret.ConfirmedAt.IsZero()
+}
+
+// verifyMFACode accepts either a TOTP code of the secret or one of the user's unused recovery codes.
+// A TOTP code is only accepted once, and so is a recovery code. After too many invalid codes the
+// user is locked out of two-factor authentication until the failures expire.
+func (server *Server) verifyMFACode(ctx context.Context, secret db.MfaSecret, code string) error {
+	maxAttempts := server.Config.MFAMaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMFAMaxAttempts
+	}
+	lockout := server.Config.MFALockoutDuration
+	if lockout <= 0 {
+		lockout = defaultMFALockoutDuration
+	}
+
+	userID := secret.UserID.String()
+	failures, err := server.Cache.MFAFailures(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if failures >= maxAttempts {
+		return errMFALockedOut
+	}
+
+	err = server.checkMFACode(ctx, secret, code)
+	if err == errInvalidMFACode {
+		if _, err := server.Cache.AddMFAFailure(ctx, userID, lockout); err != nil {
+			return err
+		}
+		return errInvalidMFACode
+	}
+	if err != nil {
+		return err
+	}
+	return server.Cache.ResetMFAFailures(ctx, userID)
+}
+
+func (server *Server) checkMFACode(ctx context.Context, secret db.MfaSecret, code string) error {
+	if step, ok := util.ValidateTOTP(secret.Secret, code, time.Now()); ok {
+		_, err := server.Store.UseMFAStep(ctx, db.UseMFAStepParams{
+			UserID: secret.UserID,
+			Step:   step,
+		})
+		if err == sql.ErrNoRows {
+			return errInvalidMFACode
+		}
+		return err
+	}
+
+	_, err := server.Store.UseRecoveryCode(ctx, db.UseRecoveryCodeParams{
+		UserID:     secret.UserID,
+		HashedCode: util.HashRecoveryCode(code),
+	})
+	if err == sql.ErrNoRows {
+		return errInvalidMFACode
+	}
+	return err
+}
+
+type enrollMFAResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// enrollMFA generates a new TOTP secret for the authenticated user. The provisioning URI is meant
+// to be shown as a QR code, two-factor authentication is enabled once a code is confirmed.
+func (server *Server) enrollMFA(ctx *gin.Context) {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	user, err := server.Store.GetUserByID(ctx, payload.UserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	secret, err := util.RandomTOTPSecret()
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	arg := db.CreateMFASecretParams{
+		UserID: user.ID,
+		Secret: secret,
+	}
+	// a confirmed secret is not replaced and no row is returned
+	_, err = server.Store.CreateMFASecret(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusForbidden, errMFAAlreadyEnabled)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	rsp := enrollMFAResponse{
+		Secret:          secret,
+		ProvisioningURI: util.TOTPProvisioningURI(server.Config.MFAIssuer, user.Email, secret),
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type confirmMFARequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+type confirmMFAResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// confirmMFA enables two-factor authentication with a first code of the enrolled secret and
+// returns the recovery codes. They are only shown once.
+func (server *Server) confirmMFA(ctx *gin.Context) {
+	var req confirmMFARequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	secret, err := server.Store.GetMFASecret(ctx, payload.UserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if mfaEnabled(secret) {
+		respondError(ctx, http.StatusForbidden, errMFAAlreadyEnabled)
+		return
+	}
+
+	step, ok := util.ValidateTOTP(secret.Secret, req.Code, time.Now())
+	if !ok {
+		respondError(ctx, http.StatusUnauthorized, errInvalidMFACode)
+		return
+	}
+	_, err = server.Store.UseMFAStep(ctx, db.UseMFAStepParams{
+		UserID: secret.UserID,
+		Step:   step,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusUnauthorized, errInvalidMFACode)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashedCodes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		codes[i], err = util.RandomRecoveryCode()
+		if err != nil {
+			respondError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		hashedCodes[i] = util.HashRecoveryCode(codes[i])
+	}
+
+	_, err = server.Store.ConfirmMFATx(ctx, db.ConfirmMFATxParams{
+		UserID:              secret.UserID,
+		HashedRecoveryCodes: hashedCodes,
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, confirmMFAResponse{RecoveryCodes: codes})
+}
+
+type mfaChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+}
+
+type loginUserMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// loginUserMFA is the second step of the login of a user with two-factor authentication.
+// The MFA challenge token returned by loginUser is exchanged for a token pair with a valid code.
+func (server *Server) loginUserMFA(ctx *gin.Context) {
+	var req loginUserMFARequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	payload, err := server.MFATokenMaker.VerifyTokenFor(req.MFAToken, token.TypeMFAChallenge)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	user, err := server.Store.GetUserByID(ctx, payload.UserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusUnauthorized, errors.New("invalid credentials"))
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	// the user may have been locked since the password was checked
+	if userLocked(user) {
+		respondError(ctx, http.StatusForbidden, errUserLocked)
+		return
+	}
+
+	secret, err := server.Store.GetMFASecret(ctx, user.ID)
+	if err != nil && err != sql.ErrNoRows {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if err == sql.ErrNoRows || !mfaEnabled(secret) {
+		respondError(ctx, http.StatusForbidden, errMFANotEnabled)
+		return
+	}
+
+	if err := server.verifyMFACode(ctx, secret, req.Code); err != nil {
+		switch err {
+		case errInvalidMFACode:
+			respondError(ctx, http.StatusUnauthorized, err)
+		case errMFALockedOut:
+			respondError(ctx, http.StatusTooManyRequests, err)
+		default:
+			respondError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	fresh, err := server.Cache.ConsumeToken(ctx, *payload)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if !fresh {
+		respondError(ctx, http.StatusUnauthorized, errTokenAlreadyUsed)
+		return
+	}
+
+	rsp, err := server.newLoginResponse(ctx, user)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// transferStepUp requires a two-factor authentication code in the X-MFA-Code header for transfers
+// of more blocks than the configured threshold. It runs before the idempotency middleware so that
+// a missing code can be supplied on retry with the same key. A threshold of 0 disables the check.
+func (server *Server) transferStepUp(ctx *gin.Context) {
+	threshold := server.Config.MFATransferThreshold
+	if threshold <= 0 {
+		ctx.Next()
+		return
+	}
+
+	body, err := ioutil.ReadAll(ctx.Request.Body)
+	if err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	ctx.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	// the request is validated by the handler, only the amount matters here
+	var req struct {
+		Amount int64 `json:"amount"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil || req.Amount <= threshold {
+		ctx.Next()
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	secret, err := server.Store.GetMFASecret(ctx, payload.UserID)
+	if err != nil && err != sql.ErrNoRows {
+		abortWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if err == sql.ErrNoRows || !mfaEnabled(secret) {
+		err := fmt.Errorf("two-factor authentication must be enabled for transfers of more than %d blocks", threshold)
+		abortWithError(ctx, http.StatusForbidden, err)
+		return
+	}
+
+	code := ctx.GetHeader(mfaCodeHeader)
+	if code == "" {
+		err := fmt.Errorf("a two-factor authentication code is required in the %s header", mfaCodeHeader)
+		abortWithError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	if err := server.verifyMFACode(ctx, secret, code); err != nil {
+		switch err {
+		case errInvalidMFACode:
+			abortWithError(ctx, http.StatusUnauthorized, err)
+		case errMFALockedOut:
+			abortWithError(ctx, http.StatusTooManyRequests, err)
+		default:
+			abortWithError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	ctx.Next()
+}
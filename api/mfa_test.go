@@ -0,0 +1,654 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockcache "github.com/awakim/immoblock-backend/cache/mock"
+	mockdb "github.com/awakim/immoblock-backend/db/mock"
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	mockidentity "github.com/awakim/immoblock-backend/identity/mock"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomMFASecret(t *testing.T, user db.User, confirmed bool) db.MfaSecret {
+	secret, err := util.RandomTOTPSecret()
+	require.NoError(t, err)
+
+	mfaSecret := db.MfaSecret{
+		UserID: user.ID,
+		Secret: secret,
+	}
+	if confirmed {
+		mfaSecret.ConfirmedAt = time.Now().UTC()
+	}
+	return mfaSecret
+}
+
+func currentTOTPCode(t *testing.T, secret db.MfaSecret) string {
+	code, err := util.TOTPCode(secret.Secret, util.TOTPStep(time.Now()))
+	require.NoError(t, err)
+	return code
+}
+
+func TestEnrollMFAAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CreateMFASecret(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateMFASecretParams) (db.MfaSecret, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						return db.MfaSecret{UserID: arg.UserID, Secret: arg.Secret}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp enrollMFAResponse
+				err := json.NewDecoder(recorder.Body).Decode(&rsp)
+				require.NoError(t, err)
+				require.NotEmpty(t, rsp.Secret)
+				require.True(t, strings.HasPrefix(rsp.ProvisioningURI, "otpauth://totp/Immoblock:"))
+				require.Contains(t, rsp.ProvisioningURI, "secret="+rsp.Secret)
+			},
+		},
+		{
+			name: "AlreadyEnabled",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().CreateMFASecret(gomock.Any(), gomock.Any()).Times(1).Return(db.MfaSecret{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, sql.ErrConnDone)
+				store.EXPECT().CreateMFASecret(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/users/mfa/enroll", nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestConfirmMFAAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	pending := randomMFASecret(t, user, false)
+	confirmed := randomMFASecret(t, user, true)
+
+	testCases := []struct {
+		name          string
+		code          func() string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			code: func() string { return currentTOTPCode(t, pending) },
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().UseMFAStep(gomock.Any(), gomock.Any()).Times(1).Return(pending, nil)
+				store.EXPECT().
+					ConfirmMFATx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ConfirmMFATxParams) (db.ConfirmMFATxResult, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.Len(t, arg.HashedRecoveryCodes, recoveryCodeCount)
+						return db.ConfirmMFATxResult{}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp confirmMFAResponse
+				err := json.NewDecoder(recorder.Body).Decode(&rsp)
+				require.NoError(t, err)
+				require.Len(t, rsp.RecoveryCodes, recoveryCodeCount)
+			},
+		},
+		{
+			name: "InvalidCode",
+			code: func() string {
+				code := []byte(currentTOTPCode(t, pending))
+				code[0] = '0' + (code[0]-'0'+1)%10
+				return string(code)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().UseMFAStep(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ConfirmMFATx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ReplayedCode",
+			code: func() string { return currentTOTPCode(t, pending) },
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().UseMFAStep(gomock.Any(), gomock.Any()).Times(1).Return(db.MfaSecret{}, sql.ErrNoRows)
+				store.EXPECT().ConfirmMFATx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyEnabled",
+			code: func() string { return currentTOTPCode(t, confirmed) },
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(confirmed, nil)
+				store.EXPECT().ConfirmMFATx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NotEnrolled",
+			code: func() string { return "123456" },
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.MfaSecret{}, sql.ErrNoRows)
+				store.EXPECT().ConfirmMFATx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidCodeFormat",
+			code: func() string { return "abc" },
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"code": tc.code()})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/mfa/confirm", bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestLoginUserMFAChallengeAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	password := util.RandomString(10)
+	hashedPassword, err := util.HashPassword(password)
+	require.NoError(t, err)
+	user.HashedPassword = hashedPassword
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore, cache *mockcache.MockCache)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "MFAEnabled",
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(randomMFASecret(t, user, true), nil)
+				cache.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp mfaChallengeResponse
+				err := json.NewDecoder(recorder.Body).Decode(&rsp)
+				require.NoError(t, err)
+				require.True(t, rsp.MFARequired)
+				require.NotEmpty(t, rsp.MFAToken)
+			},
+		},
+		{
+			name: "MFAPending",
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(randomMFASecret(t, user, false), nil)
+				expectUserAccess(store, user.ID, investorAccess)
+				cache.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp loginUserResponse
+				err := json.NewDecoder(recorder.Body).Decode(&rsp)
+				require.NoError(t, err)
+				require.NotEmpty(t, rsp.AccessToken)
+				require.NotEmpty(t, rsp.RefreshToken)
+			},
+		},
+		{
+			name: "MFADisabled",
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.MfaSecret{}, sql.ErrNoRows)
+				expectUserAccess(store, user.ID, investorAccess)
+				cache.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp loginUserResponse
+				err := json.NewDecoder(recorder.Body).Decode(&rsp)
+				require.NoError(t, err)
+				require.NotEmpty(t, rsp.AccessToken)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Any()).Times(1).Return(db.MfaSecret{}, sql.ErrConnDone)
+				cache.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			cache.EXPECT().IsRateLimited(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+			tc.buildStubs(store, cache)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"email": user.Email, "password": password})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.RemoteAddr = "127.0.0.1:12345"
+
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestLoginUserMFAAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	secret := randomMFASecret(t, user, true)
+
+	mfaToken := func(server *Server) string {
+		_, mfaToken, err := server.MFATokenMaker.CreateToken(token.TypeMFAChallenge, user.ID, token.Access{}, time.Minute)
+		require.NoError(t, err)
+		return mfaToken
+	}
+
+	testCases := []struct {
+		name          string
+		token         func(server *Server) string
+		code          func() string
+		buildStubs    func(store *mockdb.MockStore, cache *mockcache.MockCache)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			token: mfaToken,
+			code:  func() string { return currentTOTPCode(t, secret) },
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(secret, nil)
+				cache.EXPECT().MFAFailures(gomock.Any(), gomock.Eq(user.ID.String())).Times(1).Return(int64(0), nil)
+				store.EXPECT().UseMFAStep(gomock.Any(), gomock.Any()).Times(1).Return(secret, nil)
+				cache.EXPECT().ResetMFAFailures(gomock.Any(), gomock.Eq(user.ID.String())).Times(1).Return(nil)
+				cache.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(1).Return(true, nil)
+				expectUserAccess(store, user.ID, investorAccess)
+				cache.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp loginUserResponse
+				err := json.NewDecoder(recorder.Body).Decode(&rsp)
+				require.NoError(t, err)
+				require.NotEmpty(t, rsp.AccessToken)
+				require.NotEmpty(t, rsp.RefreshToken)
+			},
+		},
+		{
+			name:  "RecoveryCode",
+			token: mfaToken,
+			code:  func() string { return "abcdefgh-ijklmnop" },
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(secret, nil)
+				cache.EXPECT().MFAFailures(gomock.Any(), gomock.Eq(user.ID.String())).Times(1).Return(int64(0), nil)
+				arg := db.UseRecoveryCodeParams{
+					UserID:     user.ID,
+					HashedCode: util.HashRecoveryCode("abcdefgh-ijklmnop"),
+				}
+				store.EXPECT().UseRecoveryCode(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.RecoveryCode{}, nil)
+				cache.EXPECT().ResetMFAFailures(gomock.Any(), gomock.Eq(user.ID.String())).Times(1).Return(nil)
+				cache.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(1).Return(true, nil)
+				expectUserAccess(store, user.ID, investorAccess)
+				cache.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidCode",
+			token: mfaToken,
+			code:  func() string { return "abcdefgh-ijklmnop" },
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(secret, nil)
+				cache.EXPECT().MFAFailures(gomock.Any(), gomock.Eq(user.ID.String())).Times(1).Return(int64(0), nil)
+				store.EXPECT().UseRecoveryCode(gomock.Any(), gomock.Any()).Times(1).Return(db.RecoveryCode{}, sql.ErrNoRows)
+				cache.EXPECT().AddMFAFailure(gomock.Any(), gomock.Eq(user.ID.String()), gomock.Eq(defaultMFALockoutDuration)).Times(1).Return(int64(1), nil)
+				cache.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(0)
+				cache.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "LockedOut",
+			token: mfaToken,
+			code:  func() string { return currentTOTPCode(t, secret) },
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(secret, nil)
+				cache.EXPECT().MFAFailures(gomock.Any(), gomock.Eq(user.ID.String())).Times(1).Return(int64(defaultMFAMaxAttempts), nil)
+				store.EXPECT().UseMFAStep(gomock.Any(), gomock.Any()).Times(0)
+				cache.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(0)
+				cache.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+			},
+		},
+		{
+			name:  "ChallengeAlreadyUsed",
+			token: mfaToken,
+			code:  func() string { return currentTOTPCode(t, secret) },
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(secret, nil)
+				cache.EXPECT().MFAFailures(gomock.Any(), gomock.Eq(user.ID.String())).Times(1).Return(int64(0), nil)
+				store.EXPECT().UseMFAStep(gomock.Any(), gomock.Any()).Times(1).Return(secret, nil)
+				cache.EXPECT().ResetMFAFailures(gomock.Any(), gomock.Eq(user.ID.String())).Times(1).Return(nil)
+				cache.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				cache.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "AccessTokenRejected",
+			token: func(server *Server) string {
+				_, accessToken, err := server.TokenMaker.CreateToken(token.TypeAccess, user.ID, token.Access{}, time.Minute)
+				require.NoError(t, err)
+				return accessToken
+			},
+			code: func() string { return currentTOTPCode(t, secret) },
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Times(0)
+				cache.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			cache.EXPECT().IsRateLimited(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			tc.buildStubs(store, cache)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"mfa_token": tc.token(server), "code": tc.code()})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/login/mfa", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.RemoteAddr = "127.0.0.1:12345"
+
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestTransferStepUpAPI(t *testing.T) {
+	threshold := int64(10)
+
+	user, _ := randomUser(t)
+	secret := randomMFASecret(t, user, true)
+	account1 := randomAccount(user.ID)
+	account2 := randomAccount(user.ID)
+	property := randomProperty(t)
+	account1.PropertyID = property.ID
+	account2.PropertyID = property.ID
+
+	expectTransfer := func(store *mockdb.MockStore, times int) {
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(times).Return(account1, nil)
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(times).Return(account2, nil)
+		store.EXPECT().GetProperty(gomock.Any(), gomock.Eq(property.ID)).Times(2*times).Return(property, nil)
+		store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(times)
+	}
+
+	testCases := []struct {
+		name          string
+		amount        int64
+		code          func() string
+		buildStubs    func(store *mockdb.MockStore, cache *mockcache.MockCache)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "BelowThreshold",
+			amount: threshold,
+			code:   func() string { return "" },
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Any()).Times(0)
+				expectTransfer(store, 1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "ValidCode",
+			amount: threshold + 1,
+			code:   func() string { return currentTOTPCode(t, secret) },
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(secret, nil)
+				cache.EXPECT().MFAFailures(gomock.Any(), gomock.Eq(user.ID.String())).Times(1).Return(int64(0), nil)
+				store.EXPECT().UseMFAStep(gomock.Any(), gomock.Any()).Times(1).Return(secret, nil)
+				cache.EXPECT().ResetMFAFailures(gomock.Any(), gomock.Eq(user.ID.String())).Times(1).Return(nil)
+				expectTransfer(store, 1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "MissingCode",
+			amount: threshold + 1,
+			code:   func() string { return "" },
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(secret, nil)
+				expectTransfer(store, 0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "ReplayedCode",
+			amount: threshold + 1,
+			code:   func() string { return currentTOTPCode(t, secret) },
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(secret, nil)
+				cache.EXPECT().MFAFailures(gomock.Any(), gomock.Eq(user.ID.String())).Times(1).Return(int64(0), nil)
+				store.EXPECT().UseMFAStep(gomock.Any(), gomock.Any()).Times(1).Return(db.MfaSecret{}, sql.ErrNoRows)
+				store.EXPECT().UseRecoveryCode(gomock.Any(), gomock.Any()).Times(0)
+				cache.EXPECT().AddMFAFailure(gomock.Any(), gomock.Eq(user.ID.String()), gomock.Eq(defaultMFALockoutDuration)).Times(1).Return(int64(1), nil)
+				expectTransfer(store, 0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "LockedOut",
+			amount: threshold + 1,
+			code:   func() string { return currentTOTPCode(t, secret) },
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(secret, nil)
+				cache.EXPECT().MFAFailures(gomock.Any(), gomock.Eq(user.ID.String())).Times(1).Return(int64(defaultMFAMaxAttempts), nil)
+				store.EXPECT().UseMFAStep(gomock.Any(), gomock.Any()).Times(0)
+				expectTransfer(store, 0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+			},
+		},
+		{
+			name:   "MFANotEnabled",
+			amount: threshold + 1,
+			code:   func() string { return "123456" },
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetMFASecret(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.MfaSecret{}, sql.ErrNoRows)
+				expectTransfer(store, 0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+			expectApprovedIdentity(store, user.ID)
+			tc.buildStubs(store, cache)
+
+			server := newTestServer(t, store, cache, userManager)
+			server.Config.MFATransferThreshold = threshold
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          tc.amount,
+				"property_id":     property.ID,
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+			if code := tc.code(); code != "" {
+				request.Header.Set(mfaCodeHeader, code)
+			}
+
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+)
+
+type placeOrderRequest struct {
+	Side   string `json:"side" binding:"required,oneof=buy sell"`
+	Price  int64  `json:"price" binding:"required,gt=0"`
+	Amount int64  `json:"amount" binding:"required,gt=0"`
+}
+
+func (server *Server) placeOrder(ctx *gin.Context) {
+	var uri getPropertyRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var req placeOrderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.PlaceOrderTxParams{
+		UserID:     authPayload.UserID,
+		PropertyID: uri.ID,
+		Side:       req.Side,
+		Price:      req.Price,
+		Amount:     req.Amount,
+	}
+
+	result, err := server.Store.PlaceOrderTx(ctx, arg)
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			respondError(ctx, http.StatusNotFound, err)
+		case errors.Is(err, db.ErrPropertyNotForSale):
+			respondError(ctx, http.StatusForbidden, err)
+		case errors.Is(err, db.ErrInsufficientBalance):
+			respondError(ctx, http.StatusConflict, err)
+		case errors.Is(err, db.ErrCostTooLarge):
+			respondError(ctx, http.StatusBadRequest, err)
+		default:
+			respondError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type cancelOrderRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) cancelOrder(ctx *gin.Context) {
+	var req cancelOrderRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	order, err := server.Store.GetOrder(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if order.UserID != authPayload.UserID {
+		err := errors.New("order does not belong to the authenticated user")
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	order, err = server.Store.CancelOrder(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusConflict, errors.New("order is no longer open"))
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, order)
+}
+
+type listOrdersRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listOrders(ctx *gin.Context) {
+	var req listOrdersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.ListOrdersParams{
+		UserID: authPayload.UserID,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	}
+
+	orders, err := server.Store.ListOrders(ctx, arg)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, orders)
+}
+
+type orderBookLevel struct {
+	Price      int64 `json:"price"`
+	Amount     int64 `json:"amount"`
+	OrderCount int64 `json:"order_count"`
+}
+
+type orderBookResponse struct {
+	PropertyID int64            `json:"property_id"`
+	Bids       []orderBookLevel `json:"bids"`
+	Asks       []orderBookLevel `json:"asks"`
+}
+
+// newOrderBookResponse splits the aggregated price levels into bids, best (highest) first,
+// and asks, best (lowest) first. Levels are expected in ascending price order.
+func newOrderBookResponse(propertyID int64, levels []db.ListOrderBookDepthRow) orderBookResponse {
+	rsp := orderBookResponse{
+		PropertyID: propertyID,
+		Bids:       []orderBookLevel{},
+		Asks:       []orderBookLevel{},
+	}
+	for _, level := range levels {
+		l := orderBookLevel{
+			Price:      level.Price,
+			Amount:     level.Amount,
+			OrderCount: level.OrderCount,
+		}
+		if level.Side == db.OrderSideBuy {
+			rsp.Bids = append([]orderBookLevel{l}, rsp.Bids...)
+		} else {
+			rsp.Asks = append(rsp.Asks, l)
+		}
+	}
+	return rsp
+}
+
+func (server *Server) getOrderBook(ctx *gin.Context) {
+	var req getPropertyRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	_, err := server.Store.GetProperty(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	levels, err := server.Store.ListOrderBookDepth(ctx, req.ID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newOrderBookResponse(req.ID, levels))
+}
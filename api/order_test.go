@@ -0,0 +1,350 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockcache "github.com/awakim/immoblock-backend/cache/mock"
+	mockdb "github.com/awakim/immoblock-backend/db/mock"
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	mockidentity "github.com/awakim/immoblock-backend/identity/mock"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func randomOrder(userID uuid.UUID, account db.Account, side string) db.Order {
+	amount := util.RandomInt(1, 100)
+	return db.Order{
+		ID:              util.RandomInt(1, 1000),
+		UserID:          userID,
+		AccountID:       account.ID,
+		PropertyID:      account.PropertyID,
+		Side:            side,
+		Price:           util.RandomInt(1, 10000),
+		Amount:          amount,
+		RemainingAmount: amount,
+		Status:          db.OrderStatusOpen,
+	}
+}
+
+func TestPlaceOrderAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.ID)
+	order := randomOrder(user.ID, account, db.OrderSideSell)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore, cache *mockcache.MockCache)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"side":   order.Side,
+				"price":  order.Price,
+				"amount": order.Amount,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				arg := db.PlaceOrderTxParams{
+					UserID:     user.ID,
+					PropertyID: order.PropertyID,
+					Side:       order.Side,
+					Price:      order.Price,
+					Amount:     order.Amount,
+				}
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().PlaceOrderTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.PlaceOrderTxResult{Order: order, Trades: []db.Trade{}}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InsufficientBalance",
+			body: gin.H{
+				"side":   order.Side,
+				"price":  order.Price,
+				"amount": order.Amount,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().PlaceOrderTx(gomock.Any(), gomock.Any()).Times(1).Return(db.PlaceOrderTxResult{}, db.ErrInsufficientBalance)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "CostTooLarge",
+			body: gin.H{
+				"side":   db.OrderSideBuy,
+				"price":  3,
+				"amount": math.MaxInt64 / 2,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().PlaceOrderTx(gomock.Any(), gomock.Any()).Times(1).Return(db.PlaceOrderTxResult{}, db.ErrCostTooLarge)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "EmailNotVerified",
+			body: gin.H{
+				"side":   order.Side,
+				"price":  order.Price,
+				"amount": order.Amount,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				unverified := user
+				unverified.EmailVerifiedAt = time.Time{}
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(unverified, nil)
+				store.EXPECT().PlaceOrderTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "IdentityNotApproved",
+			body: gin.H{
+				"side":   order.Side,
+				"price":  order.Price,
+				"amount": order.Amount,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				userInfo := randomUserInfo(user.ID)
+				userInfo.VerificationStep = db.VerificationUnderReview
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(userInfo, nil)
+				store.EXPECT().PlaceOrderTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidSide",
+			body: gin.H{
+				"side":   "hold",
+				"price":  order.Price,
+				"amount": order.Amount,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().PlaceOrderTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{
+				"side":   order.Side,
+				"price":  order.Price,
+				"amount": order.Amount,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().PlaceOrderTx(gomock.Any(), gomock.Any()).Times(1).Return(db.PlaceOrderTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			tc.buildStubs(store, cache)
+			store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).AnyTimes().Return(user, nil)
+			expectApprovedIdentity(store, user.ID)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/properties/%d/orders", order.PropertyID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.TokenMaker)
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestCancelOrderAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	otherUser, _ := randomUser(t)
+	account := randomAccount(user.ID)
+	order := randomOrder(user.ID, account, db.OrderSideBuy)
+	cancelled := order
+	cancelled.Status = db.OrderStatusCancelled
+
+	testCases := []struct {
+		name          string
+		userID        uuid.UUID
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			userID: user.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetOrder(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(order, nil)
+				store.EXPECT().CancelOrder(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(cancelled, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "UnauthorizedUser",
+			userID: otherUser.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetOrder(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(order, nil)
+				store.EXPECT().CancelOrder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "AlreadyFilled",
+			userID: user.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetOrder(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(order, nil)
+				store.EXPECT().CancelOrder(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(db.Order{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:   "NotFound",
+			userID: user.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetOrder(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(db.Order{}, sql.ErrNoRows)
+				store.EXPECT().CancelOrder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/orders/%d", order.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, tc.userID, investorAccess, time.Minute)
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetOrderBookAPI(t *testing.T) {
+	property := randomProperty(t)
+	levels := []db.ListOrderBookDepthRow{
+		{Side: db.OrderSideBuy, Price: 90, Amount: 5, OrderCount: 1},
+		{Side: db.OrderSideBuy, Price: 95, Amount: 10, OrderCount: 2},
+		{Side: db.OrderSideSell, Price: 100, Amount: 3, OrderCount: 1},
+		{Side: db.OrderSideSell, Price: 110, Amount: 7, OrderCount: 3},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	cache := mockcache.NewMockCache(ctrl)
+	userManager := mockidentity.NewMockUserManagement(ctrl)
+	store.EXPECT().GetProperty(gomock.Any(), gomock.Eq(property.ID)).Times(1).Return(property, nil)
+	store.EXPECT().ListOrderBookDepth(gomock.Any(), gomock.Eq(property.ID)).Times(1).Return(levels, nil)
+
+	server := newTestServer(t, store, cache, userManager)
+	recorder := httptest.NewRecorder()
+
+	url := fmt.Sprintf("/properties/%d/orderbook", property.ID)
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	data, err := ioutil.ReadAll(recorder.Body)
+	require.NoError(t, err)
+
+	var book orderBookResponse
+	err = json.Unmarshal(data, &book)
+	require.NoError(t, err)
+	require.Equal(t, property.ID, book.PropertyID)
+	require.Len(t, book.Bids, 2)
+	require.Len(t, book.Asks, 2)
+	require.Equal(t, int64(95), book.Bids[0].Price)
+	require.Equal(t, int64(100), book.Asks[0].Price)
+}
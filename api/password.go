@@ -0,0 +1,207 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	identity "github.com/awakim/immoblock-backend/identity/auth0"
+	mail "github.com/awakim/immoblock-backend/mail/local"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/gin-gonic/gin"
+)
+
+var errTokenIssuedBeforePasswordChange = errors.New("token was issued before the last password change")
+
+// sendPasswordResetEmail mails a short-lived, single-use password reset link to the user.
+func (server *Server) sendPasswordResetEmail(ctx context.Context, user db.User) error {
+	_, resetToken, err := server.PasswordTokenMaker.CreateToken(token.TypePasswordReset, user.ID, token.Access{}, server.Config.PasswordResetTokenDuration)
+	if err != nil {
+		return err
+	}
+
+	msg := mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nYou can choose a new password by following this link:\n%s?token=%s\n\nThe link expires in %s. If you did not ask for a new password, you can ignore this email.\n",
+			user.Nickname,
+			server.Config.PasswordResetURL,
+			resetToken,
+			server.Config.PasswordResetTokenDuration,
+		),
+	}
+	return server.Mailer.Send(ctx, msg)
+}
+
+// setPassword stores the new password of the user and signs the user out everywhere: every token
+// issued before the change is revoked and the refresh tokens are purged from the cache. Auth0 gets
+// the password first, so that a failure leaves the old password in place on both sides.
+func (server *Server) setPassword(ctx context.Context, user db.User, password string) (db.User, error) {
+	hashedPassword, err := util.HashPassword(password)
+	if err != nil {
+		return db.User{}, err
+	}
+
+	err = identity.SetPassword(server.UserManager, user.ID, password)
+	if err != nil {
+		return db.User{}, identityError(err)
+	}
+
+	arg := db.UpdateUserPasswordParams{
+		ID:                user.ID,
+		HashedPassword:    hashedPassword,
+		PasswordChangedAt: time.Now().UTC(),
+	}
+	user, err = server.Store.UpdateUserPassword(ctx, arg)
+	if err != nil {
+		return db.User{}, err
+	}
+
+	err = server.Cache.RevokeUserTokens(ctx, user.ID.String(), arg.PasswordChangedAt, server.Config.RefreshTokenDuration)
+	if err != nil {
+		return db.User{}, err
+	}
+	return user, nil
+}
+
+type forgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// forgotPassword sends a password reset link. The response is the same whether the email
+// is known or not, so that it cannot be used to list users.
+func (server *Server) forgotPassword(ctx *gin.Context) {
+	var req forgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	rsp := gin.H{
+		"message": "a password reset email has been sent if the address belongs to an account",
+	}
+
+	user, err := server.Store.GetUser(ctx, req.Email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusOK, rsp)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := server.sendPasswordResetEmail(ctx, user); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type resetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+func (server *Server) resetPassword(ctx *gin.Context) {
+	var req resetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	payload, err := server.PasswordTokenMaker.VerifyTokenFor(req.Token, token.TypePasswordReset)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	user, err := server.Store.GetUserByID(ctx, payload.UserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	// a reset link is void once the password has been changed, even if it has not been used
+	if payload.IssuedBefore(user.PasswordChangedAt) {
+		respondError(ctx, http.StatusUnauthorized, errTokenIssuedBeforePasswordChange)
+		return
+	}
+
+	fresh, err := server.Cache.ConsumeToken(ctx, *payload)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if !fresh {
+		respondError(ctx, http.StatusUnauthorized, errTokenAlreadyUsed)
+		return
+	}
+
+	// the token is released when the password cannot be set, so that the link can be followed again
+	if _, err := server.setPassword(ctx, user, req.Password); err != nil {
+		_ = server.Cache.ReleaseToken(ctx, *payload)
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "password has been reset, please log in again",
+	})
+}
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8,nefield=CurrentPassword"`
+}
+
+// changePassword replaces the password of the authenticated user. All the other sessions are
+// signed out and a new token pair is returned for the current one.
+func (server *Server) changePassword(ctx *gin.Context) {
+	var req changePasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	user, err := server.Store.GetUserByID(ctx, payload.UserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := util.CheckPassword(req.CurrentPassword, user.HashedPassword); err != nil {
+		respondError(ctx, http.StatusUnauthorized, errors.New("invalid credentials"))
+		return
+	}
+
+	user, err = server.setPassword(ctx, user, req.NewPassword)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	rsp, err := server.newLoginResponse(ctx, user)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
@@ -0,0 +1,526 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/auth0/go-auth0/management"
+	mockcache "github.com/awakim/immoblock-backend/cache/mock"
+	mockdb "github.com/awakim/immoblock-backend/db/mock"
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	identity "github.com/awakim/immoblock-backend/identity/auth0"
+	mockidentity "github.com/awakim/immoblock-backend/identity/mock"
+	mail "github.com/awakim/immoblock-backend/mail/local"
+	mockmail "github.com/awakim/immoblock-backend/mail/mock"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForgotPasswordAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		email         string
+		buildStubs    func(store *mockdb.MockStore, mailer *mockmail.MockMailer)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			email: user.Email,
+			buildStubs: func(store *mockdb.MockStore, mailer *mockmail.MockMailer) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				mailer.EXPECT().
+					Send(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, msg mail.Message) error {
+						require.Equal(t, user.Email, msg.To)
+						require.True(t, strings.Contains(msg.Body, "http://localhost:4200/reset-password?token="))
+						return nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "UnknownEmail",
+			email: "unknown@email.com",
+			buildStubs: func(store *mockdb.MockStore, mailer *mockmail.MockMailer) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, sql.ErrNoRows)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalError",
+			email: user.Email,
+			buildStubs: func(store *mockdb.MockStore, mailer *mockmail.MockMailer) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, sql.ErrConnDone)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidEmail",
+			email: "invalid",
+			buildStubs: func(store *mockdb.MockStore, mailer *mockmail.MockMailer) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			mailer := mockmail.NewMockMailer(ctrl)
+			cache.EXPECT().IsRateLimited(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			tc.buildStubs(store, mailer)
+
+			server := newTestServer(t, store, cache, userManager)
+			server.Mailer = mailer
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"email": tc.email})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/password/forgot", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.RemoteAddr = "127.0.0.1:12345"
+
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+// auth0Error is an error answered by the Auth0 management API.
+type auth0Error struct {
+	status  int
+	message string
+}
+
+func (e auth0Error) Status() int {
+	return e.status
+}
+
+func (e auth0Error) Error() string {
+	return e.message
+}
+
+// expectAuth0Password expects the password of the user to be replaced in Auth0.
+func expectAuth0Password(userManager *mockidentity.MockUserManagement, userID uuid.UUID, password string, err error) {
+	connection := identity.Connection
+	userManager.EXPECT().
+		Update(gomock.Eq(identity.UserID(userID)), gomock.Eq(&management.User{Password: &password, Connection: &connection})).
+		Times(1).
+		Return(err)
+}
+
+// expectPasswordUpdate expects the password of the user to be replaced by password and all the
+// tokens of the user to be revoked at the time of the change.
+func expectPasswordUpdate(t *testing.T, store *mockdb.MockStore, cache *mockcache.MockCache, user db.User, password string) {
+	var changedAt time.Time
+	store.EXPECT().
+		UpdateUserPassword(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.UpdateUserPasswordParams) (db.User, error) {
+			require.Equal(t, user.ID, arg.ID)
+			require.NoError(t, util.CheckPassword(password, arg.HashedPassword))
+			require.WithinDuration(t, time.Now(), arg.PasswordChangedAt, time.Second)
+			changedAt = arg.PasswordChangedAt
+
+			user.HashedPassword = arg.HashedPassword
+			user.PasswordChangedAt = arg.PasswordChangedAt
+			return user, nil
+		})
+	cache.EXPECT().
+		RevokeUserTokens(gomock.Any(), gomock.Eq(user.ID.String()), gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, _ string, issuedBefore time.Time, _ time.Duration) error {
+			require.Equal(t, changedAt, issuedBefore)
+			return nil
+		})
+}
+
+func TestResetPasswordAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	password := util.RandomString(10)
+
+	resetToken := func(server *Server) string {
+		_, resetToken, err := server.PasswordTokenMaker.CreateToken(token.TypePasswordReset, user.ID, token.Access{}, time.Minute)
+		require.NoError(t, err)
+		return resetToken
+	}
+
+	testCases := []struct {
+		name          string
+		token         func(server *Server) string
+		password      string
+		buildStubs    func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			token:    resetToken,
+			password: password,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				cache.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(1).Return(true, nil)
+				expectAuth0Password(userManager, user.ID, password, nil)
+				expectPasswordUpdate(t, store, cache, user, password)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "AlreadyUsed",
+			token:    resetToken,
+			password: password,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				cache.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().UpdateUserPassword(gomock.Any(), gomock.Any()).Times(0)
+				userManager.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+				cache.EXPECT().RevokeUserTokens(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "IssuedBeforePasswordChange",
+			token:    resetToken,
+			password: password,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				changed := user
+				changed.PasswordChangedAt = time.Now().UTC().Add(time.Second)
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(changed, nil)
+				cache.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdateUserPassword(gomock.Any(), gomock.Any()).Times(0)
+				userManager.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "AccessTokenRejected",
+			token: func(server *Server) string {
+				_, accessToken, err := server.TokenMaker.CreateToken(token.TypeAccess, user.ID, token.Access{}, time.Minute)
+				require.NoError(t, err)
+				return accessToken
+			},
+			password: password,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Times(0)
+				cache.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdateUserPassword(gomock.Any(), gomock.Any()).Times(0)
+				userManager.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "UserNotFound",
+			token:    resetToken,
+			password: password,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, sql.ErrNoRows)
+				cache.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdateUserPassword(gomock.Any(), gomock.Any()).Times(0)
+				userManager.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "TooShortPassword",
+			token:    resetToken,
+			password: "123",
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdateUserPassword(gomock.Any(), gomock.Any()).Times(0)
+				userManager.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "Auth0RejectsPassword",
+			token:    resetToken,
+			password: password,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				cache.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(1).Return(true, nil)
+				expectAuth0Password(userManager, user.ID, password, auth0Error{http.StatusBadRequest, "PasswordStrengthError: Password is too weak"})
+				store.EXPECT().UpdateUserPassword(gomock.Any(), gomock.Any()).Times(0)
+				cache.EXPECT().RevokeUserTokens(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				cache.EXPECT().ReleaseToken(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+				var rsp problem
+				require.NoError(t, json.NewDecoder(recorder.Body).Decode(&rsp))
+				require.Equal(t, CodeIdentityRejected, rsp.Code)
+			},
+		},
+		{
+			name:     "UpdateFailed",
+			token:    resetToken,
+			password: password,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				cache.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(1).Return(true, nil)
+				expectAuth0Password(userManager, user.ID, password, nil)
+				store.EXPECT().UpdateUserPassword(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, sql.ErrConnDone)
+				cache.EXPECT().RevokeUserTokens(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				cache.EXPECT().ReleaseToken(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			tc.buildStubs(store, cache, userManager)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"token": tc.token(server), "password": tc.password})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/password/reset", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestChangePasswordAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	currentPassword := util.RandomString(10)
+	hashedPassword, err := util.HashPassword(currentPassword)
+	require.NoError(t, err)
+	user.HashedPassword = hashedPassword
+	newPassword := util.RandomString(10)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"current_password": currentPassword, "new_password": newPassword},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				expectAuth0Password(userManager, user.ID, newPassword, nil)
+				expectPasswordUpdate(t, store, cache, user, newPassword)
+				expectUserAccess(store, user.ID, investorAccess)
+				cache.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp loginUserResponse
+				err := json.NewDecoder(recorder.Body).Decode(&rsp)
+				require.NoError(t, err)
+				require.NotEmpty(t, rsp.AccessToken)
+				require.NotEmpty(t, rsp.RefreshToken)
+				require.Equal(t, user.ID, rsp.User.UserID)
+				require.False(t, rsp.User.PasswordChangedAt.IsZero())
+			},
+		},
+		{
+			name: "WrongCurrentPassword",
+			body: gin.H{"current_password": "wrong-password", "new_password": newPassword},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().UpdateUserPassword(gomock.Any(), gomock.Any()).Times(0)
+				userManager.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+				cache.EXPECT().RevokeUserTokens(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "SamePassword",
+			body: gin.H{"current_password": currentPassword, "new_password": currentPassword},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdateUserPassword(gomock.Any(), gomock.Any()).Times(0)
+				userManager.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{"current_password": currentPassword, "new_password": newPassword},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				expectAuth0Password(userManager, user.ID, newPassword, nil)
+				store.EXPECT().UpdateUserPassword(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, sql.ErrConnDone)
+				cache.EXPECT().RevokeUserTokens(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "Auth0Error",
+			body: gin.H{"current_password": currentPassword, "new_password": newPassword},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				expectAuth0Password(userManager, user.ID, newPassword, errors.New("auth0 is unavailable"))
+				store.EXPECT().UpdateUserPassword(gomock.Any(), gomock.Any()).Times(0)
+				cache.EXPECT().RevokeUserTokens(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadGateway, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			tc.buildStubs(store, cache, userManager)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/password/change", bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestChangePasswordJWT(t *testing.T) {
+	user, _ := randomUser(t)
+	currentPassword := util.RandomString(10)
+	hashedPassword, err := util.HashPassword(currentPassword)
+	require.NoError(t, err)
+	user.HashedPassword = hashedPassword
+	newPassword := util.RandomString(10)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	cache := mockcache.NewMockCache(ctrl)
+	userManager := mockidentity.NewMockUserManagement(ctrl)
+
+	// the cache revokes the tokens issued before the cutoff set by the password change
+	var revokedBefore time.Time
+	cache.EXPECT().
+		IsRevoked(gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(_ context.Context, payload token.Payload) (bool, error) {
+			return payload.IssuedBefore(revokedBefore), nil
+		})
+	store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+	expectAuth0Password(userManager, user.ID, newPassword, nil)
+	store.EXPECT().UpdateUserPassword(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, arg db.UpdateUserPasswordParams) (db.User, error) {
+			user.PasswordChangedAt = arg.PasswordChangedAt
+			return user, nil
+		})
+	cache.EXPECT().RevokeUserTokens(gomock.Any(), gomock.Eq(user.ID.String()), gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, _ string, issuedBefore time.Time, _ time.Duration) error {
+			revokedBefore = issuedBefore
+			return nil
+		})
+	expectUserAccess(store, user.ID, investorAccess)
+	cache.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+	cache.EXPECT().ListSessions(gomock.Any(), gomock.Eq(user.ID.String())).Times(1).Return(nil, nil)
+
+	config := testConfig()
+	config.TokenMaker = tokenMakerJWT
+	server := newTestServerWithConfig(t, config, store, cache, userManager)
+
+	data, err := json.Marshal(gin.H{"current_password": currentPassword, "new_password": newPassword})
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/users/password/change", bytes.NewReader(data))
+	require.NoError(t, err)
+	addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp loginUserResponse
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&rsp))
+
+	// the JWT dates are whole seconds, the new token is issued within the second of the cutoff
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/users/sessions", nil)
+	require.NoError(t, err)
+	request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, rsp.AccessToken))
+
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+)
+
+var errPropertyNotFound = errors.New("property not found")
+
+type createPropertyRequest struct {
+	Name              string `json:"name" binding:"required,min=2"`
+	Description       string `json:"description" binding:"required"`
+	InitialBlockCount int64  `json:"initial_block_count" binding:"required,gt=0"`
+	PricePerBlock     int64  `json:"price_per_block" binding:"gte=0"`
+	Currency          string `json:"currency" binding:"required,len=3,uppercase"`
+}
+
+func (server *Server) createProperty(ctx *gin.Context) {
+	var req createPropertyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	arg := db.CreatePropertyParams{
+		Name:                req.Name,
+		Description:         req.Description,
+		InitialBlockCount:   req.InitialBlockCount,
+		RemainingBlockCount: req.InitialBlockCount,
+		PricePerBlock:       req.PricePerBlock,
+		Currency:            req.Currency,
+	}
+
+	property, err := server.Store.CreateProperty(ctx, arg)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, property)
+}
+
+type getPropertyRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getProperty(ctx *gin.Context) {
+	var req getPropertyRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	property, err := server.Store.GetProperty(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, errPropertyNotFound)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	// archived properties are hidden from the public catalog, as in the list
+	if property.Archived && !server.canManageProperties(ctx) {
+		respondError(ctx, http.StatusNotFound, errPropertyNotFound)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, property)
+}
+
+// canManageProperties tells if the optional access token of a public request grants the management
+// of the properties. An invalid or revoked token is treated as no token.
+func (server *Server) canManageProperties(ctx *gin.Context) bool {
+	accessToken := ""
+	if _, err := fmt.Sscanf(ctx.GetHeader(authorizationHeaderKey), "Bearer %s", &accessToken); err != nil {
+		return false
+	}
+
+	payload, err := server.TokenMaker.VerifyTokenFor(accessToken, token.TypeAccess)
+	if err != nil || !payload.HasPermission(db.PermissionPropertiesWrite) {
+		return false
+	}
+
+	revoked, err := server.Cache.IsRevoked(ctx, *payload)
+	return err == nil && !revoked
+}
+
+type listPropertiesRequest struct {
+	PageID    int32  `form:"page_id" binding:"required,min=1"`
+	PageSize  int32  `form:"page_size" binding:"required,min=5,max=20"`
+	Name      string `form:"name" binding:"max=64"`
+	Available bool   `form:"available"`
+}
+
+// likeEscaper escapes the ILIKE wildcards so user input is matched literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (server *Server) listProperties(ctx *gin.Context) {
+	var req listPropertiesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	arg := db.ListPropertiesParams{
+		NamePattern: "%" + likeEscaper.Replace(req.Name) + "%",
+		Limit:       req.PageSize,
+		Offset:      (req.PageID - 1) * req.PageSize,
+	}
+	if req.Available {
+		arg.MinRemainingBlockCount = 1
+	}
+
+	properties, err := server.Store.ListProperties(ctx, arg)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, properties)
+}
+
+type updatePropertyRequest struct {
+	Name          string `json:"name" binding:"required,min=2"`
+	Description   string `json:"description" binding:"required"`
+	PricePerBlock int64  `json:"price_per_block" binding:"gte=0"`
+}
+
+func (server *Server) updateProperty(ctx *gin.Context) {
+	var uri getPropertyRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var req updatePropertyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	arg := db.UpdatePropertyParams{
+		ID:            uri.ID,
+		Name:          req.Name,
+		Description:   req.Description,
+		PricePerBlock: req.PricePerBlock,
+	}
+
+	property, err := server.Store.UpdateProperty(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, property)
+}
+
+// archiveProperty hides a property from the public catalog. Properties are never
+// deleted since accounts keep referencing them.
+func (server *Server) archiveProperty(ctx *gin.Context) {
+	var req getPropertyRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	property, err := server.Store.ArchiveProperty(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, property)
+}
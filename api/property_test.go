@@ -0,0 +1,414 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockcache "github.com/awakim/immoblock-backend/cache/mock"
+	mockdb "github.com/awakim/immoblock-backend/db/mock"
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	mockidentity "github.com/awakim/immoblock-backend/identity/mock"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPropertyAPI(t *testing.T) {
+	property := randomProperty(t)
+	archived := randomProperty(t)
+	archived.Archived = true
+
+	testCases := []struct {
+		name          string
+		propertyID    int64
+		access        *token.Access
+		buildStubs    func(store *mockdb.MockStore, cache *mockcache.MockCache)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "OK",
+			propertyID: property.ID,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetProperty(gomock.Any(), gomock.Eq(property.ID)).Times(1).Return(property, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchProperty(t, recorder.Body, property)
+			},
+		},
+		{
+			name:       "Archived",
+			propertyID: archived.ID,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetProperty(gomock.Any(), gomock.Eq(archived.ID)).Times(1).Return(archived, nil)
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:       "ArchivedForInvestor",
+			propertyID: archived.ID,
+			access:     &investorAccess,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetProperty(gomock.Any(), gomock.Eq(archived.ID)).Times(1).Return(archived, nil)
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:       "ArchivedForPropertyManager",
+			propertyID: archived.ID,
+			access:     &propertyManagerAccess,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetProperty(gomock.Any(), gomock.Eq(archived.ID)).Times(1).Return(archived, nil)
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchProperty(t, recorder.Body, archived)
+			},
+		},
+		{
+			name:       "ArchivedForRevokedToken",
+			propertyID: archived.ID,
+			access:     &propertyManagerAccess,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetProperty(gomock.Any(), gomock.Eq(archived.ID)).Times(1).Return(archived, nil)
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(true, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:       "NotFound",
+			propertyID: property.ID,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetProperty(gomock.Any(), gomock.Eq(property.ID)).Times(1).Return(db.Property{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:       "InternalError",
+			propertyID: property.ID,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetProperty(gomock.Any(), gomock.Eq(property.ID)).Times(1).Return(db.Property{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:       "InvalidID",
+			propertyID: 0,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetProperty(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			tc.buildStubs(store, cache)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/properties/%d", tc.propertyID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			if tc.access != nil {
+				addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, uuid.New(), *tc.access, time.Minute)
+			}
+
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListPropertiesAPI(t *testing.T) {
+	n := 5
+	properties := make([]db.Property, n)
+	for i := 0; i < n; i++ {
+		properties[i] = randomProperty(t)
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: fmt.Sprintf("page_id=1&page_size=%d", n),
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListPropertiesParams{
+					NamePattern: "%%",
+					Limit:       int32(n),
+					Offset:      0,
+				}
+				store.EXPECT().ListProperties(gomock.Any(), gomock.Eq(arg)).Times(1).Return(properties, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "Filtered",
+			query: fmt.Sprintf("page_id=2&page_size=%d&name=50%%25_off&available=true", n),
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListPropertiesParams{
+					NamePattern:            `%50\%\_off%`,
+					MinRemainingBlockCount: 1,
+					Limit:                  int32(n),
+					Offset:                 int32(n),
+				}
+				store.EXPECT().ListProperties(gomock.Any(), gomock.Eq(arg)).Times(1).Return(properties, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidPageSize",
+			query: "page_id=1&page_size=100000",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListProperties(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalError",
+			query: fmt.Sprintf("page_id=1&page_size=%d", n),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListProperties(gomock.Any(), gomock.Any()).Times(1).Return([]db.Property{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/properties?"+tc.query, nil)
+			require.NoError(t, err)
+
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreatePropertyAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	property := randomProperty(t)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore, cache *mockcache.MockCache)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"name":                property.Name,
+				"description":         property.Description,
+				"initial_block_count": property.InitialBlockCount,
+				"price_per_block":     property.PricePerBlock,
+				"currency":            property.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, propertyManagerAccess, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				arg := db.CreatePropertyParams{
+					Name:                property.Name,
+					Description:         property.Description,
+					InitialBlockCount:   property.InitialBlockCount,
+					RemainingBlockCount: property.InitialBlockCount,
+					PricePerBlock:       property.PricePerBlock,
+					Currency:            property.Currency,
+				}
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().CreateProperty(gomock.Any(), gomock.Eq(arg)).Times(1).Return(property, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchProperty(t, recorder.Body, property)
+			},
+		},
+		{
+			name: "MissingPermission",
+			body: gin.H{
+				"name":                property.Name,
+				"description":         property.Description,
+				"initial_block_count": property.InitialBlockCount,
+				"price_per_block":     property.PricePerBlock,
+				"currency":            property.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().CreateProperty(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			body: gin.H{
+				"name":                property.Name,
+				"description":         property.Description,
+				"initial_block_count": property.InitialBlockCount,
+				"price_per_block":     property.PricePerBlock,
+				"currency":            property.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateProperty(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidBlockCount",
+			body: gin.H{
+				"name":                property.Name,
+				"description":         property.Description,
+				"initial_block_count": -1,
+				"price_per_block":     property.PricePerBlock,
+				"currency":            property.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, propertyManagerAccess, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().CreateProperty(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			tc.buildStubs(store, cache)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/properties", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.TokenMaker)
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestArchivePropertyAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	property := randomProperty(t)
+	archived := property
+	archived.Archived = true
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	cache := mockcache.NewMockCache(ctrl)
+	userManager := mockidentity.NewMockUserManagement(ctrl)
+	cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+	store.EXPECT().ArchiveProperty(gomock.Any(), gomock.Eq(property.ID)).Times(1).Return(archived, nil)
+
+	server := newTestServer(t, store, cache, userManager)
+	recorder := httptest.NewRecorder()
+
+	url := fmt.Sprintf("/properties/%d", property.ID)
+	request, err := http.NewRequest(http.MethodDelete, url, nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, user.ID, propertyManagerAccess, time.Minute)
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	requireBodyMatchProperty(t, recorder.Body, archived)
+}
+
+func requireBodyMatchProperty(t *testing.T, body *bytes.Buffer, property db.Property) {
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotProperty db.Property
+	err = json.Unmarshal(data, &gotProperty)
+	require.NoError(t, err)
+	require.Equal(t, property.ID, gotProperty.ID)
+	require.Equal(t, property.Name, gotProperty.Name)
+	require.Equal(t, property.Description, gotProperty.Description)
+	require.Equal(t, property.InitialBlockCount, gotProperty.InitialBlockCount)
+	require.Equal(t, property.RemainingBlockCount, gotProperty.RemainingBlockCount)
+	require.Equal(t, property.PricePerBlock, gotProperty.PricePerBlock)
+	require.Equal(t, property.Currency, gotProperty.Currency)
+	require.Equal(t, property.Archived, gotProperty.Archived)
+}
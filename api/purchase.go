@@ -0,0 +1,55 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+)
+
+type createPurchaseRequest struct {
+	Amount int64 `json:"amount" binding:"required,gt=0"`
+}
+
+func (server *Server) createPurchase(ctx *gin.Context) {
+	var uri getPropertyRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var req createPurchaseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.PurchaseTxParams{
+		UserID:     authPayload.UserID,
+		PropertyID: uri.ID,
+		Amount:     req.Amount,
+	}
+
+	result, err := server.Store.PurchaseTx(ctx, arg)
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			respondError(ctx, http.StatusNotFound, err)
+		case errors.Is(err, db.ErrPropertyNotForSale):
+			respondError(ctx, http.StatusForbidden, err)
+		case errors.Is(err, db.ErrInsufficientBlocks):
+			respondError(ctx, http.StatusConflict, err)
+		case errors.Is(err, db.ErrCostTooLarge):
+			respondError(ctx, http.StatusBadRequest, err)
+		default:
+			respondError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
@@ -44,20 +44,18 @@ func (server *Server) loginRateLimiter(ctx *gin.Context) {
 
 	IP, err := getIP(ctx)
 	if err != nil {
-		ctx.AbortWithStatusJSON(http.StatusNotFound, errorResponse(err))
+		abortWithError(ctx, http.StatusNotFound, err)
 		return
 	}
 
 	b, err := server.Cache.IsRateLimited(ctx, IP)
 	if err != nil {
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+		abortWithError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
 	if b {
-		ctx.AbortWithStatusJSON(
-			http.StatusTooManyRequests,
-			errorResponse(errors.New("too many requests. Try again in 15 minutes")))
+		abortWithError(ctx, http.StatusTooManyRequests, errors.New("too many requests. Try again in 15 minutes"))
 		return
 	}
 
@@ -2,10 +2,13 @@ package api
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
+	cache "github.com/awakim/immoblock-backend/cache/redis"
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	"github.com/awakim/immoblock-backend/token"
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
 	"github.com/go-redis/redis/v8"
 )
 
@@ -21,57 +24,69 @@ type refreshResponse struct {
 func (server *Server) refresh(ctx *gin.Context) {
 	var req refreshRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		var verr validator.ValidationErrors
-		if errors.As(err, &verr) {
-			ctx.JSON(http.StatusBadRequest, gin.H{"errors": ValidationError(verr)})
-			return
-		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errorResponse(err)})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	refreshToken, err := server.TokenMaker.VerifyToken(req.RefreshTokenString)
+	refreshToken, err := server.TokenMaker.VerifyTokenFor(req.RefreshTokenString, token.TypeRefresh)
 	if err != nil {
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+		abortWithError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 
 	revoked, err := server.Cache.IsRevoked(ctx, *refreshToken)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 	if revoked {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("user has been signed out")))
+		respondError(ctx, http.StatusUnauthorized, errors.New("user has been signed out"))
 		return
 	}
 
-	prevTokenID := refreshToken.ID.String()
-	if prevTokenID != "" {
-		if err := server.Cache.DeleteRefreshToken(ctx, refreshToken.UserID.String(), prevTokenID); err != nil {
-			if err == redis.Nil {
-				ctx.JSON(http.StatusNotFound, errorResponse(errors.New("unable to refresh access")))
-				return
-			}
-			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-			return
-		}
+	// the roles are read again so that the new tokens carry the latest grants
+	access, err := server.userAccess(ctx, refreshToken.UserID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
 	}
 
+	// the session outlives its tokens, the new pair belongs to the session of the refresh token
 	newAT, newATST, newRT, newRTST, err := server.TokenMaker.CreateTokenPair(
 		refreshToken.UserID,
-		refreshToken.IsAdmin,
+		refreshToken.SessionID,
+		access,
 		server.Config.AccessTokenDuration,
 		server.Config.RefreshTokenDuration,
 	)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	err = server.Cache.SetTokenData(ctx, newAT, server.Config.AccessTokenDuration, newRT, server.Config.RefreshTokenDuration)
+	_, err = server.Cache.RefreshSession(ctx, *refreshToken, newAT, server.Config.AccessTokenDuration, newRT, server.Config.RefreshTokenDuration)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		if err == cache.ErrRefreshTokenReused {
+			// the token family has been revoked, the reuse is recorded for the user and the support team
+			ip, _ := getIP(ctx)
+			_, err = server.Store.CreateAuditEvent(ctx, db.CreateAuditEventParams{
+				UserID:  refreshToken.UserID,
+				Kind:    db.AuditRefreshTokenReused,
+				Ip:      ip,
+				Details: fmt.Sprintf("refresh token %s of session %s", refreshToken.ID, refreshToken.SessionID),
+			})
+			if err != nil {
+				respondError(ctx, http.StatusInternalServerError, err)
+				return
+			}
+			respondError(ctx, http.StatusUnauthorized, cache.ErrRefreshTokenReused)
+			return
+		}
+		if err == redis.Nil {
+			respondError(ctx, http.StatusNotFound, errors.New("unable to refresh access"))
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockcache "github.com/awakim/immoblock-backend/cache/mock"
+	cache "github.com/awakim/immoblock-backend/cache/redis"
+	mockdb "github.com/awakim/immoblock-backend/db/mock"
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	mockidentity "github.com/awakim/immoblock-backend/identity/mock"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	session := cache.Session{ID: uuid.NewString(), UserID: user.ID.String()}
+
+	testCases := []struct {
+		name          string
+		tokenType     token.Type
+		buildStubs    func(store *mockdb.MockStore, cache *mockcache.MockCache)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			tokenType: token.TypeRefresh,
+			buildStubs: func(store *mockdb.MockStore, cacheMock *mockcache.MockCache) {
+				cacheMock.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				expectUserAccess(store, user.ID, adminAccess)
+				cacheMock.EXPECT().
+					RefreshSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, _ token.Payload, at token.Payload, _ time.Duration, rt token.Payload, _ time.Duration) (cache.Session, error) {
+						// the roles granted since the login are carried by the new tokens
+						require.Equal(t, adminAccess, at.Access)
+						require.Equal(t, adminAccess, rt.Access)
+						return session, nil
+					})
+				store.EXPECT().CreateAuditEvent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp refreshResponse
+				err := json.NewDecoder(recorder.Body).Decode(&rsp)
+				require.NoError(t, err)
+				require.NotEmpty(t, rsp.AccessToken)
+				require.NotEmpty(t, rsp.RefreshToken)
+			},
+		},
+		{
+			name:      "AccessError",
+			tokenType: token.TypeRefresh,
+			buildStubs: func(store *mockdb.MockStore, cacheMock *mockcache.MockCache) {
+				cacheMock.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().ListUserRoles(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(nil, sql.ErrConnDone)
+				cacheMock.EXPECT().
+					RefreshSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:      "Revoked",
+			tokenType: token.TypeRefresh,
+			buildStubs: func(store *mockdb.MockStore, cacheMock *mockcache.MockCache) {
+				cacheMock.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(true, nil)
+				cacheMock.EXPECT().
+					RefreshSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "UnknownSession",
+			tokenType: token.TypeRefresh,
+			buildStubs: func(store *mockdb.MockStore, cacheMock *mockcache.MockCache) {
+				cacheMock.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				expectUserAccess(store, user.ID, adminAccess)
+				cacheMock.EXPECT().
+					RefreshSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(cache.Session{}, redis.Nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "Reused",
+			tokenType: token.TypeRefresh,
+			buildStubs: func(store *mockdb.MockStore, cacheMock *mockcache.MockCache) {
+				cacheMock.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				expectUserAccess(store, user.ID, adminAccess)
+				cacheMock.EXPECT().
+					RefreshSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(cache.Session{}, cache.ErrRefreshTokenReused)
+				store.EXPECT().
+					CreateAuditEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateAuditEventParams) (db.AuditEvent, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.Equal(t, db.AuditRefreshTokenReused, arg.Kind)
+						require.Equal(t, "127.0.0.1", arg.Ip)
+						// the session is already gone, it is named after the refresh token
+						require.Contains(t, arg.Details, session.ID)
+						return db.AuditEvent{ID: 1, UserID: arg.UserID, Kind: arg.Kind}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "AccessToken",
+			tokenType: token.TypeAccess,
+			buildStubs: func(store *mockdb.MockStore, cacheMock *mockcache.MockCache) {
+				cacheMock.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(0)
+				cacheMock.EXPECT().
+					RefreshSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "ReusedAuditError",
+			tokenType: token.TypeRefresh,
+			buildStubs: func(store *mockdb.MockStore, cacheMock *mockcache.MockCache) {
+				cacheMock.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				expectUserAccess(store, user.ID, adminAccess)
+				cacheMock.EXPECT().
+					RefreshSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(cache.Session{}, cache.ErrRefreshTokenReused)
+				store.EXPECT().CreateAuditEvent(gomock.Any(), gomock.Any()).Times(1).Return(db.AuditEvent{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cacheMock := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			tc.buildStubs(store, cacheMock)
+
+			server := newTestServer(t, store, cacheMock, userManager)
+			recorder := httptest.NewRecorder()
+
+			_, accessToken, _, refreshToken, err := server.TokenMaker.CreateTokenPair(user.ID, session.ID, token.Access{}, time.Minute, time.Minute)
+			require.NoError(t, err)
+			if tc.tokenType == token.TypeAccess {
+				refreshToken = accessToken
+			}
+			data, err := json.Marshal(gin.H{"refresh_token": refreshToken})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/refresh", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.RemoteAddr = "127.0.0.1:12345"
+
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	requestIDHeader = "X-Request-ID"
+	requestIDKey    = "X-Request-ID"
+)
+
+// request IDs given by the client are kept when they are short and printable
+var requestIDRegex = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// requestID tags the request with the ID given by the client or a new one, and sends it back in the response headers.
+func requestID(ctx *gin.Context) {
+	id := ctx.GetHeader(requestIDHeader)
+	if !requestIDRegex.MatchString(id) {
+		id = uuid.NewString()
+	}
+
+	ctx.Set(requestIDKey, id)
+	ctx.Header(requestIDHeader, id)
+	ctx.Next()
+}
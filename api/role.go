@@ -0,0 +1,151 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var errRoleNotFound = errors.New("role not found")
+
+// userAccess reads the roles of the user and the permissions they grant, to be carried by the tokens.
+func (server *Server) userAccess(ctx *gin.Context, userID uuid.UUID) (token.Access, error) {
+	roles, err := server.Store.ListUserRoles(ctx, userID)
+	if err != nil {
+		return token.Access{}, err
+	}
+
+	permissions, err := server.Store.ListUserPermissions(ctx, userID)
+	if err != nil {
+		return token.Access{}, err
+	}
+
+	access := token.Access{
+		Roles:       roles,
+		Permissions: permissions,
+	}
+	return access, nil
+}
+
+func (server *Server) listRoles(ctx *gin.Context) {
+	roles, err := server.Store.ListRoles(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, roles)
+}
+
+type userRolesResponse struct {
+	UserID      uuid.UUID `json:"user_id"`
+	Roles       []string  `json:"roles"`
+	Permissions []string  `json:"permissions"`
+}
+
+// userRolesResponse answers with the roles of the user once it is known to exist.
+func (server *Server) userRolesResponse(ctx *gin.Context, userID uuid.UUID) {
+	access, err := server.userAccess(ctx, userID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, userRolesResponse{
+		UserID:      userID,
+		Roles:       access.Roles,
+		Permissions: access.Permissions,
+	})
+}
+
+func (server *Server) listUserRoles(ctx *gin.Context) {
+	user, ok := server.getAdminUser(ctx)
+	if !ok {
+		return
+	}
+
+	server.userRolesResponse(ctx, user.ID)
+}
+
+type grantUserRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// grantUserRole grants a role to a user. The new permissions are carried by the tokens of the user
+// from their next login or refresh.
+func (server *Server) grantUserRole(ctx *gin.Context) {
+	user, ok := server.getAdminUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req grantUserRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	role, err := server.Store.GetRole(ctx, req.Role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, errRoleNotFound)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = server.Store.AddUserRole(ctx, db.AddUserRoleParams{
+		UserID: user.ID,
+		Role:   role.Name,
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	server.userRolesResponse(ctx, user.ID)
+}
+
+type revokeUserRoleRequest struct {
+	UserID string `uri:"id" binding:"required,uuid"`
+	Role   string `uri:"role" binding:"required"`
+}
+
+// revokeUserRole revokes a role of a user. The user is signed out everywhere, so that the tokens
+// carrying the revoked permissions cannot be used any longer.
+func (server *Server) revokeUserRole(ctx *gin.Context) {
+	var req revokeUserRoleRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	userID := uuid.MustParse(req.UserID)
+
+	n, err := server.Store.RemoveUserRole(ctx, db.RemoveUserRoleParams{
+		UserID: userID,
+		Role:   req.Role,
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if n == 0 {
+		respondError(ctx, http.StatusNotFound, errRoleNotFound)
+		return
+	}
+
+	err = server.Cache.RevokeUserTokens(ctx, userID.String(), time.Now().UTC(), server.Config.RefreshTokenDuration)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	server.userRolesResponse(ctx, userID)
+}
@@ -0,0 +1,379 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockcache "github.com/awakim/immoblock-backend/cache/mock"
+	mockdb "github.com/awakim/immoblock-backend/db/mock"
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	mockidentity "github.com/awakim/immoblock-backend/identity/mock"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// expectUserAccess expects the roles and the permissions of the user to be read for new tokens
+func expectUserAccess(store *mockdb.MockStore, userID uuid.UUID, access token.Access) {
+	store.EXPECT().ListUserRoles(gomock.Any(), gomock.Eq(userID)).Times(1).Return(access.Roles, nil)
+	store.EXPECT().ListUserPermissions(gomock.Any(), gomock.Eq(userID)).Times(1).Return(access.Permissions, nil)
+}
+
+func TestRequirePermission(t *testing.T) {
+	userID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		access      token.Access
+		permissions []string
+		code        int
+	}{
+		{
+			name:        "OK",
+			access:      propertyManagerAccess,
+			permissions: []string{db.PermissionPropertiesWrite},
+			code:        http.StatusOK,
+		},
+		{
+			name:        "AllPermissions",
+			access:      propertyManagerAccess,
+			permissions: []string{db.PermissionPropertiesWrite, db.PermissionDistributionsWrite},
+			code:        http.StatusOK,
+		},
+		{
+			name:        "MissingPermission",
+			access:      propertyManagerAccess,
+			permissions: []string{db.PermissionPropertiesWrite, db.PermissionUsersRead},
+			code:        http.StatusForbidden,
+		},
+		{
+			name:        "RoleWithoutPermission",
+			access:      token.Access{Roles: []string{db.RoleAdmin}},
+			permissions: []string{db.PermissionPropertiesWrite},
+			code:        http.StatusForbidden,
+		},
+		{
+			name:        "NoAccess",
+			permissions: []string{db.PermissionPropertiesWrite},
+			code:        http.StatusForbidden,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			server := newTestServer(t, store, cache, userManager)
+
+			authPath := "/permission"
+			server.Router.GET(
+				authPath,
+				auth(server.TokenMaker),
+				requirePermission(tc.permissions...),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, authPath, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, userID, tc.access, time.Minute)
+
+			server.Router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.code, recorder.Code)
+		})
+	}
+}
+
+func TestListRolesAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	roles := []db.Role{
+		{Name: db.RoleAdmin, Description: "Has every permission"},
+		{Name: db.RoleInvestor, Description: "Buys property tokens and manages their own accounts"},
+	}
+
+	testCases := []struct {
+		name          string
+		access        token.Access
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			access: adminAccess,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListRoles(gomock.Any()).Times(1).Return(roles, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp []db.Role
+				err := json.NewDecoder(recorder.Body).Decode(&rsp)
+				require.NoError(t, err)
+				require.Equal(t, roles, rsp)
+			},
+		},
+		{
+			name:   "MissingPermission",
+			access: propertyManagerAccess,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListRoles(gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "InternalError",
+			access: adminAccess,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListRoles(gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/admin/roles", nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, admin.ID, tc.access, time.Minute)
+
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func requireBodyMatchUserRoles(t *testing.T, body *bytes.Buffer, userID uuid.UUID, access token.Access) {
+	var rsp userRolesResponse
+	err := json.NewDecoder(body).Decode(&rsp)
+	require.NoError(t, err)
+	require.Equal(t, userID, rsp.UserID)
+	require.Equal(t, access.Roles, rsp.Roles)
+	require.Equal(t, access.Permissions, rsp.Permissions)
+}
+
+func TestGrantUserRoleAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	user, _ := randomUser(t)
+	role := db.Role{Name: db.RolePropertyManager}
+
+	testCases := []struct {
+		name          string
+		userID        string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			userID: user.ID.String(),
+			body:   gin.H{"role": role.Name},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.AddUserRoleParams{UserID: user.ID, Role: role.Name}
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetRole(gomock.Any(), gomock.Eq(role.Name)).Times(1).Return(role, nil)
+				store.EXPECT().AddUserRole(gomock.Any(), gomock.Eq(arg)).Times(1).Return(nil)
+				expectUserAccess(store, user.ID, propertyManagerAccess)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchUserRoles(t, recorder.Body, user.ID, propertyManagerAccess)
+			},
+		},
+		{
+			name:   "UserNotFound",
+			userID: user.ID.String(),
+			body:   gin.H{"role": role.Name},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().AddUserRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "RoleNotFound",
+			userID: user.ID.String(),
+			body:   gin.H{"role": "owner"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetRole(gomock.Any(), gomock.Eq("owner")).Times(1).Return(db.Role{}, sql.ErrNoRows)
+				store.EXPECT().AddUserRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "InvalidUserID",
+			userID: "invalid",
+			body:   gin.H{"role": role.Name},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "MissingRole",
+			userID: user.ID.String(),
+			body:   gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "InternalError",
+			userID: user.ID.String(),
+			body:   gin.H{"role": role.Name},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetRole(gomock.Any(), gomock.Eq(role.Name)).Times(1).Return(role, nil)
+				store.EXPECT().AddUserRole(gomock.Any(), gomock.Any()).Times(1).Return(sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/admin/users/%s/roles", tc.userID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, admin.ID, adminAccess, time.Minute)
+
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestRevokeUserRoleAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		role          string
+		buildStubs    func(store *mockdb.MockStore, cache *mockcache.MockCache)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			role: db.RolePropertyManager,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				arg := db.RemoveUserRoleParams{UserID: user.ID, Role: db.RolePropertyManager}
+				store.EXPECT().RemoveUserRole(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(1), nil)
+				cache.EXPECT().RevokeUserTokens(gomock.Any(), gomock.Eq(user.ID.String()), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+				expectUserAccess(store, user.ID, investorAccess)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchUserRoles(t, recorder.Body, user.ID, investorAccess)
+			},
+		},
+		{
+			name: "NotGranted",
+			role: db.RoleSupport,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().RemoveUserRole(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				cache.EXPECT().RevokeUserTokens(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "RevokeTokensError",
+			role: db.RolePropertyManager,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().RemoveUserRole(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+				cache.EXPECT().RevokeUserTokens(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			tc.buildStubs(store, cache)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/admin/users/%s/roles/%s", user.ID, tc.role)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, admin.ID, adminAccess, time.Minute)
+
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -9,35 +9,92 @@ import (
 	"github.com/awakim/immoblock-backend/config"
 	db "github.com/awakim/immoblock-backend/db/sqlc"
 	identity "github.com/awakim/immoblock-backend/identity/auth0"
+	"github.com/awakim/immoblock-backend/kyc"
+	mail "github.com/awakim/immoblock-backend/mail/local"
+	payment "github.com/awakim/immoblock-backend/payment/local"
+	storage "github.com/awakim/immoblock-backend/storage/local"
 	"github.com/awakim/immoblock-backend/token"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 )
 
+// Formats of the access and refresh tokens
+const (
+	tokenMakerPaseto = "paseto"
+	tokenMakerJWT    = "jwt"
+)
+
 // Server serves HTTP requests for our banking service.
 type Server struct {
-	Config      config.Config
-	Store       db.Store
-	Cache       cache.Cache
-	TokenMaker  token.Maker
-	Router      *gin.Engine
-	UserManager identity.UserManager
+	Config             config.Config
+	Store              db.Store
+	Cache              cache.Cache
+	TokenMaker         token.Maker
+	Router             *gin.Engine
+	UserManager        identity.UserManager
+	PaymentProvider    payment.Provider
+	Mailer             mail.Mailer
+	DocumentStorage    storage.Storage
+	KYCProvider        kyc.Provider
+	EmailTokenMaker    token.Maker
+	PasswordTokenMaker token.Maker
+	MFATokenMaker      token.Maker
+}
+
+// newTokenMaker creates the maker of the access and refresh tokens. The format is chosen with
+// TokenMaker, PASETO by default. The tokens are signed with public keys when keys are configured.
+func newTokenMaker(config config.Config) (token.Maker, error) {
+	switch config.TokenMaker {
+	case tokenMakerPaseto, "":
+		if len(config.TokenKeys) > 0 {
+			return token.NewPasetoPublicMaker(config.TokenKeys, config.TokenIssuer, config.TokenAudience)
+		}
+		return token.NewPasetoMaker(config.TokenSymmetricKey, config.TokenIssuer, config.TokenAudience)
+	case tokenMakerJWT:
+		if len(config.TokenKeys) > 0 {
+			return token.NewJWTPublicMaker(config.TokenKeys, config.TokenIssuer, config.TokenAudience)
+		}
+		return token.NewJWTMaker(config.TokenSymmetricKey, config.TokenIssuer, config.TokenAudience)
+	}
+	return nil, fmt.Errorf("unknown token maker %q", config.TokenMaker)
 }
 
 // NewServer creates a new HTTP server and set up routing.
-func NewServer(config config.Config, store db.Store, cache cache.Cache, userManager identity.UserManager) (*Server, error) {
-	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+func NewServer(config config.Config, store db.Store, cache cache.Cache, userManager identity.UserManager, paymentProvider payment.Provider, mailer mail.Mailer, documentStorage storage.Storage, kycProvider kyc.Provider) (*Server, error) {
+	tokenMaker, err := newTokenMaker(config)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
 
+	emailTokenMaker, err := token.NewPasetoMaker(token.DeriveKey(config.TokenSymmetricKey, "email-verification"), config.TokenIssuer, config.TokenAudience)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create email token maker: %w", err)
+	}
+
+	passwordTokenMaker, err := token.NewPasetoMaker(token.DeriveKey(config.TokenSymmetricKey, "password-reset"), config.TokenIssuer, config.TokenAudience)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create password token maker: %w", err)
+	}
+
+	mfaTokenMaker, err := token.NewPasetoMaker(token.DeriveKey(config.TokenSymmetricKey, "mfa-challenge"), config.TokenIssuer, config.TokenAudience)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create mfa token maker: %w", err)
+	}
+
 	server := &Server{
-		Config:      config,
-		Store:       store,
-		Cache:       cache,
-		TokenMaker:  tokenMaker,
-		UserManager: userManager,
+		Config:             config,
+		Store:              store,
+		Cache:              cache,
+		TokenMaker:         tokenMaker,
+		UserManager:        userManager,
+		PaymentProvider:    paymentProvider,
+		Mailer:             mailer,
+		DocumentStorage:    documentStorage,
+		KYCProvider:        kycProvider,
+		EmailTokenMaker:    emailTokenMaker,
+		PasswordTokenMaker: passwordTokenMaker,
+		MFATokenMaker:      mfaTokenMaker,
 	}
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
@@ -48,6 +105,9 @@ func NewServer(config config.Config, store db.Store, cache cache.Cache, userMana
 			}
 			return name
 		})
+		if err := registerValidations(v); err != nil {
+			return nil, fmt.Errorf("cannot register validations: %w", err)
+		}
 	}
 
 	server.setupRouter()
@@ -57,23 +117,94 @@ func NewServer(config config.Config, store db.Store, cache cache.Cache, userMana
 func (server *Server) setupRouter() {
 
 	router := gin.Default()
-	router.Use(CORS(server.Config.CorsOrigins))
+	router.Use(requestID, CORS(server.Config.CorsOrigins))
 
 	router.POST("/users", server.createUser)
 	router.POST("/users/login", server.loginRateLimiter, server.loginUser)
+	router.POST("/users/login/mfa", server.loginRateLimiter, server.loginUserMFA)
 	router.POST("/users/refresh", server.refresh)
+	router.POST("/users/verify-email", server.verifyEmail)
+	router.POST("/users/verify-email/resend", server.loginRateLimiter, server.resendVerificationEmail)
+	router.POST("/users/password/forgot", server.loginRateLimiter, server.forgotPassword)
+	router.POST("/users/password/reset", server.resetPassword)
+
+	router.GET("/.well-known/paseto-keys", server.listTokenKeys)
+
+	router.POST("/webhooks/kyc", server.kycWebhook)
+
+	router.GET("/properties", server.listProperties)
+	router.GET("/properties/:id", server.getProperty)
+	router.GET("/properties/:id/orderbook", server.getOrderBook)
 
 	authRoutes := router.Group("/").Use(auth(server.TokenMaker), server.revoked)
 
 	authRoutes.POST("/accounts", server.createAccount)
 	authRoutes.GET("/accounts/:id", server.getAccount)
 	authRoutes.GET("/accounts", server.listAccounts)
+	authRoutes.GET("/accounts/:id/entries", server.listAccountEntries)
+	authRoutes.GET("/accounts/:id/transfers", server.listAccountTransfers)
+
+	authRoutes.POST("/transfers", server.requireVerifiedEmail, server.requireApprovedIdentity, server.transferStepUp, server.idempotent, server.createTransfer)
+
+	authRoutes.POST("/properties/:id/purchases", server.requireApprovedIdentity, server.idempotent, server.createPurchase)
+	authRoutes.POST("/properties/:id/orders", server.requireVerifiedEmail, server.requireApprovedIdentity, server.idempotent, server.placeOrder)
+
+	authRoutes.GET("/orders", server.listOrders)
+	authRoutes.DELETE("/orders/:id", server.cancelOrder)
+
+	authRoutes.GET("/wallets", server.listWallets)
+	authRoutes.GET("/wallets/:id/entries", server.listWalletEntries)
+	authRoutes.POST("/wallets/deposits", server.idempotent, server.createDeposit)
+	authRoutes.POST("/wallets/withdrawals", server.requireApprovedIdentity, server.idempotent, server.createWithdrawal)
 
-	authRoutes.POST("/transfers", server.createTransfer)
+	authRoutes.GET("/users/payouts", server.listPayouts)
+	authRoutes.GET("/users/activity", server.listActivity)
 
 	authRoutes.GET("/users/info", server.getUserInfo)
 	authRoutes.POST("/users/info", server.createUserInfo)
+	authRoutes.PATCH("/users/info", server.updateUserInfo)
+	authRoutes.GET("/users/info/documents", server.listIdentityDocuments)
+	authRoutes.POST("/users/info/documents", server.uploadIdentityDocument)
+	authRoutes.POST("/users/info/verification", server.submitVerification)
 	authRoutes.POST("/users/logout", server.logoutUser)
+	authRoutes.GET("/users/sessions", server.listSessions)
+	authRoutes.DELETE("/users/sessions", server.deleteSessions)
+	authRoutes.DELETE("/users/sessions/:id", server.deleteSession)
+	authRoutes.POST("/users/password/change", server.changePassword)
+	authRoutes.POST("/users/mfa/enroll", server.enrollMFA)
+	authRoutes.POST("/users/mfa/confirm", server.confirmMFA)
+
+	// the staff manage the catalog next to the public routes of the properties
+	staffRoutes := router.Group("/").Use(auth(server.TokenMaker), server.revoked)
+
+	staffRoutes.POST("/properties", requirePermission(db.PermissionPropertiesWrite), server.createProperty)
+	staffRoutes.PUT("/properties/:id", requirePermission(db.PermissionPropertiesWrite), server.updateProperty)
+	staffRoutes.DELETE("/properties/:id", requirePermission(db.PermissionPropertiesWrite), server.archiveProperty)
+	staffRoutes.POST("/properties/:id/distributions", requirePermission(db.PermissionDistributionsWrite), server.idempotent, server.createDistribution)
+	staffRoutes.GET("/properties/:id/distributions", requirePermission(db.PermissionDistributionsWrite), server.listDistributions)
+
+	// the back-office API is only reachable with the admin access, each route checks its own permission on top
+	adminRoutes := router.Group("/admin").Use(auth(server.TokenMaker), server.revoked, requirePermission(db.PermissionAdminAccess))
+
+	adminRoutes.GET("/users", requirePermission(db.PermissionUsersRead), server.searchUsers)
+	adminRoutes.GET("/users/:id", requirePermission(db.PermissionUsersRead), server.getUser)
+	adminRoutes.GET("/users/:id/accounts", requirePermission(db.PermissionUsersRead), server.listUserAccounts)
+	adminRoutes.GET("/users/:id/transfers", requirePermission(db.PermissionUsersRead), server.listUserTransfers)
+	adminRoutes.GET("/users/:id/audit-events", requirePermission(db.PermissionAuditRead), server.listUserAuditEvents)
+	adminRoutes.GET("/users/:id/info/changes", requirePermission(db.PermissionUsersRead), server.listUserInfoChanges)
+	adminRoutes.POST("/users/:id/lock", requirePermission(db.PermissionUsersWrite), server.lockUser)
+	adminRoutes.POST("/users/:id/unlock", requirePermission(db.PermissionUsersWrite), server.unlockUser)
+	adminRoutes.POST("/users/:id/logout", requirePermission(db.PermissionUsersWrite), server.signOutUser)
+	adminRoutes.POST("/users/:id/verification", requirePermission(db.PermissionKYCReview), server.transitionVerification)
+	adminRoutes.GET("/users/:id/verification/history", requirePermission(db.PermissionKYCReview), server.listVerificationHistory)
+	adminRoutes.GET("/users/:id/kyc-checks", requirePermission(db.PermissionKYCReview), server.listKYCChecks)
+	adminRoutes.GET("/users/:id/documents", requirePermission(db.PermissionKYCDocumentsRead), server.listUserIdentityDocuments)
+	adminRoutes.GET("/users/:id/documents/:document_id", requirePermission(db.PermissionKYCDocumentsRead), server.downloadIdentityDocument)
+
+	adminRoutes.GET("/roles", requirePermission(db.PermissionRolesWrite), server.listRoles)
+	adminRoutes.GET("/users/:id/roles", requirePermission(db.PermissionRolesWrite), server.listUserRoles)
+	adminRoutes.POST("/users/:id/roles", requirePermission(db.PermissionRolesWrite), server.grantUserRole)
+	adminRoutes.DELETE("/users/:id/roles/:role", requirePermission(db.PermissionRolesWrite), server.revokeUserRole)
 
 	server.Router = router
 }
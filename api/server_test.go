@@ -0,0 +1,93 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awakim/immoblock-backend/config"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTokenMaker(t *testing.T) {
+	key, err := token.GenerateKey("2022-01", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		tokenMaker string
+		keys       []token.Key
+		check      func(t *testing.T, maker token.Maker, err error)
+	}{
+		{
+			name:       "Default",
+			tokenMaker: "",
+			check: func(t *testing.T, maker token.Maker, err error) {
+				require.NoError(t, err)
+				require.IsType(t, &token.PasetoMaker{}, maker)
+			},
+		},
+		{
+			name:       "PasetoPublic",
+			tokenMaker: tokenMakerPaseto,
+			keys:       []token.Key{key},
+			check: func(t *testing.T, maker token.Maker, err error) {
+				require.NoError(t, err)
+				require.IsType(t, &token.PasetoPublicMaker{}, maker)
+			},
+		},
+		{
+			name:       "JWT",
+			tokenMaker: tokenMakerJWT,
+			check: func(t *testing.T, maker token.Maker, err error) {
+				require.NoError(t, err)
+				require.IsType(t, &token.JWTMaker{}, maker)
+			},
+		},
+		{
+			name:       "JWTPublic",
+			tokenMaker: tokenMakerJWT,
+			keys:       []token.Key{key},
+			check: func(t *testing.T, maker token.Maker, err error) {
+				require.NoError(t, err)
+				require.IsType(t, &token.JWTMaker{}, maker)
+			},
+		},
+		{
+			name:       "Unknown",
+			tokenMaker: "unknown",
+			check: func(t *testing.T, maker token.Maker, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			config := config.Config{
+				TokenMaker:        tc.tokenMaker,
+				TokenSymmetricKey: util.RandomString(32),
+				TokenIssuer:       "immoblock-backend",
+				TokenAudience:     "immoblock",
+				TokenKeys:         tc.keys,
+			}
+			maker, err := newTokenMaker(config)
+			tc.check(t, maker, err)
+			if err != nil {
+				return
+			}
+
+			userID, err := uuid.NewRandom()
+			require.NoError(t, err)
+			_, ss, err := maker.CreateToken(token.TypeAccess, userID, token.Access{}, time.Minute)
+			require.NoError(t, err)
+
+			payload, err := maker.VerifyTokenFor(ss, token.TypeAccess)
+			require.NoError(t, err)
+			require.Equal(t, userID, payload.UserID)
+		})
+	}
+}
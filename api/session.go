@@ -0,0 +1,121 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	cache "github.com/awakim/immoblock-backend/cache/redis"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultMaxSessions = 3
+	maxUserAgentLength = 512
+)
+
+// newSession describes a new session of the user on the requesting device.
+func newSession(ctx *gin.Context, userID uuid.UUID) (cache.Session, error) {
+	sessionID, err := uuid.NewRandom()
+	if err != nil {
+		return cache.Session{}, err
+	}
+
+	userAgent := ctx.Request.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	// the address is only informative, a session is not refused without one
+	ip, _ := getIP(ctx)
+
+	now := time.Now().UTC()
+	session := cache.Session{
+		ID:         sessionID.String(),
+		UserID:     userID.String(),
+		UserAgent:  userAgent,
+		IP:         ip,
+		CreatedAt:  now,
+		LastUsedAt: now,
+	}
+	return session, nil
+}
+
+type sessionResponse struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	Current    bool      `json:"current"`
+}
+
+// listSessions returns the active sessions of the user. The session of the access token of the
+// request is flagged as the current one.
+func (server *Server) listSessions(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	sessions, err := server.Cache.ListSessions(ctx, authPayload.UserID.String())
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	rsp := make([]sessionResponse, len(sessions))
+	for i, session := range sessions {
+		rsp[i] = sessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			Current:    session.ID == authPayload.SessionID,
+		}
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type deleteSessionRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+func (server *Server) deleteSession(ctx *gin.Context) {
+	var req deleteSessionRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	err := server.Cache.DeleteSession(ctx, authPayload.UserID.String(), req.ID)
+	if err != nil {
+		if err == redis.Nil {
+			respondError(ctx, http.StatusNotFound, errors.New("session not found"))
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "session has been signed out",
+	})
+}
+
+// deleteSessions signs the user out everywhere, including the current session.
+func (server *Server) deleteSessions(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	err := server.Cache.RevokeUserTokens(ctx, authPayload.UserID.String(), time.Now().UTC(), server.Config.RefreshTokenDuration)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "user has successfully logged out of all sessions",
+	})
+}
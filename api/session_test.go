@@ -0,0 +1,152 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockcache "github.com/awakim/immoblock-backend/cache/mock"
+	cache "github.com/awakim/immoblock-backend/cache/redis"
+	mockdb "github.com/awakim/immoblock-backend/db/mock"
+	mockidentity "github.com/awakim/immoblock-backend/identity/mock"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListSessionsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	cacheMock := mockcache.NewMockCache(ctrl)
+	userManager := mockidentity.NewMockUserManagement(ctrl)
+
+	server := newTestServer(t, store, cacheMock, userManager)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/users/sessions", nil)
+	require.NoError(t, err)
+	sessionID := uuid.NewString()
+	_, accessToken, _, _, err := server.TokenMaker.CreateTokenPair(user.ID, sessionID, token.Access{}, time.Minute, time.Minute)
+	require.NoError(t, err)
+	request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+
+	// the session has been refreshed since, the access token of the request is still current
+	now := time.Now().UTC()
+	sessions := []cache.Session{
+		{ID: sessionID, UserID: user.ID.String(), UserAgent: "Firefox", IP: "10.0.0.1", CreatedAt: now, LastUsedAt: now, AccessTokenID: uuid.NewString()},
+		{ID: uuid.NewString(), UserID: user.ID.String(), UserAgent: "Safari", IP: "10.0.0.2", CreatedAt: now, LastUsedAt: now, AccessTokenID: uuid.NewString()},
+	}
+	cacheMock.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+	cacheMock.EXPECT().ListSessions(gomock.Any(), gomock.Eq(user.ID.String())).Times(1).Return(sessions, nil)
+
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp []sessionResponse
+	err = json.NewDecoder(recorder.Body).Decode(&rsp)
+	require.NoError(t, err)
+	require.Len(t, rsp, 2)
+	require.Equal(t, sessions[0].ID, rsp[0].ID)
+	require.Equal(t, "Firefox", rsp[0].UserAgent)
+	require.True(t, rsp[0].Current)
+	require.False(t, rsp[1].Current)
+}
+
+func TestDeleteSessionAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	sessionID := uuid.NewString()
+
+	testCases := []struct {
+		name          string
+		sessionID     string
+		buildStubs    func(cache *mockcache.MockCache)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			sessionID: sessionID,
+			buildStubs: func(cache *mockcache.MockCache) {
+				cache.EXPECT().DeleteSession(gomock.Any(), gomock.Eq(user.ID.String()), gomock.Eq(sessionID)).Times(1).Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "NotFound",
+			sessionID: sessionID,
+			buildStubs: func(cache *mockcache.MockCache) {
+				cache.EXPECT().DeleteSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(redis.Nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidID",
+			sessionID: "invalid",
+			buildStubs: func(cache *mockcache.MockCache) {
+				cache.EXPECT().DeleteSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			tc.buildStubs(cache)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodDelete, "/users/sessions/"+tc.sessionID, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestDeleteSessionsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	cache := mockcache.NewMockCache(ctrl)
+	userManager := mockidentity.NewMockUserManagement(ctrl)
+	cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+	cache.EXPECT().RevokeUserTokens(gomock.Any(), gomock.Eq(user.ID.String()), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+
+	server := newTestServer(t, store, cache, userManager)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodDelete, "/users/sessions", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
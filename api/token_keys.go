@@ -0,0 +1,24 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+)
+
+type listTokenKeysResponse struct {
+	Keys []token.PublicKey `json:"keys"`
+}
+
+// listTokenKeys publishes the public keys verifying the access and refresh tokens, so that other
+// services can verify them without sharing a secret. The list is empty with a symmetric key.
+func (server *Server) listTokenKeys(ctx *gin.Context) {
+	rsp := listTokenKeysResponse{Keys: []token.PublicKey{}}
+	if publisher, ok := server.TokenMaker.(token.KeyPublisher); ok {
+		rsp.Keys = publisher.PublicKeys()
+	}
+
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, rsp)
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTokenKeysAPI(t *testing.T) {
+	key, err := token.GenerateKey("2022-01", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	publicMaker, err := token.NewPasetoPublicMaker([]token.Key{key}, "immoblock-backend", "immoblock")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		tokenMaker    func(server *Server) token.Maker
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "PublicKeys",
+			tokenMaker: func(server *Server) token.Maker {
+				return publicMaker
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotEmpty(t, recorder.Header().Get("Cache-Control"))
+
+				var rsp listTokenKeysResponse
+				err := json.NewDecoder(recorder.Body).Decode(&rsp)
+				require.NoError(t, err)
+				require.Len(t, rsp.Keys, 1)
+				require.Equal(t, key.ID, rsp.Keys[0].ID)
+				require.Equal(t, key.PublicKey, rsp.Keys[0].PublicKey)
+				require.NotContains(t, recorder.Body.String(), key.PrivateKey)
+			},
+		},
+		{
+			name: "SymmetricKey",
+			tokenMaker: func(server *Server) token.Maker {
+				return server.TokenMaker
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp listTokenKeysResponse
+				err := json.NewDecoder(recorder.Body).Decode(&rsp)
+				require.NoError(t, err)
+				require.NotNil(t, rsp.Keys)
+				require.Empty(t, rsp.Keys)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil, nil, nil)
+			server.TokenMaker = tc.tokenMaker(server)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/.well-known/paseto-keys", nil)
+			require.NoError(t, err)
+
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
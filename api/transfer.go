@@ -9,7 +9,6 @@ import (
 	db "github.com/awakim/immoblock-backend/db/sqlc"
 	"github.com/awakim/immoblock-backend/token"
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
 )
 
 type transferRequest struct {
@@ -22,12 +21,7 @@ type transferRequest struct {
 func (server *Server) createTransfer(ctx *gin.Context) {
 	var req transferRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		var verr validator.ValidationErrors
-		if errors.As(err, &verr) {
-			ctx.JSON(http.StatusBadRequest, gin.H{"errors": ValidationError(verr)})
-			return
-		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errorResponse(err)})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
@@ -39,7 +33,7 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	if fromAccount.UserID != authPayload.UserID {
 		err := errors.New("from account does not belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 	_, valid = server.validAccount(ctx, req.ToAccountID, req.PropertyID)
@@ -55,7 +49,7 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 
 	result, err := server.Store.TransferTx(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -66,27 +60,27 @@ func (server *Server) validAccount(ctx *gin.Context, accountID int64, propertyID
 	account, err := server.Store.GetAccount(ctx, accountID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			respondError(ctx, http.StatusNotFound, err)
 			return account, false
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return account, false
 	}
 
 	_, err = server.Store.GetProperty(ctx, propertyID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			respondError(ctx, http.StatusNotFound, err)
 			return account, false
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return account, false
 	}
 
 	if account.PropertyID != propertyID {
 		err := fmt.Errorf("account [%d] property_id mismatch: %v vs %v", accountID, account.PropertyID, propertyID)
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return account, false
 	}
 
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"net/http"
@@ -17,6 +18,7 @@ import (
 	"github.com/awakim/immoblock-backend/util"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -27,6 +29,8 @@ func randomProperty(t *testing.T) (property db.Property) {
 		Description:         util.RandomString(32),
 		InitialBlockCount:   1000,
 		RemainingBlockCount: 1000,
+		PricePerBlock:       util.RandomInt(100, 10000),
+		Currency:            "EUR",
 		CreatedAt:           time.Now(),
 		UpdatedAt:           time.Now(),
 	}
@@ -66,7 +70,7 @@ func TestTransferAPI(t *testing.T) {
 				"property_id":     property1.ID,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.ID, user1.IsAdmin, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.ID, investorAccess, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
 				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
@@ -86,6 +90,29 @@ func TestTransferAPI(t *testing.T) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name: "EmailNotVerified",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount,
+				"property_id":     property1.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.ID, investorAccess, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				unverified := user1
+				unverified.EmailVerifiedAt = time.Time{}
+				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(unverified, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
 		{
 			name: "UnauthorizedUser",
 			body: gin.H{
@@ -95,7 +122,7 @@ func TestTransferAPI(t *testing.T) {
 				"property_id":     property1.ID,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user2.ID, user2.IsAdmin, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user2.ID, investorAccess, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
 				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
@@ -140,7 +167,7 @@ func TestTransferAPI(t *testing.T) {
 				"property_id":     property1.ID,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.ID, user1.IsAdmin, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.ID, investorAccess, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
 				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
@@ -163,7 +190,7 @@ func TestTransferAPI(t *testing.T) {
 				"property_id":     property1.ID,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.ID, user1.IsAdmin, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.ID, investorAccess, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
 				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
@@ -186,7 +213,7 @@ func TestTransferAPI(t *testing.T) {
 				"property_id":     property1.ID,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user3.ID, user3.IsAdmin, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user3.ID, investorAccess, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
 				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
@@ -210,7 +237,7 @@ func TestTransferAPI(t *testing.T) {
 		// 		"property_id":     property1.ID,
 		// 	},
 		// 	setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-		// 		addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.ID, false, time.Minute)
+		// 		addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.ID, investorAccess, time.Minute)
 		// 	},
 		// 	buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
 		// 		cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
@@ -313,6 +340,20 @@ func TestTransferAPI(t *testing.T) {
 			userManager := mockidentity.NewMockUserManagement(ctrl)
 
 			tc.buildStubs(store, cache, userManager)
+			// users have verified their email unless a test case expects otherwise
+			store.EXPECT().
+				GetUserByID(gomock.Any(), gomock.Any()).
+				AnyTimes().
+				DoAndReturn(func(_ context.Context, id uuid.UUID) (db.User, error) {
+					return db.User{ID: id, EmailVerifiedAt: time.Now()}, nil
+				})
+			// and their identity has been approved
+			store.EXPECT().
+				GetUserInfo(gomock.Any(), gomock.Any()).
+				AnyTimes().
+				DoAndReturn(func(_ context.Context, id uuid.UUID) (db.UserInformation, error) {
+					return db.UserInformation{UserID: id, VerificationStep: db.VerificationApproved}, nil
+				})
 
 			server := newTestServer(t, store, cache, userManager)
 			recorder := httptest.NewRecorder()
@@ -8,8 +8,9 @@ import (
 
 	"github.com/auth0/go-auth0/management"
 	db "github.com/awakim/immoblock-backend/db/sqlc"
+	identity "github.com/awakim/immoblock-backend/identity/auth0"
+	"github.com/awakim/immoblock-backend/token"
 	"github.com/awakim/immoblock-backend/util"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 
@@ -27,6 +28,7 @@ type userResponse struct {
 	Nickname          string    `json:"nickname"`
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
+	EmailVerifiedAt   time.Time `json:"email_verified_at"`
 	CreatedAt         time.Time `json:"created_at"`
 }
 
@@ -36,6 +38,7 @@ func newUserResponse(user db.User) userResponse {
 		Nickname:          user.Nickname,
 		Email:             user.Email,
 		PasswordChangedAt: user.PasswordChangedAt,
+		EmailVerifiedAt:   user.EmailVerifiedAt,
 		CreatedAt:         user.CreatedAt,
 	}
 }
@@ -43,18 +46,13 @@ func newUserResponse(user db.User) userResponse {
 func (server *Server) createUser(ctx *gin.Context) {
 	var req createUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		var verr validator.ValidationErrors
-		if errors.As(err, &verr) {
-			ctx.JSON(http.StatusBadRequest, gin.H{"errors": ValidationError(verr)})
-			return
-		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errorResponse(err)})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	hashedPassword, err := util.HashPassword(req.Password)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -64,37 +62,51 @@ func (server *Server) createUser(ctx *gin.Context) {
 		HashedPassword: hashedPassword,
 	}
 
-	user, err := server.Store.CreateUser(ctx, arg)
+	// the user is registered with Auth0 before the commit so that a failure leaves no local user behind
+	verifyEmail := false
+	connection := identity.Connection
+	var registered uuid.NullUUID
+	result, err := server.Store.CreateUserTx(ctx, db.CreateUserTxParams{
+		CreateUserParams: arg,
+		Roles:            []string{db.RoleInvestor},
+		AfterCreate: func(user db.User) error {
+			// Auth0 hashes the password itself, the verification email is sent by the backend
+			id := user.ID.String()
+			err := server.UserManager.Create(&management.User{
+				ID:          &id,
+				Name:        &req.Nickname,
+				Email:       &req.Email,
+				Password:    &req.Password,
+				VerifyEmail: &verifyEmail,
+				Connection:  &connection,
+			})
+			if err != nil {
+				return identityError(err)
+			}
+			registered = uuid.NullUUID{UUID: user.ID, Valid: true}
+			return nil
+		},
+	})
 	if err != nil {
+		// the commit failed after the registration, Auth0 must forget the user as well
+		if registered.Valid {
+			_ = identity.Delete(server.UserManager, registered.UUID)
+		}
 		if pqErr, ok := err.(*pq.Error); ok {
 			switch pqErr.Code.Name() {
 			case "unique_violation":
 				errEmailAlreadyExists := errors.New("this email already exists")
-				ctx.JSON(http.StatusForbidden, errorResponse(errEmailAlreadyExists))
+				respondError(ctx, http.StatusForbidden, errEmailAlreadyExists)
 				return
 			}
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
+	user := result.User
 
-	uid := user.ID
-	strUUID := uid.String()
-	verifyEmail := true
-	connection := "Username-Password-Authentication"
-	authZeroUser := &management.User{
-		ID:          &strUUID,
-		Name:        &req.Nickname,
-		Email:       &req.Email,
-		Password:    &hashedPassword,
-		VerifyEmail: &verifyEmail,
-		Connection:  &connection,
-	}
-	err = server.UserManager.Create(authZeroUser)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
+	// the account exists at this point, a failed email can be sent again with the resend endpoint
+	_ = server.sendVerificationEmail(ctx, user)
 
 	rsp := newUserResponse(user)
 	ctx.JSON(http.StatusOK, rsp)
@@ -114,46 +126,92 @@ type loginUserResponse struct {
 func (server *Server) loginUser(ctx *gin.Context) {
 	var req loginUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		var verr validator.ValidationErrors
-		if errors.As(err, &verr) {
-			ctx.JSON(http.StatusBadRequest, gin.H{"errors": ValidationError(verr)})
-			return
-		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errorResponse(err)})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	user, err := server.Store.GetUser(ctx, req.Email)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("invalid credentials")))
+			respondError(ctx, http.StatusUnauthorized, errors.New("invalid credentials"))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
 	err = util.CheckPassword(req.Password, user.HashedPassword)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("invalid credentials")))
+		respondError(ctx, http.StatusUnauthorized, errors.New("invalid credentials"))
+		return
+	}
+
+	if user.EmailVerifiedAt.IsZero() {
+		respondError(ctx, http.StatusForbidden, errEmailNotVerified)
+		return
+	}
+
+	if userLocked(user) {
+		respondError(ctx, http.StatusForbidden, errUserLocked)
+		return
+	}
+
+	secret, err := server.Store.GetMFASecret(ctx, user.ID)
+	if err != nil && err != sql.ErrNoRows {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if err == nil && mfaEnabled(secret) {
+		_, mfaToken, err := server.MFATokenMaker.CreateToken(token.TypeMFAChallenge, user.ID, token.Access{}, server.Config.MFAChallengeTokenDuration)
+		if err != nil {
+			respondError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, mfaChallengeResponse{
+			MFARequired: true,
+			MFAToken:    mfaToken,
+		})
+		return
+	}
+
+	rsp, err := server.newLoginResponse(ctx, user)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// newLoginResponse starts a new session for the user on the requesting device with a new token pair.
+func (server *Server) newLoginResponse(ctx *gin.Context, user db.User) (loginUserResponse, error) {
+	access, err := server.userAccess(ctx, user.ID)
+	if err != nil {
+		return loginUserResponse{}, err
+	}
+
+	session, err := newSession(ctx, user.ID)
+	if err != nil {
+		return loginUserResponse{}, err
+	}
 
 	newAT, newATST, newRT, newRTST, err := server.TokenMaker.CreateTokenPair(
 		user.ID,
-		user.IsAdmin,
+		session.ID,
+		access,
 		server.Config.AccessTokenDuration,
 		server.Config.RefreshTokenDuration,
 	)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		return loginUserResponse{}, err
 	}
 
-	err = server.Cache.SetTokenData(ctx, newAT, server.Config.AccessTokenDuration, newRT, server.Config.RefreshTokenDuration)
+	maxSessions := server.Config.MaxSessions
+	if maxSessions <= 0 {
+		maxSessions = defaultMaxSessions
+	}
+	err = server.Cache.CreateSession(ctx, session, newAT, server.Config.AccessTokenDuration, newRT, server.Config.RefreshTokenDuration, maxSessions)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		return loginUserResponse{}, err
 	}
 
 	rsp := loginUserResponse{
@@ -161,7 +219,7 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		RefreshToken: newRTST,
 		User:         newUserResponse(user),
 	}
-	ctx.JSON(http.StatusOK, rsp)
+	return rsp, nil
 }
 
 type logoutUserRequest struct {
@@ -172,30 +230,25 @@ type logoutUserRequest struct {
 func (server *Server) logoutUser(ctx *gin.Context) {
 	var req logoutUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		var verr validator.ValidationErrors
-		if errors.As(err, &verr) {
-			ctx.JSON(http.StatusBadRequest, gin.H{"errors": ValidationError(verr)})
-			return
-		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errorResponse(err)})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	accessToken, err := server.TokenMaker.VerifyToken(req.AccessToken)
+	accessToken, err := server.TokenMaker.VerifyTokenFor(req.AccessToken, token.TypeAccess)
 	if err != nil {
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+		abortWithError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 
-	refreshToken, err := server.TokenMaker.VerifyToken(req.RefreshToken)
+	refreshToken, err := server.TokenMaker.VerifyTokenFor(req.RefreshToken, token.TypeRefresh)
 	if err != nil {
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+		abortWithError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 
 	err = server.Cache.LogoutUser(ctx, *accessToken, *refreshToken)
 	if err != nil {
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+		abortWithError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 
@@ -7,61 +7,61 @@ import (
 
 	db "github.com/awakim/immoblock-backend/db/sqlc"
 	"github.com/awakim/immoblock-backend/token"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 )
 
 type createUserInfoRequest struct {
-	Firstname   string `json:"firstname" binding:"required,alpha"`
-	Lastname    string `json:"lastname" binding:"required,alpha"`
+	Firstname   string `json:"firstname" binding:"required,personname,max=64"`
+	Lastname    string `json:"lastname" binding:"required,personname,max=64"`
 	PhoneNumber string `json:"phone_number" binding:"required,e164"`
-	Nationality string `json:"nationality" binding:"required,alpha"`
-	Address     string `json:"address" binding:"required,ascii"`
-	PostalCode  string `json:"postal_code" binding:"required,alphanum"`
-	City        string `json:"city" binding:"required,alpha"`
-	Country     string `json:"country" binding:"required,alpha"`
+	Nationality string `json:"nationality" binding:"required,nationality"`
+	Address     string `json:"address" binding:"required,max=128"`
+	PostalCode  string `json:"postal_code" binding:"required,postcode=Country"`
+	City        string `json:"city" binding:"required,placename,max=64"`
+	Country     string `json:"country" binding:"required,country"`
 }
 
 type userInfoResponse struct {
-	UserID           uuid.UUID `json:"user_id"`
-	Firstname        string    `json:"firstname"`
-	Lastname         string    `json:"lastname"`
-	PhoneNumber      string    `json:"phone_number"`
-	Nationality      string    `json:"nationality"`
-	Address          string    `json:"address"`
-	PostalCode       string    `json:"postal_code"`
-	City             string    `json:"city"`
-	Country          string    `json:"country"`
-	VerificationStep int16     `json:"verification_step"`
+	UserID             uuid.UUID `json:"user_id"`
+	Firstname          string    `json:"firstname"`
+	Lastname           string    `json:"lastname"`
+	PhoneNumber        string    `json:"phone_number"`
+	Nationality        string    `json:"nationality"`
+	Address            string    `json:"address"`
+	PostalCode         string    `json:"postal_code"`
+	City               string    `json:"city"`
+	Country            string    `json:"country"`
+	VerificationStep   int16     `json:"verification_step"`
+	VerificationStatus string    `json:"verification_status"`
+	VerificationReason string    `json:"verification_reason,omitempty"`
 }
 
 func newUserInfoResponse(userInfo db.UserInformation) userInfoResponse {
 	return userInfoResponse{
-		UserID:           userInfo.UserID,
-		Firstname:        userInfo.Firstname,
-		Lastname:         userInfo.Lastname,
-		PhoneNumber:      userInfo.PhoneNumber,
-		Nationality:      userInfo.Nationality,
-		Address:          userInfo.Address,
-		PostalCode:       userInfo.PostalCode,
-		City:             userInfo.City,
-		Country:          userInfo.Country,
-		VerificationStep: userInfo.VerificationStep,
+		UserID:             userInfo.UserID,
+		Firstname:          userInfo.Firstname,
+		Lastname:           userInfo.Lastname,
+		PhoneNumber:        userInfo.PhoneNumber,
+		Nationality:        userInfo.Nationality,
+		Address:            userInfo.Address,
+		PostalCode:         userInfo.PostalCode,
+		City:               userInfo.City,
+		Country:            userInfo.Country,
+		VerificationStep:   userInfo.VerificationStep,
+		VerificationStatus: db.VerificationStatus(userInfo.VerificationStep),
+		VerificationReason: userInfo.VerificationReason,
 	}
 }
 
 func (server *Server) createUserInfo(ctx *gin.Context) {
 	var req createUserInfoRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		var verr validator.ValidationErrors
-		if errors.As(err, &verr) {
-			ctx.JSON(http.StatusBadRequest, gin.H{"errors": ValidationError(verr)})
-			return
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
@@ -69,11 +69,11 @@ func (server *Server) createUserInfo(ctx *gin.Context) {
 
 	exists, err := server.Store.ExistsUserInfo(ctx, authPayload.UserID)
 	if err == nil && exists {
-		errRowAlreadyExist := errors.New("user information already provided please contact support")
-		ctx.JSON(http.StatusForbidden, errorResponse(errRowAlreadyExist))
+		errRowAlreadyExist := errors.New("user information already provided, update it instead")
+		respondError(ctx, http.StatusForbidden, errRowAlreadyExist)
 		return
 	} else if err != nil && err != sql.ErrNoRows {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -87,7 +87,7 @@ func (server *Server) createUserInfo(ctx *gin.Context) {
 		PostalCode:       req.PostalCode,
 		City:             req.City,
 		Country:          req.Country,
-		VerificationStep: 1,
+		VerificationStep: db.VerificationInfoSubmitted,
 	}
 
 	userInfo, err := server.Store.CreateUserInfo(ctx, arg)
@@ -96,11 +96,11 @@ func (server *Server) createUserInfo(ctx *gin.Context) {
 			switch pqErr.Code.Name() {
 			case "unique_violation":
 				errPhoneAlreadyExists := errors.New("this phone number already exists")
-				ctx.JSON(http.StatusForbidden, errorResponse(errPhoneAlreadyExists))
+				respondError(ctx, http.StatusForbidden, errPhoneAlreadyExists)
 				return
 			}
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -108,6 +108,90 @@ func (server *Server) createUserInfo(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, rsp)
 }
 
+type updateUserInfoRequest struct {
+	Firstname   *string `json:"firstname" binding:"omitempty,personname,max=64"`
+	Lastname    *string `json:"lastname" binding:"omitempty,personname,max=64"`
+	PhoneNumber *string `json:"phone_number" binding:"omitempty,e164"`
+	Nationality *string `json:"nationality" binding:"omitempty,nationality"`
+	Address     *string `json:"address" binding:"omitempty,max=128"`
+	PostalCode  *string `json:"postal_code" binding:"omitempty,postcode=Country"`
+	City        *string `json:"city" binding:"omitempty,placename,max=64"`
+	Country     *string `json:"country" binding:"omitempty,country"`
+}
+
+// userAddress is the part of the address whose fields are validated together. The changes of a
+// partial update are merged with the stored address before they are checked.
+type userAddress struct {
+	PostalCode string `json:"postal_code" binding:"postcode=Country"`
+	Country    string `json:"country"`
+}
+
+func optionalString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+// updateUserInfo changes the fields of the user information present in the request. Contact
+// details can be changed at any time, a change of the legal identity sends the verification back
+// to info submitted.
+func (server *Server) updateUserInfo(ctx *gin.Context) {
+	var req updateUserInfoRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	result, err := server.Store.UpdateUserInfoTx(ctx, db.UpdateUserInfoTxParams{
+		UserID:      authPayload.UserID,
+		Firstname:   optionalString(req.Firstname),
+		Lastname:    optionalString(req.Lastname),
+		PhoneNumber: optionalString(req.PhoneNumber),
+		Nationality: optionalString(req.Nationality),
+		Address:     optionalString(req.Address),
+		PostalCode:  optionalString(req.PostalCode),
+		City:        optionalString(req.City),
+		Country:     optionalString(req.Country),
+		Validate: func(userInfo db.UserInformation) error {
+			// a new postal code must suit the stored country and a new country the stored postal code
+			if req.PostalCode == nil && req.Country == nil {
+				return nil
+			}
+			return binding.Validator.ValidateStruct(userAddress{
+				PostalCode: userInfo.PostalCode,
+				Country:    userInfo.Country,
+			})
+		},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, errUserInfoNotFound)
+			return
+		}
+		var verr validator.ValidationErrors
+		if errors.As(err, &verr) {
+			respondError(ctx, http.StatusBadRequest, err)
+			return
+		}
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				errPhoneAlreadyExists := errors.New("this phone number already exists")
+				respondError(ctx, http.StatusForbidden, errPhoneAlreadyExists)
+				return
+			}
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	rsp := newUserInfoResponse(result.UserInfo)
+	ctx.JSON(http.StatusOK, rsp)
+}
+
 func (server *Server) getUserInfo(ctx *gin.Context) {
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
@@ -115,10 +199,10 @@ func (server *Server) getUserInfo(ctx *gin.Context) {
 	userInfo, err := server.Store.GetUserInfo(ctx, authPayload.UserID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("user has not provided information yet")))
+			respondError(ctx, http.StatusNotFound, errors.New("user has not provided information yet"))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
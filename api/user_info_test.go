@@ -2,10 +2,14 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 
@@ -15,8 +19,10 @@ import (
 	mockidentity "github.com/awakim/immoblock-backend/identity/mock"
 	"github.com/awakim/immoblock-backend/token"
 	"github.com/awakim/immoblock-backend/util"
+	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 )
 
@@ -26,15 +32,65 @@ func randomUserInfo(userID uuid.UUID) db.UserInformation {
 		Firstname:        util.RandomString(6),
 		Lastname:         util.RandomString(6),
 		PhoneNumber:      util.RandomString(6),
-		Nationality:      util.RandomString(6),
+		Nationality:      "FR",
 		Address:          util.RandomString(32),
 		PostalCode:       "75005",
 		City:             "Paris",
-		Country:          "France",
+		Country:          "FR",
 		VerificationStep: 1,
 	}
 }
 
+func requireValidationErrors(t *testing.T, recorder *httptest.ResponseRecorder, errs map[string]string) {
+	var rsp struct {
+		Errors map[string]string `json:"errors"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&rsp))
+	require.Equal(t, errs, rsp.Errors)
+}
+
+type eqUpdateUserInfoTxParamsMatcher struct {
+	arg db.UpdateUserInfoTxParams
+}
+
+func (e eqUpdateUserInfoTxParamsMatcher) Matches(x interface{}) bool {
+	arg, ok := x.(db.UpdateUserInfoTxParams)
+	if !ok {
+		return false
+	}
+
+	// the callback validating the merged information cannot be compared
+	e.arg.Validate = nil
+	arg.Validate = nil
+	return reflect.DeepEqual(e.arg, arg)
+}
+
+func (e eqUpdateUserInfoTxParamsMatcher) String() string {
+	return fmt.Sprintf("matches arg %v", e.arg)
+}
+
+func EqUpdateUserInfoTxParams(arg db.UpdateUserInfoTxParams) gomock.Matcher {
+	return eqUpdateUserInfoTxParamsMatcher{arg}
+}
+
+// mergeUserInfo applies the changes of the update to the stored information as the transaction does,
+// then runs the validation of the update.
+func mergeUserInfo(stored db.UserInformation) func(context.Context, db.UpdateUserInfoTxParams) (db.UpdateUserInfoTxResult, error) {
+	return func(_ context.Context, arg db.UpdateUserInfoTxParams) (db.UpdateUserInfoTxResult, error) {
+		merged := stored
+		if arg.PostalCode.Valid {
+			merged.PostalCode = arg.PostalCode.String
+		}
+		if arg.Country.Valid {
+			merged.Country = arg.Country.String
+		}
+		if err := arg.Validate(merged); err != nil {
+			return db.UpdateUserInfoTxResult{}, err
+		}
+		return db.UpdateUserInfoTxResult{UserInfo: merged}, nil
+	}
+}
+
 func TestGetUserInfoAPI(t *testing.T) {
 	user1, _ := randomUser(t)
 	user2, _ := randomUser(t)
@@ -52,7 +108,7 @@ func TestGetUserInfoAPI(t *testing.T) {
 			name: "OK",
 
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.ID, user1.IsAdmin, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.ID, investorAccess, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
 				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
@@ -66,7 +122,7 @@ func TestGetUserInfoAPI(t *testing.T) {
 			name: "NotFound",
 
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user2.ID, user2.IsAdmin, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user2.ID, investorAccess, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
 				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
@@ -80,7 +136,7 @@ func TestGetUserInfoAPI(t *testing.T) {
 			name: "InternalError",
 
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user2.ID, user2.IsAdmin, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user2.ID, investorAccess, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
 				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
@@ -94,7 +150,7 @@ func TestGetUserInfoAPI(t *testing.T) {
 			name: "Unauthorized",
 
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				// addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user2.ID, user2.IsAdmin, time.Minute)
+				// addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user2.ID, investorAccess, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
 				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(0).Return(false, nil)
@@ -108,7 +164,7 @@ func TestGetUserInfoAPI(t *testing.T) {
 			name: "UserRevoked",
 
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user2.ID, user2.IsAdmin, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user2.ID, investorAccess, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
 				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(true, nil)
@@ -146,6 +202,208 @@ func TestGetUserInfoAPI(t *testing.T) {
 	}
 }
 
+func TestUpdateUserInfoAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	userInfo := randomUserInfo(user.ID)
+	phoneNumber := util.RandomPhoneNumber()
+	lastname := util.RandomString(6)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "ContactDetails",
+			body: gin.H{"phone_number": phoneNumber, "city": "Lyon"},
+			buildStubs: func(store *mockdb.MockStore) {
+				updated := userInfo
+				updated.PhoneNumber = phoneNumber
+				updated.City = "Lyon"
+
+				arg := db.UpdateUserInfoTxParams{
+					UserID:      user.ID,
+					PhoneNumber: sql.NullString{String: phoneNumber, Valid: true},
+					City:        sql.NullString{String: "Lyon", Valid: true},
+				}
+				store.EXPECT().UpdateUserInfoTx(gomock.Any(), EqUpdateUserInfoTxParams(arg)).Times(1).
+					Return(db.UpdateUserInfoTxResult{UserInfo: updated}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp userInfoResponse
+				require.NoError(t, json.NewDecoder(recorder.Body).Decode(&rsp))
+				require.Equal(t, phoneNumber, rsp.PhoneNumber)
+				require.Equal(t, "Lyon", rsp.City)
+			},
+		},
+		{
+			name: "LegalName",
+			body: gin.H{"lastname": lastname},
+			buildStubs: func(store *mockdb.MockStore) {
+				updated := userInfo
+				updated.Lastname = lastname
+				updated.VerificationStep = db.VerificationInfoSubmitted
+
+				arg := db.UpdateUserInfoTxParams{
+					UserID:   user.ID,
+					Lastname: sql.NullString{String: lastname, Valid: true},
+				}
+				store.EXPECT().UpdateUserInfoTx(gomock.Any(), EqUpdateUserInfoTxParams(arg)).Times(1).
+					Return(db.UpdateUserInfoTxResult{UserInfo: updated}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp userInfoResponse
+				require.NoError(t, json.NewDecoder(recorder.Body).Decode(&rsp))
+				require.Equal(t, lastname, rsp.Lastname)
+				require.Equal(t, "info_submitted", rsp.VerificationStatus)
+			},
+		},
+		{
+			name: "InvalidPhoneNumber",
+			body: gin.H{"phone_number": "0612345678"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserInfoTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AccentedName",
+			body: gin.H{"firstname": "Zoë", "lastname": "O'Brien-Séguin"},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpdateUserInfoTxParams{
+					UserID:    user.ID,
+					Firstname: sql.NullString{String: "Zoë", Valid: true},
+					Lastname:  sql.NullString{String: "O'Brien-Séguin", Valid: true},
+				}
+				store.EXPECT().UpdateUserInfoTx(gomock.Any(), EqUpdateUserInfoTxParams(arg)).Times(1).
+					Return(db.UpdateUserInfoTxResult{UserInfo: userInfo}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidCountry",
+			body: gin.H{"country": "France"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserInfoTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireValidationErrors(t, recorder, map[string]string{
+					"country": "must be an ISO 3166 country code, such as FR",
+				})
+			},
+		},
+		{
+			name: "PostalCodeOfAnotherCountry",
+			body: gin.H{"postal_code": "SW1A 1AA", "country": "FR"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserInfoTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireValidationErrors(t, recorder, map[string]string{
+					"postal_code": "is not a valid postal code for the country",
+				})
+			},
+		},
+		{
+			name: "PostalCodeOfStoredCountry",
+			body: gin.H{"postal_code": "69003"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserInfoTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(mergeUserInfo(userInfo))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "PostalCodeNotOfStoredCountry",
+			body: gin.H{"postal_code": "SW1A 1AA"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserInfoTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(mergeUserInfo(userInfo))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireValidationErrors(t, recorder, map[string]string{
+					"postal_code": "is not a valid postal code for the country",
+				})
+			},
+		},
+		{
+			name: "CountryNotOfStoredPostalCode",
+			body: gin.H{"country": "GB"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserInfoTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(mergeUserInfo(userInfo))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireValidationErrors(t, recorder, map[string]string{
+					"postal_code": "is not a valid postal code for the country",
+				})
+			},
+		},
+		{
+			name: "PhoneNumberTaken",
+			body: gin.H{"phone_number": phoneNumber},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserInfoTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.UpdateUserInfoTxResult{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H{"city": "Lyon"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserInfoTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.UpdateUserInfoTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, cache, userManager)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPatch, "/users/info", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
 // func TestCreateUserInfoAPI(t *testing.T) {
 // 	user1, _ := randomUser(t)
 // 	// user2, _ := randomUser(t)
@@ -186,7 +444,7 @@ func TestGetUserInfoAPI(t *testing.T) {
 // 			body: body,
 
 // 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-// 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.ID, user1.IsAdmin, time.Minute)
+// 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.ID, investorAccess, time.Minute)
 // 			},
 // 			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
 // 				cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
@@ -2,18 +2,23 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/auth0/go-auth0/management"
 	mockcache "github.com/awakim/immoblock-backend/cache/mock"
 	mockdb "github.com/awakim/immoblock-backend/db/mock"
 	db "github.com/awakim/immoblock-backend/db/sqlc"
+	identity "github.com/awakim/immoblock-backend/identity/auth0"
 	mockidentity "github.com/awakim/immoblock-backend/identity/mock"
 	"github.com/awakim/immoblock-backend/util"
 	"github.com/gin-gonic/gin"
@@ -23,13 +28,13 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-type eqCreateUserParamsMatcher struct {
-	arg      db.CreateUserParams
+type eqCreateUserTxParamsMatcher struct {
+	arg      db.CreateUserTxParams
 	password string
 }
 
-func (e eqCreateUserParamsMatcher) Matches(x interface{}) bool {
-	arg, ok := x.(db.CreateUserParams)
+func (e eqCreateUserTxParamsMatcher) Matches(x interface{}) bool {
+	arg, ok := x.(db.CreateUserTxParams)
 	if !ok {
 		return false
 	}
@@ -39,16 +44,19 @@ func (e eqCreateUserParamsMatcher) Matches(x interface{}) bool {
 		return false
 	}
 
+	// the callback registering the user elsewhere cannot be compared
 	e.arg.HashedPassword = arg.HashedPassword
+	e.arg.AfterCreate = nil
+	arg.AfterCreate = nil
 	return reflect.DeepEqual(e.arg, arg)
 }
 
-func (e eqCreateUserParamsMatcher) String() string {
+func (e eqCreateUserTxParamsMatcher) String() string {
 	return fmt.Sprintf("matches arg %v and password %v", e.arg, e.password)
 }
 
-func EqCreateUserParams(arg db.CreateUserParams, password string) gomock.Matcher {
-	return eqCreateUserParamsMatcher{arg, password}
+func EqCreateUserTxParams(arg db.CreateUserTxParams, password string) gomock.Matcher {
+	return eqCreateUserTxParamsMatcher{arg, password}
 }
 
 func randomUser(t *testing.T) (user db.User, hashedPassword string) {
@@ -59,10 +67,11 @@ func randomUser(t *testing.T) (user db.User, hashedPassword string) {
 	require.NoError(t, err)
 
 	user = db.User{
-		ID:             uid,
-		HashedPassword: hashedPassword,
-		Nickname:       util.RandomString(6),
-		Email:          util.RandomEmail(),
+		ID:              uid,
+		HashedPassword:  hashedPassword,
+		Nickname:        util.RandomString(6),
+		Email:           util.RandomEmail(),
+		EmailVerifiedAt: time.Now().UTC(),
 	}
 	return
 }
@@ -97,12 +106,28 @@ func TestCreateUserAPI(t *testing.T) {
 				"email":    user.Email,
 			},
 			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
-				arg := db.CreateUserParams{
-					Nickname: user.Nickname,
-					Email:    user.Email,
+				arg := db.CreateUserTxParams{
+					CreateUserParams: db.CreateUserParams{
+						Nickname: user.Nickname,
+						Email:    user.Email,
+					},
+					Roles: []string{db.RoleInvestor},
 				}
-				store.EXPECT().CreateUser(gomock.Any(), EqCreateUserParams(arg, password)).Times(1).Return(user, nil)
-				userManager.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+				result := db.CreateUserTxResult{User: user, Roles: arg.Roles}
+				store.EXPECT().CreateUserTx(gomock.Any(), EqCreateUserTxParams(arg, password)).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateUserTxParams) (db.CreateUserTxResult, error) {
+						return result, arg.AfterCreate(user)
+					})
+				userManager.EXPECT().Create(gomock.Any()).Times(1).
+					DoAndReturn(func(u *management.User, _ ...management.RequestOption) error {
+						// Auth0 is given the password itself, not our hash
+						require.Equal(t, user.ID.String(), u.GetID())
+						require.Equal(t, user.Email, u.GetEmail())
+						require.Equal(t, password, u.GetPassword())
+						require.Equal(t, identity.Connection, u.GetConnection())
+						require.False(t, u.GetVerifyEmail())
+						return nil
+					})
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -117,13 +142,72 @@ func TestCreateUserAPI(t *testing.T) {
 				"email":    user.Email,
 			},
 			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
-				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, sql.ErrConnDone)
+				store.EXPECT().CreateUserTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CreateUserTxResult{}, sql.ErrConnDone)
 				userManager.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
+		{
+			name: "Auth0Error",
+			body: gin.H{
+				"password": password,
+				"nickname": user.Nickname,
+				"email":    user.Email,
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				store.EXPECT().CreateUserTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateUserTxParams) (db.CreateUserTxResult, error) {
+						// the transaction rolls the user back when the callback fails
+						return db.CreateUserTxResult{}, arg.AfterCreate(user)
+					})
+				userManager.EXPECT().Create(gomock.Any()).Times(1).Return(errors.New("auth0 is unavailable"))
+				userManager.EXPECT().Delete(gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadGateway, recorder.Code)
+			},
+		},
+		{
+			name: "Auth0Conflict",
+			body: gin.H{
+				"password": password,
+				"nickname": user.Nickname,
+				"email":    user.Email,
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				store.EXPECT().CreateUserTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateUserTxParams) (db.CreateUserTxResult, error) {
+						return db.CreateUserTxResult{}, arg.AfterCreate(user)
+					})
+				userManager.EXPECT().Create(gomock.Any()).Times(1).Return(auth0Error{http.StatusConflict, "The user already exists."})
+				userManager.EXPECT().Delete(gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "CommitError",
+			body: gin.H{
+				"password": password,
+				"nickname": user.Nickname,
+				"email":    user.Email,
+			},
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
+				store.EXPECT().CreateUserTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateUserTxParams) (db.CreateUserTxResult, error) {
+						require.NoError(t, arg.AfterCreate(user))
+						return db.CreateUserTxResult{}, sql.ErrTxDone
+					})
+				userManager.EXPECT().Create(gomock.Any()).Times(1).Return(nil)
+				userManager.EXPECT().Delete(gomock.Eq(identity.UserID(user.ID))).Times(1).Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
 		{
 			name: "DuplicateUsername",
 			body: gin.H{
@@ -132,7 +216,7 @@ func TestCreateUserAPI(t *testing.T) {
 				"email":    user.Email,
 			},
 			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
-				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, &pq.Error{Code: "23505"})
+				store.EXPECT().CreateUserTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CreateUserTxResult{}, &pq.Error{Code: "23505"})
 				userManager.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
@@ -148,7 +232,7 @@ func TestCreateUserAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 				userManager.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
 			},
@@ -165,7 +249,7 @@ func TestCreateUserAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache, userManager *mockidentity.MockUserManagement) {
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 				userManager.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
 			},
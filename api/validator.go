@@ -0,0 +1,83 @@
+package api
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	// names are made of letters in any script, separated by single spaces, hyphens or apostrophes,
+	// such as "Jean-Luc", "O'Brien" or "Zoë"
+	personNameRegex = regexp.MustCompile(`^(?:\p{L}\p{M}*)+(?:[ '’-](?:\p{L}\p{M}*)+)*$`)
+	// place names can also contain digits and dots, such as "São Paulo", "St. Louis" or "Paris 15"
+	placeNameRegex = regexp.MustCompile(`^\p{L}\p{M}*(?:[\p{L}\p{N}]\p{M}*)*(?:(?:[ '’-]|\. ?)(?:[\p{L}\p{N}]\p{M}*)+)*\.?$`)
+	// postal codes of the countries without a specific format
+	defaultPostalCodeRegex = regexp.MustCompile(`^[A-Z0-9][A-Z0-9 -]{1,8}[A-Z0-9]$`)
+)
+
+// postalCodeRegexes are the formats of the postal codes of the countries with investors, by ISO 3166 code
+var postalCodeRegexes = map[string]*regexp.Regexp{
+	"AT": regexp.MustCompile(`^\d{4}$`),
+	"BE": regexp.MustCompile(`^\d{4}$`),
+	"CA": regexp.MustCompile(`^[A-Z]\d[A-Z] ?\d[A-Z]\d$`),
+	"CH": regexp.MustCompile(`^\d{4}$`),
+	"DE": regexp.MustCompile(`^\d{5}$`),
+	"ES": regexp.MustCompile(`^\d{5}$`),
+	"FR": regexp.MustCompile(`^\d{5}$`),
+	"GB": regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`),
+	"IT": regexp.MustCompile(`^\d{5}$`),
+	"LU": regexp.MustCompile(`^\d{4}$`),
+	"MC": regexp.MustCompile(`^980\d{2}$`),
+	"NL": regexp.MustCompile(`^\d{4} ?[A-Z]{2}$`),
+	"PT": regexp.MustCompile(`^\d{4}-\d{3}$`),
+	"US": regexp.MustCompile(`^\d{5}(-\d{4})?$`),
+}
+
+func validPersonName(fl validator.FieldLevel) bool {
+	return personNameRegex.MatchString(fl.Field().String())
+}
+
+func validPlaceName(fl validator.FieldLevel) bool {
+	return placeNameRegex.MatchString(fl.Field().String())
+}
+
+// validPostalCode checks the postal code against the format of the country held by the field named
+// in the parameter, or against a loose format when the country is unknown or not given.
+func validPostalCode(fl validator.FieldLevel) bool {
+	postalCode := strings.ToUpper(fl.Field().String())
+
+	country := ""
+	if field := reflect.Indirect(fl.Parent()).FieldByName(fl.Param()); field.IsValid() {
+		if field.Kind() == reflect.Ptr && !field.IsNil() {
+			field = field.Elem()
+		}
+		if field.Kind() == reflect.String {
+			country = field.String()
+		}
+	}
+
+	if regex, ok := postalCodeRegexes[country]; ok {
+		return regex.MatchString(postalCode)
+	}
+	return defaultPostalCodeRegex.MatchString(postalCode)
+}
+
+// registerValidations adds the validation tags of the user information:
+// personname, placename, country and nationality (ISO 3166-1 alpha-2 codes) and postcode=<country field>.
+func registerValidations(v *validator.Validate) error {
+	if err := v.RegisterValidation("personname", validPersonName); err != nil {
+		return err
+	}
+	if err := v.RegisterValidation("placename", validPlaceName); err != nil {
+		return err
+	}
+	if err := v.RegisterValidation("postcode", validPostalCode); err != nil {
+		return err
+	}
+	v.RegisterAlias("country", "iso3166_1_alpha2")
+	v.RegisterAlias("nationality", "iso3166_1_alpha2")
+	return nil
+}
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/require"
+)
+
+type testUserInfo struct {
+	Name       string  `json:"name" validate:"personname"`
+	City       string  `json:"city" validate:"placename"`
+	Country    string  `json:"country" validate:"country"`
+	PostalCode string  `json:"postal_code" validate:"postcode=Country"`
+	Zip        *string `json:"zip" validate:"omitempty,postcode=State"`
+	State      *string `json:"state" validate:"omitempty,nationality"`
+}
+
+func newTestValidator(t *testing.T) *validator.Validate {
+	v := validator.New()
+	require.NoError(t, registerValidations(v))
+	return v
+}
+
+func TestPersonName(t *testing.T) {
+	v := newTestValidator(t)
+
+	for _, name := range []string{"Jean-Luc", "O'Brien", "O’Brien", "Zoë", "Zoe\u0308", "Mary Ann", "Łukasz", "Ngô", "Алексей"} {
+		require.NoError(t, v.Var(name, "personname"), name)
+	}
+	for _, name := range []string{"", " Jean", "Jean ", "Jean--Luc", "Jean  Luc", "R2D2", "Jean_Luc", "'Brien", "<script>"} {
+		require.Error(t, v.Var(name, "personname"), name)
+	}
+}
+
+func TestPlaceName(t *testing.T) {
+	v := newTestValidator(t)
+
+	for _, city := range []string{"Paris", "São Paulo", "St. Louis", "Saint-Étienne", "L'Haÿ-les-Roses", "Paris 15", "Zürich"} {
+		require.NoError(t, v.Var(city, "placename"), city)
+	}
+	for _, city := range []string{"", "15 Paris", "Paris!", "Paris  15", "-Lyon", "St..Louis"} {
+		require.Error(t, v.Var(city, "placename"), city)
+	}
+}
+
+func TestCountry(t *testing.T) {
+	v := newTestValidator(t)
+
+	for _, country := range []string{"FR", "DE", "US", "GB"} {
+		require.NoError(t, v.Var(country, "country"), country)
+		require.NoError(t, v.Var(country, "nationality"), country)
+	}
+	for _, country := range []string{"", "fr", "FRA", "France", "XX", "UK"} {
+		require.Error(t, v.Var(country, "country"), country)
+		require.Error(t, v.Var(country, "nationality"), country)
+	}
+}
+
+func TestPostalCode(t *testing.T) {
+	v := newTestValidator(t)
+
+	testCases := []struct {
+		country    string
+		postalCode string
+		valid      bool
+	}{
+		{"FR", "75005", true},
+		{"FR", "7500", false},
+		{"FR", "SW1A 1AA", false},
+		{"GB", "SW1A 1AA", true},
+		{"GB", "sw1a1aa", true},
+		{"GB", "75005", false},
+		{"NL", "1012 AB", true},
+		{"PT", "1000-001", true},
+		{"PT", "1000", false},
+		{"CA", "K1A 0B1", true},
+		{"US", "10001-1234", true},
+		{"MC", "98000", true},
+		{"MC", "75005", false},
+		{"BR", "01310-100", true},
+		{"BR", "!", false},
+	}
+
+	for _, tc := range testCases {
+		err := v.Struct(testUserInfo{
+			Name:       "Zoë",
+			City:       "Paris",
+			Country:    tc.country,
+			PostalCode: tc.postalCode,
+		})
+		if tc.valid {
+			require.NoError(t, err, "%s %s", tc.country, tc.postalCode)
+		} else {
+			require.Error(t, err, "%s %s", tc.country, tc.postalCode)
+		}
+	}
+
+	zip, state := "1012 AB", "NL"
+	require.NoError(t, v.Struct(testUserInfo{Name: "Zoë", City: "Paris", Country: "FR", PostalCode: "75005", Zip: &zip, State: &state}))
+	state = "FR"
+	require.Error(t, v.Struct(testUserInfo{Name: "Zoë", City: "Paris", Country: "FR", PostalCode: "75005", Zip: &zip, State: &state}))
+}
+
+func TestValidationError(t *testing.T) {
+	v := newTestValidator(t)
+	v.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		return fld.Tag.Get("json")
+	})
+
+	err := v.Struct(testUserInfo{Name: "R2D2", City: "Paris", Country: "France", PostalCode: "75005"})
+	verr, ok := err.(validator.ValidationErrors)
+	require.True(t, ok)
+
+	testCases := []struct {
+		name           string
+		acceptLanguage string
+		errs           map[string]string
+	}{
+		{
+			name: "Default",
+			errs: map[string]string{
+				"name":    "must be a name made of letters, spaces, hyphens and apostrophes",
+				"country": "must be an ISO 3166 country code, such as FR",
+			},
+		},
+		{
+			name:           "French",
+			acceptLanguage: "fr-FR,fr;q=0.9,en;q=0.8",
+			errs: map[string]string{
+				"name":    "doit être un nom composé de lettres, d'espaces, de traits d'union et d'apostrophes",
+				"country": "doit être un code pays ISO 3166, par exemple FR",
+			},
+		},
+		{
+			name:           "PreferredLanguage",
+			acceptLanguage: "fr;q=0.5, en-GB",
+			errs: map[string]string{
+				"name":    "must be a name made of letters, spaces, hyphens and apostrophes",
+				"country": "must be an ISO 3166 country code, such as FR",
+			},
+		},
+		{
+			name:           "UnsupportedLanguage",
+			acceptLanguage: "de-DE,de",
+			errs: map[string]string{
+				"name":    "must be a name made of letters, spaces, hyphens and apostrophes",
+				"country": "must be an ISO 3166 country code, such as FR",
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/users/info", nil)
+			if tc.acceptLanguage != "" {
+				ctx.Request.Header.Set("Accept-Language", tc.acceptLanguage)
+			}
+			require.Equal(t, tc.errs, ValidationError(ctx, verr))
+		})
+	}
+}
+
+func TestValidationMessageParam(t *testing.T) {
+	v := validator.New()
+
+	type request struct {
+		Reason string `validate:"max=5"`
+		Amount int64  `validate:"gt=0"`
+	}
+	err := v.Struct(request{Reason: "too long", Amount: 0})
+	verr, ok := err.(validator.ValidationErrors)
+	require.True(t, ok)
+
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	ctx.Request.Header.Set("Accept-Language", "fr")
+	require.Equal(t, map[string]string{
+		"Reason": "doit contenir au plus 5 caractères",
+		"Amount": "doit être supérieur à 0",
+	}, ValidationError(ctx, verr))
+}
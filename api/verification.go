@@ -0,0 +1,128 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var (
+	errIdentityNotApproved       = errors.New("identity verification is not approved")
+	errUnknownVerificationStatus = errors.New("unknown verification status")
+	errRejectionReasonRequired   = errors.New("a reason is required to reject a verification")
+)
+
+// requireApprovedIdentity aborts the request unless the identity of the authenticated user has been approved.
+func (server *Server) requireApprovedIdentity(ctx *gin.Context) {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	userInfo, err := server.Store.GetUserInfo(ctx, payload.UserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			abortWithError(ctx, http.StatusForbidden, errIdentityNotApproved)
+			return
+		}
+		abortWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	if userInfo.VerificationStep != db.VerificationApproved {
+		abortWithError(ctx, http.StatusForbidden, errIdentityNotApproved)
+		return
+	}
+
+	ctx.Next()
+}
+
+type transitionVerificationRequest struct {
+	Status string `json:"status" binding:"required"`
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// transitionVerification moves the identity verification of the user to another status. Only the
+// transitions allowed by the store are accepted, a rejection needs a reason shown to the user.
+func (server *Server) transitionVerification(ctx *gin.Context) {
+	userInfo, ok := server.getAdminUserInfo(ctx)
+	if !ok {
+		return
+	}
+
+	var req transitionVerificationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	step, ok := db.ParseVerificationStatus(req.Status)
+	if !ok {
+		respondError(ctx, http.StatusBadRequest, errUnknownVerificationStatus)
+		return
+	}
+	if step == db.VerificationRejected && req.Reason == "" {
+		respondError(ctx, http.StatusBadRequest, errRejectionReasonRequired)
+		return
+	}
+
+	actor := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.Store.TransitionVerificationTx(ctx, db.TransitionVerificationTxParams{
+		UserID:  userInfo.UserID,
+		ToStep:  step,
+		ActorID: uuid.NullUUID{UUID: actor.UserID, Valid: true},
+		Reason:  req.Reason,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrInvalidVerificationTransition) {
+			respondError(ctx, http.StatusConflict, err)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserInfoResponse(result.UserInfo))
+}
+
+type verificationTransitionResponse struct {
+	db.VerificationTransition
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+}
+
+// listVerificationHistory lists the transitions of the identity verification of the user, the latest first.
+func (server *Server) listVerificationHistory(ctx *gin.Context) {
+	user, ok := server.getAdminUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req adminPageRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	transitions, err := server.Store.ListVerificationTransitions(ctx, db.ListVerificationTransitionsParams{
+		UserID: user.ID,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	rsp := make([]verificationTransitionResponse, len(transitions))
+	for i, transition := range transitions {
+		rsp[i] = verificationTransitionResponse{
+			VerificationTransition: transition,
+			FromStatus:             db.VerificationStatus(transition.FromStep),
+			ToStatus:               db.VerificationStatus(transition.ToStep),
+		}
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
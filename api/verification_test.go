@@ -0,0 +1,265 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockcache "github.com/awakim/immoblock-backend/cache/mock"
+	mockdb "github.com/awakim/immoblock-backend/db/mock"
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+var complianceAccess = token.Access{
+	Roles:       []string{db.RoleInvestor, db.RoleComplianceOfficer},
+	Permissions: []string{db.PermissionAdminAccess, db.PermissionUsersRead, db.PermissionKYCReview, db.PermissionKYCDocumentsRead},
+}
+
+// expectApprovedIdentity lets the user through the identity verification gate
+func expectApprovedIdentity(store *mockdb.MockStore, userID uuid.UUID) {
+	userInfo := randomUserInfo(userID)
+	userInfo.VerificationStep = db.VerificationApproved
+	store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(userID)).AnyTimes().Return(userInfo, nil)
+}
+
+func TestTransitionVerificationAPI(t *testing.T) {
+	staff, _ := randomUser(t)
+	user, _ := randomUser(t)
+	userInfo := randomUserInfo(user.ID)
+	userInfo.VerificationStep = db.VerificationUnderReview
+	reason := "documents are blurry"
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		access        token.Access
+		buildStubs    func(store *mockdb.MockStore, cache *mockcache.MockCache)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Approve",
+			body:   gin.H{"status": "approved"},
+			access: complianceAccess,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				approved := userInfo
+				approved.VerificationStep = db.VerificationApproved
+
+				arg := db.TransitionVerificationTxParams{
+					UserID:  user.ID,
+					ToStep:  db.VerificationApproved,
+					ActorID: uuid.NullUUID{UUID: staff.ID, Valid: true},
+				}
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(userInfo, nil)
+				store.EXPECT().TransitionVerificationTx(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.TransitionVerificationTxResult{UserInfo: approved}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp userInfoResponse
+				err := json.NewDecoder(recorder.Body).Decode(&rsp)
+				require.NoError(t, err)
+				require.Equal(t, db.VerificationApproved, rsp.VerificationStep)
+				require.Equal(t, "approved", rsp.VerificationStatus)
+			},
+		},
+		{
+			name:   "Reject",
+			body:   gin.H{"status": "rejected", "reason": reason},
+			access: complianceAccess,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				rejected := userInfo
+				rejected.VerificationStep = db.VerificationRejected
+				rejected.VerificationReason = reason
+
+				arg := db.TransitionVerificationTxParams{
+					UserID:  user.ID,
+					ToStep:  db.VerificationRejected,
+					ActorID: uuid.NullUUID{UUID: staff.ID, Valid: true},
+					Reason:  reason,
+				}
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(userInfo, nil)
+				store.EXPECT().TransitionVerificationTx(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.TransitionVerificationTxResult{UserInfo: rejected}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp userInfoResponse
+				err := json.NewDecoder(recorder.Body).Decode(&rsp)
+				require.NoError(t, err)
+				require.Equal(t, "rejected", rsp.VerificationStatus)
+				require.Equal(t, reason, rsp.VerificationReason)
+			},
+		},
+		{
+			name:   "RejectWithoutReason",
+			body:   gin.H{"status": "rejected"},
+			access: complianceAccess,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(userInfo, nil)
+				store.EXPECT().TransitionVerificationTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "UnknownStatus",
+			body:   gin.H{"status": "verified"},
+			access: complianceAccess,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(userInfo, nil)
+				store.EXPECT().TransitionVerificationTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "InvalidTransition",
+			body:   gin.H{"status": "expired"},
+			access: complianceAccess,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(userInfo, nil)
+				store.EXPECT().TransitionVerificationTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.TransitionVerificationTxResult{}, fmt.Errorf("%w: from under_review to expired", db.ErrInvalidVerificationTransition))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:   "NoInformation",
+			body:   gin.H{"status": "approved"},
+			access: complianceAccess,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.UserInformation{}, sql.ErrNoRows)
+				store.EXPECT().TransitionVerificationTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "MissingPermission",
+			body:   gin.H{"status": "approved"},
+			access: supportAccess,
+			buildStubs: func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+				store.EXPECT().TransitionVerificationTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/admin/users/%s/verification", user.ID)
+			recorder := serveAdminRequest(t, http.MethodPost, url, tc.body, staff.ID, tc.access, tc.buildStubs)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListVerificationHistoryAPI(t *testing.T) {
+	staff, _ := randomUser(t)
+	user, _ := randomUser(t)
+
+	transitions := []db.VerificationTransition{
+		{
+			ID:        2,
+			UserID:    user.ID,
+			FromStep:  db.VerificationUnderReview,
+			ToStep:    db.VerificationApproved,
+			ActorID:   uuid.NullUUID{UUID: staff.ID, Valid: true},
+			CreatedAt: time.Now().UTC(),
+		},
+		{
+			ID:        1,
+			UserID:    user.ID,
+			FromStep:  db.VerificationDocumentsUploaded,
+			ToStep:    db.VerificationUnderReview,
+			CreatedAt: time.Now().UTC(),
+		},
+	}
+
+	url := fmt.Sprintf("/admin/users/%s/verification/history?page_id=1&page_size=5", user.ID)
+	recorder := serveAdminRequest(t, http.MethodGet, url, nil, staff.ID, complianceAccess,
+		func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+			arg := db.ListVerificationTransitionsParams{UserID: user.ID, Limit: 5, Offset: 0}
+			store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+			store.EXPECT().ListVerificationTransitions(gomock.Any(), gomock.Eq(arg)).Times(1).Return(transitions, nil)
+		})
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp []verificationTransitionResponse
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&rsp))
+	require.Len(t, rsp, 2)
+	require.Equal(t, "under_review", rsp[0].FromStatus)
+	require.Equal(t, "approved", rsp[0].ToStatus)
+	require.Equal(t, "documents_uploaded", rsp[1].FromStatus)
+}
+
+func TestRequireApprovedIdentity(t *testing.T) {
+	user, _ := randomUser(t)
+	url := "/wallets/withdrawals"
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+	}{
+		{
+			name: "NoInformation",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.UserInformation{}, sql.ErrNoRows)
+			},
+		},
+		{
+			name: "UnderReview",
+			buildStubs: func(store *mockdb.MockStore) {
+				userInfo := randomUserInfo(user.ID)
+				userInfo.VerificationStep = db.VerificationUnderReview
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(userInfo, nil)
+			},
+		},
+		{
+			name: "Expired",
+			buildStubs: func(store *mockdb.MockStore) {
+				userInfo := randomUserInfo(user.ID)
+				userInfo.VerificationStep = db.VerificationExpired
+				store.EXPECT().GetUserInfo(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(userInfo, nil)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveAdminRequest(t, http.MethodPost, url, gin.H{"amount": 100}, user.ID, investorAccess,
+				func(store *mockdb.MockStore, cache *mockcache.MockCache) {
+					tc.buildStubs(store)
+					store.EXPECT().WalletTx(gomock.Any(), gomock.Any()).Times(0)
+					cache.EXPECT().LockIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				})
+			require.Equal(t, http.StatusForbidden, recorder.Code)
+		})
+	}
+}
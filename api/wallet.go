@@ -0,0 +1,185 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	payment "github.com/awakim/immoblock-backend/payment/local"
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/gin-gonic/gin"
+)
+
+func (server *Server) listWallets(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	wallets, err := server.Store.ListWallets(ctx, authPayload.UserID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, wallets)
+}
+
+type listWalletEntriesURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type listWalletEntriesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listWalletEntries(ctx *gin.Context) {
+	var uri listWalletEntriesURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var req listWalletEntriesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	wallet, err := server.Store.GetWallet(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if wallet.UserID != authPayload.UserID {
+		err := errors.New("wallet does not belong to the authenticated user")
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	arg := db.ListWalletEntriesParams{
+		WalletID: wallet.ID,
+		Limit:    req.PageSize,
+		Offset:   (req.PageID - 1) * req.PageSize,
+	}
+
+	entries, err := server.Store.ListWalletEntries(ctx, arg)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entries)
+}
+
+type walletOperationRequest struct {
+	Amount   int64  `json:"amount" binding:"required,gt=0"`
+	Currency string `json:"currency" binding:"required,len=3,uppercase"`
+}
+
+// createDeposit charges the user and credits their wallet. The charge is refunded if the wallet
+// cannot be credited.
+func (server *Server) createDeposit(ctx *gin.Context) {
+	var req walletOperationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	reference, err := server.PaymentProvider.Charge(ctx, authPayload.UserID, req.Amount, req.Currency)
+	if err != nil {
+		respondError(ctx, http.StatusBadGateway, err)
+		return
+	}
+
+	result, err := server.Store.WalletTx(ctx, db.WalletTxParams{
+		UserID:    authPayload.UserID,
+		Currency:  req.Currency,
+		Amount:    req.Amount,
+		Kind:      db.WalletEntryDeposit,
+		Reference: reference,
+	})
+	if err != nil {
+		// the wallet was not credited, the user gets the charge back rather than paying for nothing
+		if _, rfErr := server.PaymentProvider.Refund(ctx, reference); rfErr != nil {
+			respondError(ctx, http.StatusInternalServerError, fmt.Errorf("cannot refund charge %s: %v, wallet error: %w", reference, rfErr, err))
+			return
+		}
+		markRetrySafe(ctx)
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// createWithdrawal debits the wallet before paying out so the funds cannot be spent twice. The payout
+// is keyed by the withdrawal so that it is paid once, and the funds are only credited back when the
+// payment provider refuses it. Any other failure leaves the withdrawal pending, without a reference,
+// until it is reconciled with the provider.
+func (server *Server) createWithdrawal(ctx *gin.Context) {
+	var req walletOperationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	result, err := server.Store.WalletTx(ctx, db.WalletTxParams{
+		UserID:   authPayload.UserID,
+		Currency: req.Currency,
+		Amount:   -req.Amount,
+		Kind:     db.WalletEntryWithdrawal,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrInsufficientFunds) {
+			respondError(ctx, http.StatusConflict, err)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	key := fmt.Sprintf("withdrawal_%d", result.Entry.ID)
+	reference, err := server.PaymentProvider.Payout(ctx, key, authPayload.UserID, req.Amount, req.Currency)
+	if err != nil {
+		if !errors.Is(err, payment.ErrRejected) {
+			respondError(ctx, http.StatusBadGateway, fmt.Errorf("withdrawal %d is pending: %w", result.Entry.ID, err))
+			return
+		}
+		_, rbErr := server.Store.WalletTx(ctx, db.WalletTxParams{
+			UserID:    authPayload.UserID,
+			Currency:  req.Currency,
+			Amount:    req.Amount,
+			Kind:      db.WalletEntryWithdrawalReversal,
+			Reference: key,
+		})
+		if rbErr != nil {
+			respondError(ctx, http.StatusInternalServerError, rbErr)
+			return
+		}
+		markRetrySafe(ctx)
+		respondError(ctx, http.StatusBadGateway, err)
+		return
+	}
+
+	result.Entry, err = server.Store.SetWalletEntryReference(ctx, db.SetWalletEntryReferenceParams{
+		ID:        result.Entry.ID,
+		Reference: reference,
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, fmt.Errorf("withdrawal %d paid out as %s: %w", result.Entry.ID, reference, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
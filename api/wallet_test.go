@@ -0,0 +1,250 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockcache "github.com/awakim/immoblock-backend/cache/mock"
+	mockdb "github.com/awakim/immoblock-backend/db/mock"
+	db "github.com/awakim/immoblock-backend/db/sqlc"
+	mockidentity "github.com/awakim/immoblock-backend/identity/mock"
+	payment "github.com/awakim/immoblock-backend/payment/local"
+	mockpayment "github.com/awakim/immoblock-backend/payment/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletOperationsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	amount := int64(5000)
+	currency := "EUR"
+	wallet := db.Wallet{
+		ID:       1,
+		UserID:   user.ID,
+		Currency: currency,
+		Balance:  amount,
+	}
+
+	testCases := []struct {
+		name          string
+		url           string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore, provider *mockpayment.MockProvider)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "DepositOK",
+			url:  "/wallets/deposits",
+			body: gin.H{
+				"amount":   amount,
+				"currency": currency,
+			},
+			buildStubs: func(store *mockdb.MockStore, provider *mockpayment.MockProvider) {
+				provider.EXPECT().Charge(gomock.Any(), gomock.Eq(user.ID), gomock.Eq(amount), gomock.Eq(currency)).Times(1).Return("ref", nil)
+				arg := db.WalletTxParams{
+					UserID:    user.ID,
+					Currency:  currency,
+					Amount:    amount,
+					Kind:      db.WalletEntryDeposit,
+					Reference: "ref",
+				}
+				store.EXPECT().WalletTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.WalletTxResult{Wallet: wallet}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "DepositChargeFailed",
+			url:  "/wallets/deposits",
+			body: gin.H{
+				"amount":   amount,
+				"currency": currency,
+			},
+			buildStubs: func(store *mockdb.MockStore, provider *mockpayment.MockProvider) {
+				provider.EXPECT().Charge(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return("", errors.New("card declined"))
+				store.EXPECT().WalletTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadGateway, recorder.Code)
+			},
+		},
+		{
+			name: "DepositWalletTxFailed",
+			url:  "/wallets/deposits",
+			body: gin.H{
+				"amount":   amount,
+				"currency": currency,
+			},
+			buildStubs: func(store *mockdb.MockStore, provider *mockpayment.MockProvider) {
+				provider.EXPECT().Charge(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return("ref", nil)
+				store.EXPECT().WalletTx(gomock.Any(), gomock.Any()).Times(1).Return(db.WalletTxResult{}, sql.ErrConnDone)
+				provider.EXPECT().Refund(gomock.Any(), gomock.Eq("ref")).Times(1).Return("refund", nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "DepositRefundFailed",
+			url:  "/wallets/deposits",
+			body: gin.H{
+				"amount":   amount,
+				"currency": currency,
+			},
+			buildStubs: func(store *mockdb.MockStore, provider *mockpayment.MockProvider) {
+				provider.EXPECT().Charge(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return("ref", nil)
+				store.EXPECT().WalletTx(gomock.Any(), gomock.Any()).Times(1).Return(db.WalletTxResult{}, sql.ErrConnDone)
+				provider.EXPECT().Refund(gomock.Any(), gomock.Eq("ref")).Times(1).Return("", errors.New("provider unavailable"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidCurrency",
+			url:  "/wallets/deposits",
+			body: gin.H{
+				"amount":   amount,
+				"currency": "euro",
+			},
+			buildStubs: func(store *mockdb.MockStore, provider *mockpayment.MockProvider) {
+				provider.EXPECT().Charge(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().WalletTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "WithdrawalOK",
+			url:  "/wallets/withdrawals",
+			body: gin.H{
+				"amount":   amount,
+				"currency": currency,
+			},
+			buildStubs: func(store *mockdb.MockStore, provider *mockpayment.MockProvider) {
+				arg := db.WalletTxParams{
+					UserID:   user.ID,
+					Currency: currency,
+					Amount:   -amount,
+					Kind:     db.WalletEntryWithdrawal,
+				}
+				entry := db.WalletEntry{ID: 7, WalletID: wallet.ID, Amount: -amount, Kind: db.WalletEntryWithdrawal}
+				store.EXPECT().WalletTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.WalletTxResult{Wallet: wallet, Entry: entry}, nil)
+				provider.EXPECT().Payout(gomock.Any(), gomock.Eq("withdrawal_7"), gomock.Eq(user.ID), gomock.Eq(amount), gomock.Eq(currency)).Times(1).Return("ref", nil)
+				entry.Reference = "ref"
+				store.EXPECT().
+					SetWalletEntryReference(gomock.Any(), gomock.Eq(db.SetWalletEntryReferenceParams{ID: entry.ID, Reference: "ref"})).
+					Times(1).
+					Return(entry, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "WithdrawalInsufficientFunds",
+			url:  "/wallets/withdrawals",
+			body: gin.H{
+				"amount":   amount,
+				"currency": currency,
+			},
+			buildStubs: func(store *mockdb.MockStore, provider *mockpayment.MockProvider) {
+				store.EXPECT().WalletTx(gomock.Any(), gomock.Any()).Times(1).Return(db.WalletTxResult{}, db.ErrInsufficientFunds)
+				provider.EXPECT().Payout(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "WithdrawalPayoutRejected",
+			url:  "/wallets/withdrawals",
+			body: gin.H{
+				"amount":   amount,
+				"currency": currency,
+			},
+			buildStubs: func(store *mockdb.MockStore, provider *mockpayment.MockProvider) {
+				debit := db.WalletTxParams{
+					UserID:   user.ID,
+					Currency: currency,
+					Amount:   -amount,
+					Kind:     db.WalletEntryWithdrawal,
+				}
+				reversal := db.WalletTxParams{
+					UserID:    user.ID,
+					Currency:  currency,
+					Amount:    amount,
+					Kind:      db.WalletEntryWithdrawalReversal,
+					Reference: "withdrawal_7",
+				}
+				store.EXPECT().WalletTx(gomock.Any(), gomock.Eq(debit)).Times(1).Return(db.WalletTxResult{Entry: db.WalletEntry{ID: 7}}, nil)
+				provider.EXPECT().
+					Payout(gomock.Any(), gomock.Eq("withdrawal_7"), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return("", fmt.Errorf("account closed: %w", payment.ErrRejected))
+				store.EXPECT().WalletTx(gomock.Any(), gomock.Eq(reversal)).Times(1).Return(db.WalletTxResult{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadGateway, recorder.Code)
+			},
+		},
+		{
+			name: "WithdrawalPayoutUnknown",
+			url:  "/wallets/withdrawals",
+			body: gin.H{
+				"amount":   amount,
+				"currency": currency,
+			},
+			buildStubs: func(store *mockdb.MockStore, provider *mockpayment.MockProvider) {
+				// the payout may have gone through, the withdrawal stays pending rather than credited back
+				store.EXPECT().WalletTx(gomock.Any(), gomock.Any()).Times(1).Return(db.WalletTxResult{Entry: db.WalletEntry{ID: 7}}, nil)
+				provider.EXPECT().Payout(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return("", context.DeadlineExceeded)
+				store.EXPECT().SetWalletEntryReference(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadGateway, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			cache := mockcache.NewMockCache(ctrl)
+			userManager := mockidentity.NewMockUserManagement(ctrl)
+			provider := mockpayment.NewMockProvider(ctrl)
+			cache.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			tc.buildStubs(store, provider)
+			expectApprovedIdentity(store, user.ID)
+
+			server := newTestServer(t, store, cache, userManager)
+			server.PaymentProvider = provider
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, tc.url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.TokenMaker, authorizationTypeBearer, user.ID, investorAccess, time.Minute)
+			server.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 	time "time"
 
+	cache "github.com/awakim/immoblock-backend/cache/redis"
 	token "github.com/awakim/immoblock-backend/token"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -36,18 +37,62 @@ func (m *MockCache) EXPECT() *MockCacheMockRecorder {
 	return m.recorder
 }
 
-// DeleteRefreshToken mocks base method.
-func (m *MockCache) DeleteRefreshToken(arg0 context.Context, arg1, arg2 string) error {
+// AddMFAFailure mocks base method.
+func (m *MockCache) AddMFAFailure(arg0 context.Context, arg1 string, arg2 time.Duration) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteRefreshToken", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "AddMFAFailure", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddMFAFailure indicates an expected call of AddMFAFailure.
+func (mr *MockCacheMockRecorder) AddMFAFailure(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMFAFailure", reflect.TypeOf((*MockCache)(nil).AddMFAFailure), arg0, arg1, arg2)
+}
+
+// ConsumeToken mocks base method.
+func (m *MockCache) ConsumeToken(arg0 context.Context, arg1 token.Payload) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeToken", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeToken indicates an expected call of ConsumeToken.
+func (mr *MockCacheMockRecorder) ConsumeToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeToken", reflect.TypeOf((*MockCache)(nil).ConsumeToken), arg0, arg1)
+}
+
+// CreateSession mocks base method.
+func (m *MockCache) CreateSession(arg0 context.Context, arg1 cache.Session, arg2 token.Payload, arg3 time.Duration, arg4 token.Payload, arg5 time.Duration, arg6 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockCacheMockRecorder) CreateSession(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockCache)(nil).CreateSession), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+}
+
+// DeleteSession mocks base method.
+func (m *MockCache) DeleteSession(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSession", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteRefreshToken indicates an expected call of DeleteRefreshToken.
-func (mr *MockCacheMockRecorder) DeleteRefreshToken(arg0, arg1, arg2 interface{}) *gomock.Call {
+// DeleteSession indicates an expected call of DeleteSession.
+func (mr *MockCacheMockRecorder) DeleteSession(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRefreshToken", reflect.TypeOf((*MockCache)(nil).DeleteRefreshToken), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockCache)(nil).DeleteSession), arg0, arg1, arg2)
 }
 
 // IsRateLimited mocks base method.
@@ -80,6 +125,37 @@ func (mr *MockCacheMockRecorder) IsRevoked(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRevoked", reflect.TypeOf((*MockCache)(nil).IsRevoked), arg0, arg1)
 }
 
+// ListSessions mocks base method.
+func (m *MockCache) ListSessions(arg0 context.Context, arg1 string) ([]cache.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions", arg0, arg1)
+	ret0, _ := ret[0].([]cache.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockCacheMockRecorder) ListSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockCache)(nil).ListSessions), arg0, arg1)
+}
+
+// LockIdempotencyKey mocks base method.
+func (m *MockCache) LockIdempotencyKey(arg0 context.Context, arg1, arg2, arg3 string, arg4 time.Duration) (cache.IdempotencyRecord, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockIdempotencyKey", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(cache.IdempotencyRecord)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// LockIdempotencyKey indicates an expected call of LockIdempotencyKey.
+func (mr *MockCacheMockRecorder) LockIdempotencyKey(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockIdempotencyKey", reflect.TypeOf((*MockCache)(nil).LockIdempotencyKey), arg0, arg1, arg2, arg3, arg4)
+}
+
 // LogoutUser mocks base method.
 func (m *MockCache) LogoutUser(arg0 context.Context, arg1, arg2 token.Payload) error {
 	m.ctrl.T.Helper()
@@ -94,16 +170,102 @@ func (mr *MockCacheMockRecorder) LogoutUser(arg0, arg1, arg2 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutUser", reflect.TypeOf((*MockCache)(nil).LogoutUser), arg0, arg1, arg2)
 }
 
-// SetTokenData mocks base method.
-func (m *MockCache) SetTokenData(arg0 context.Context, arg1 token.Payload, arg2 time.Duration, arg3 token.Payload, arg4 time.Duration) error {
+// MFAFailures mocks base method.
+func (m *MockCache) MFAFailures(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MFAFailures", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MFAFailures indicates an expected call of MFAFailures.
+func (mr *MockCacheMockRecorder) MFAFailures(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MFAFailures", reflect.TypeOf((*MockCache)(nil).MFAFailures), arg0, arg1)
+}
+
+// RefreshSession mocks base method.
+func (m *MockCache) RefreshSession(arg0 context.Context, arg1, arg2 token.Payload, arg3 time.Duration, arg4 token.Payload, arg5 time.Duration) (cache.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshSession", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(cache.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshSession indicates an expected call of RefreshSession.
+func (mr *MockCacheMockRecorder) RefreshSession(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshSession", reflect.TypeOf((*MockCache)(nil).RefreshSession), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// ReleaseIdempotencyKey mocks base method.
+func (m *MockCache) ReleaseIdempotencyKey(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseIdempotencyKey", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseIdempotencyKey indicates an expected call of ReleaseIdempotencyKey.
+func (mr *MockCacheMockRecorder) ReleaseIdempotencyKey(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseIdempotencyKey", reflect.TypeOf((*MockCache)(nil).ReleaseIdempotencyKey), arg0, arg1, arg2)
+}
+
+// ReleaseToken mocks base method.
+func (m *MockCache) ReleaseToken(arg0 context.Context, arg1 token.Payload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseToken", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseToken indicates an expected call of ReleaseToken.
+func (mr *MockCacheMockRecorder) ReleaseToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseToken", reflect.TypeOf((*MockCache)(nil).ReleaseToken), arg0, arg1)
+}
+
+// ResetMFAFailures mocks base method.
+func (m *MockCache) ResetMFAFailures(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetMFAFailures", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetMFAFailures indicates an expected call of ResetMFAFailures.
+func (mr *MockCacheMockRecorder) ResetMFAFailures(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetMFAFailures", reflect.TypeOf((*MockCache)(nil).ResetMFAFailures), arg0, arg1)
+}
+
+// RevokeUserTokens mocks base method.
+func (m *MockCache) RevokeUserTokens(arg0 context.Context, arg1 string, arg2 time.Time, arg3 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserTokens", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserTokens indicates an expected call of RevokeUserTokens.
+func (mr *MockCacheMockRecorder) RevokeUserTokens(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserTokens", reflect.TypeOf((*MockCache)(nil).RevokeUserTokens), arg0, arg1, arg2, arg3)
+}
+
+// SaveIdempotentResponse mocks base method.
+func (m *MockCache) SaveIdempotentResponse(arg0 context.Context, arg1, arg2 string, arg3 cache.IdempotencyRecord, arg4 time.Duration) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetTokenData", arg0, arg1, arg2, arg3, arg4)
+	ret := m.ctrl.Call(m, "SaveIdempotentResponse", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetTokenData indicates an expected call of SetTokenData.
-func (mr *MockCacheMockRecorder) SetTokenData(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+// SaveIdempotentResponse indicates an expected call of SaveIdempotentResponse.
+func (mr *MockCacheMockRecorder) SaveIdempotentResponse(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTokenData", reflect.TypeOf((*MockCache)(nil).SetTokenData), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdempotentResponse", reflect.TypeOf((*MockCache)(nil).SaveIdempotentResponse), arg0, arg1, arg2, arg3, arg4)
 }
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// IdempotencyRecord is the state of a request made with an Idempotency-Key.
+// A record without a status is still being processed.
+type IdempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Status      int    `json:"status,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Completed reports whether the response of the request has been saved.
+func (record IdempotencyRecord) Completed() bool {
+	return record.Status != 0
+}
+
+func idempotencyKey(userID string, key string) string {
+	return fmt.Sprintf("idem:%s:%s", userID, key)
+}
+
+// LockIdempotencyKey claims the key `idem:{{userID}}:{{key}}` for a request fingerprint.
+// When the key is already taken, the existing record is returned with acquired set to false.
+func (cache *RedisStore) LockIdempotencyKey(ctx context.Context, userID string, key string, fingerprint string, ttl time.Duration) (IdempotencyRecord, bool, error) {
+	redisKey := idempotencyKey(userID, key)
+	pending, err := json.Marshal(IdempotencyRecord{Fingerprint: fingerprint})
+	if err != nil {
+		return IdempotencyRecord{}, false, err
+	}
+
+	// the existing record may expire between both calls, in which case the key is claimed again
+	for i := 0; i < 2; i++ {
+		acquired, err := cache.Client.SetNX(ctx, redisKey, pending, ttl).Result()
+		if err != nil {
+			return IdempotencyRecord{}, false, err
+		}
+		if acquired {
+			return IdempotencyRecord{Fingerprint: fingerprint}, true, nil
+		}
+
+		data, err := cache.Client.Get(ctx, redisKey).Bytes()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return IdempotencyRecord{}, false, err
+		}
+
+		var record IdempotencyRecord
+		err = json.Unmarshal(data, &record)
+		return record, false, err
+	}
+
+	return IdempotencyRecord{}, false, fmt.Errorf("cannot lock idempotency key %s", key)
+}
+
+// SaveIdempotentResponse stores the response of a request holding the idempotency key, to be replayed.
+func (cache *RedisStore) SaveIdempotentResponse(ctx context.Context, userID string, key string, record IdempotencyRecord, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return cache.Client.Set(ctx, idempotencyKey(userID, key), data, ttl).Err()
+}
+
+// ReleaseIdempotencyKey deletes the idempotency key so that the request can be retried.
+func (cache *RedisStore) ReleaseIdempotencyKey(ctx context.Context, userID string, key string) error {
+	return cache.Client.Del(ctx, idempotencyKey(userID, key)).Err()
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	userID := util.RandomString(8)
+	key := util.RandomString(16)
+
+	record, acquired, err := testCache.LockIdempotencyKey(context.Background(), userID, key, "fingerprint", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+	require.False(t, record.Completed())
+
+	// the key is held by the first request until its response is saved
+	record, acquired, err = testCache.LockIdempotencyKey(context.Background(), userID, key, "fingerprint", time.Minute)
+	require.NoError(t, err)
+	require.False(t, acquired)
+	require.Equal(t, "fingerprint", record.Fingerprint)
+	require.False(t, record.Completed())
+
+	saved := IdempotencyRecord{
+		Fingerprint: "fingerprint",
+		Status:      http.StatusOK,
+		Body:        []byte(`{"id":1}`),
+	}
+	err = testCache.SaveIdempotentResponse(context.Background(), userID, key, saved, time.Minute)
+	require.NoError(t, err)
+
+	record, acquired, err = testCache.LockIdempotencyKey(context.Background(), userID, key, "other", time.Minute)
+	require.NoError(t, err)
+	require.False(t, acquired)
+	require.Equal(t, saved, record)
+
+	err = testCache.ReleaseIdempotencyKey(context.Background(), userID, key)
+	require.NoError(t, err)
+
+	_, acquired, err = testCache.LockIdempotencyKey(context.Background(), userID, key, "other", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+}
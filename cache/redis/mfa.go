@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+func mfaFailuresKey(userID string) string {
+	return fmt.Sprintf("mfa:%s", userID)
+}
+
+// MFAFailures returns the number of failed two-factor authentication attempts of a user
+// stored at `mfa:{{userID}}`.
+func (cache *RedisStore) MFAFailures(ctx context.Context, userID string) (int64, error) {
+	failures, err := cache.Client.Get(ctx, mfaFailuresKey(userID)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return failures, err
+}
+
+// AddMFAFailure counts a failed two-factor authentication attempt and returns the number of failures.
+// The count expires ttl after the first failure.
+func (cache *RedisStore) AddMFAFailure(ctx context.Context, userID string, ttl time.Duration) (int64, error) {
+	key := mfaFailuresKey(userID)
+	failures, err := cache.Client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if failures == 1 {
+		err = cache.Client.Expire(ctx, key, ttl).Err()
+	}
+	return failures, err
+}
+
+// ResetMFAFailures forgets the failed two-factor authentication attempts of a user.
+func (cache *RedisStore) ResetMFAFailures(ctx context.Context, userID string) error {
+	return cache.Client.Del(ctx, mfaFailuresKey(userID)).Err()
+}
@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMFAFailures(t *testing.T) {
+	userID := uuid.New().String()
+
+	failures, err := testCache.MFAFailures(context.Background(), userID)
+	require.NoError(t, err)
+	require.Zero(t, failures)
+
+	for i := int64(1); i <= 3; i++ {
+		failures, err = testCache.AddMFAFailure(context.Background(), userID, time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, i, failures)
+	}
+
+	failures, err = testCache.MFAFailures(context.Background(), userID)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), failures)
+
+	err = testCache.ResetMFAFailures(context.Background(), userID)
+	require.NoError(t, err)
+
+	failures, err = testCache.MFAFailures(context.Background(), userID)
+	require.NoError(t, err)
+	require.Zero(t, failures)
+}
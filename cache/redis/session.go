@@ -0,0 +1,282 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/go-redis/redis/v8"
+)
+
+// ErrRefreshTokenReused is returned when a refresh token which has already been rotated is presented again.
+var ErrRefreshTokenReused = errors.New("refresh token has already been used")
+
+// Session is a login of a user on a device. It lives as long as its refresh token keeps being refreshed.
+// All the tokens issued to a session form a family, which is revoked as a whole.
+type Session struct {
+	ID                    string    `json:"id"`
+	UserID                string    `json:"user_id"`
+	UserAgent             string    `json:"user_agent"`
+	IP                    string    `json:"ip"`
+	CreatedAt             time.Time `json:"created_at"`
+	LastUsedAt            time.Time `json:"last_used_at"`
+	AccessTokenID         string    `json:"access_token_id"`
+	RefreshTokenID        string    `json:"refresh_token_id"`
+	RefreshTokenExpiredAt time.Time `json:"refresh_token_expired_at"`
+	// AccessTokens holds the expiry of every unexpired access token of the family.
+	AccessTokens map[string]time.Time `json:"access_tokens"`
+}
+
+// refreshTokenRecord is stored with the key `rt:{{userID}}:{{tokenID}}`. A rotated refresh token is kept
+// until it expires so that presenting it again is detected as a reuse.
+type refreshTokenRecord struct {
+	SessionID string    `json:"session_id"`
+	ParentID  string    `json:"parent_id,omitempty"`
+	RotatedAt time.Time `json:"rotated_at,omitempty"`
+}
+
+func (record refreshTokenRecord) rotated() bool {
+	return !record.RotatedAt.IsZero()
+}
+
+func refreshTokenKey(userID string, tokenID string) string {
+	return fmt.Sprintf("rt:%s:%s", userID, tokenID)
+}
+
+func sessionKey(userID string, sessionID string) string {
+	return fmt.Sprintf("sess:%s:%s", userID, sessionID)
+}
+
+func sessionListKey(userID string) string {
+	return fmt.Sprintf("sessl:%s", userID)
+}
+
+// setSession stores the session with the key `sess:{{userID}}:{{sessionID}}`, ranks it by last use in the
+// sorted set `sessl:{{userID}}` and links the access and refresh tokens `at:` and `rt:` to the session.
+// The refresh token records the refresh token it replaces as its parent.
+func setSession(ctx context.Context, pipe redis.Pipeliner, session *Session, accessToken token.Payload, atd time.Duration, refreshToken token.Payload, rtd time.Duration, parentID string) error {
+	now := time.Now().UTC()
+	accessTokens := map[string]time.Time{}
+	for id, expiredAt := range session.AccessTokens {
+		if expiredAt.After(now) {
+			accessTokens[id] = expiredAt
+		}
+	}
+	accessTokens[accessToken.ID.String()] = accessToken.ExpiredAt
+
+	session.AccessTokenID = accessToken.ID.String()
+	session.AccessTokens = accessTokens
+	session.RefreshTokenID = refreshToken.ID.String()
+	session.RefreshTokenExpiredAt = refreshToken.ExpiredAt
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	record, err := json.Marshal(refreshTokenRecord{SessionID: session.ID, ParentID: parentID})
+	if err != nil {
+		return err
+	}
+
+	pipe.SetEX(ctx, fmt.Sprintf("at:%s:%s", session.UserID, session.AccessTokenID), session.ID, atd)
+	pipe.SetEX(ctx, refreshTokenKey(session.UserID, session.RefreshTokenID), record, rtd)
+	pipe.SetEX(ctx, sessionKey(session.UserID, session.ID), data, rtd)
+
+	listKey := sessionListKey(session.UserID)
+	pipe.ZAdd(ctx, listKey, &redis.Z{Score: float64(session.LastUsedAt.UnixMilli()), Member: session.ID})
+	pipe.Expire(ctx, listKey, rtd)
+	return nil
+}
+
+// revokeSessions deletes the sessions and revokes their token families with the key
+// `rev:{{userID}}:{{tokenID}}` until the tokens expire.
+func revokeSessions(ctx context.Context, pipe redis.Pipeliner, sessions []Session) {
+	now := time.Now().UTC()
+	revoke := func(userID string, tokenID string, expiredAt time.Time) {
+		expiry := expiredAt.Sub(now) + time.Minute
+		if expiry > 0 {
+			pipe.SetEX(ctx, fmt.Sprintf("rev:%s:%s", userID, tokenID), 1, expiry)
+		}
+	}
+
+	for _, session := range sessions {
+		pipe.Del(ctx,
+			fmt.Sprintf("at:%s:%s", session.UserID, session.AccessTokenID),
+			refreshTokenKey(session.UserID, session.RefreshTokenID),
+			sessionKey(session.UserID, session.ID),
+		)
+		pipe.ZRem(ctx, sessionListKey(session.UserID), session.ID)
+
+		for id, expiredAt := range session.AccessTokens {
+			revoke(session.UserID, id, expiredAt)
+		}
+		revoke(session.UserID, session.RefreshTokenID, session.RefreshTokenExpiredAt)
+	}
+}
+
+// getSessions reads the sessions by ID. Sessions which have expired are left out and removed from `sessl:{{userID}}`.
+func (cache *RedisStore) getSessions(ctx context.Context, userID string, sessionIDs []string) ([]Session, error) {
+	if len(sessionIDs) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(sessionIDs))
+	for i, id := range sessionIDs {
+		keys[i] = sessionKey(userID, id)
+	}
+	vals, err := cache.Client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]Session, 0, len(vals))
+	var expired []interface{}
+	for i, val := range vals {
+		if val == nil {
+			expired = append(expired, sessionIDs[i])
+			continue
+		}
+		var session Session
+		if err := json.Unmarshal([]byte(val.(string)), &session); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	if len(expired) > 0 {
+		if err := cache.Client.ZRem(ctx, sessionListKey(userID), expired...).Err(); err != nil {
+			return nil, err
+		}
+	}
+	return sessions, nil
+}
+
+// CreateSession starts a new session with a fresh pair of tokens. When the user already has maxSessions
+// sessions, the least recently used ones are revoked to make room for the new one.
+func (cache *RedisStore) CreateSession(ctx context.Context, session Session, accessToken token.Payload, atd time.Duration, refreshToken token.Payload, rtd time.Duration, maxSessions int) error {
+	if session.ID == "" || session.UserID == "" {
+		return errors.New("invalid session data to set in cache")
+	}
+	if accessToken.UserID.String() != session.UserID || refreshToken.UserID.String() != session.UserID {
+		return errors.New("invalid token data to set in cache")
+	}
+
+	var evicted []Session
+	if maxSessions > 0 {
+		sessionIDs, err := cache.Client.ZRange(ctx, sessionListKey(session.UserID), 0, -1).Result()
+		if err != nil {
+			return err
+		}
+		sessions, err := cache.getSessions(ctx, session.UserID, sessionIDs)
+		if err != nil {
+			return err
+		}
+		if len(sessions) >= maxSessions {
+			evicted = sessions[:len(sessions)-maxSessions+1]
+		}
+	}
+
+	pipe := cache.Client.TxPipeline()
+	revokeSessions(ctx, pipe, evicted)
+	if err := setSession(ctx, pipe, &session, accessToken, atd, refreshToken, rtd, ""); err != nil {
+		return err
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// RefreshSession rotates the previous refresh token of a session for a new pair of tokens. It returns
+// redis.Nil when the refresh token does not belong to a session anymore. When the previous refresh token
+// has already been rotated, the whole token family is revoked and ErrRefreshTokenReused is returned with
+// the revoked session, if it still existed.
+func (cache *RedisStore) RefreshSession(ctx context.Context, previous token.Payload, accessToken token.Payload, atd time.Duration, refreshToken token.Payload, rtd time.Duration) (Session, error) {
+	userID := previous.UserID.String()
+	rtKey := refreshTokenKey(userID, previous.ID.String())
+
+	var session Session
+	err := cache.Client.Watch(ctx, func(tx *redis.Tx) error {
+		data, err := tx.Get(ctx, rtKey).Bytes()
+		if err != nil {
+			return err
+		}
+		var record refreshTokenRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return err
+		}
+
+		data, err = tx.Get(ctx, sessionKey(userID, record.SessionID)).Bytes()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if err == nil {
+			if err := json.Unmarshal(data, &session); err != nil {
+				return err
+			}
+		}
+
+		if record.rotated() {
+			if session.ID != "" {
+				_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+					revokeSessions(ctx, pipe, []Session{session})
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return ErrRefreshTokenReused
+		}
+		if session.ID == "" {
+			return redis.Nil
+		}
+
+		now := time.Now().UTC()
+		record.RotatedAt = now
+		rotated, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, fmt.Sprintf("at:%s:%s", userID, session.AccessTokenID))
+			pipe.SetEX(ctx, rtKey, rotated, previous.ExpiredAt.Sub(now)+time.Minute)
+			session.LastUsedAt = now
+			return setSession(ctx, pipe, &session, accessToken, atd, refreshToken, rtd, previous.ID.String())
+		})
+		return err
+	}, rtKey)
+
+	// a concurrent request rotated the same refresh token first
+	if err == redis.TxFailedErr {
+		return Session{}, redis.Nil
+	}
+	return session, err
+}
+
+// ListSessions returns the active sessions of a user, most recently used first.
+func (cache *RedisStore) ListSessions(ctx context.Context, userID string) ([]Session, error) {
+	sessionIDs, err := cache.Client.ZRevRange(ctx, sessionListKey(userID), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	return cache.getSessions(ctx, userID, sessionIDs)
+}
+
+// DeleteSession signs a session out by deleting it and revoking its tokens.
+// It returns redis.Nil when the session does not exist.
+func (cache *RedisStore) DeleteSession(ctx context.Context, userID string, sessionID string) error {
+	sessions, err := cache.getSessions(ctx, userID, []string{sessionID})
+	if err != nil {
+		return err
+	}
+	if len(sessions) == 0 {
+		return redis.Nil
+	}
+
+	pipe := cache.Client.TxPipeline()
+	revokeSessions(ctx, pipe, sessions)
+	_, err = pipe.Exec(ctx)
+	return err
+}
@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/awakim/immoblock-backend/token"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func randomSession(userID uuid.UUID) Session {
+	now := time.Now().UTC()
+	return Session{
+		ID:         uuid.NewString(),
+		UserID:     userID.String(),
+		UserAgent:  "Mozilla/5.0",
+		IP:         "127.0.0.1",
+		CreatedAt:  now,
+		LastUsedAt: now,
+	}
+}
+
+func createRandomSession(t *testing.T, userID uuid.UUID, maxSessions int) (Session, *token.Payload, *token.Payload) {
+	at, _ := token.NewPayload(token.TypeAccess, userID, token.Access{}, time.Minute)
+	rt, _ := token.NewPayload(token.TypeRefresh, userID, token.Access{}, time.Minute)
+	session := randomSession(userID)
+
+	err := testCache.CreateSession(context.Background(), session, *at, time.Minute, *rt, time.Minute, maxSessions)
+	require.NoError(t, err)
+	return session, at, rt
+}
+
+func TestCreateSession(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+	session, at, _ := createRandomSession(t, uid, 3)
+
+	sessions, err := testCache.ListSessions(context.Background(), uid.String())
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, session.ID, sessions[0].ID)
+	require.Equal(t, session.UserAgent, sessions[0].UserAgent)
+	require.Equal(t, session.IP, sessions[0].IP)
+	require.Equal(t, at.ID.String(), sessions[0].AccessTokenID)
+
+	other, _ := uuid.NewRandom()
+	at2, _ := token.NewPayload(token.TypeAccess, other, token.Access{}, time.Minute)
+	err = testCache.CreateSession(context.Background(), randomSession(uid), *at2, time.Minute, *at2, time.Minute, 3)
+	require.Error(t, err)
+}
+
+func TestCreateSessionMaxSessions(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+
+	first, firstAT, _ := createRandomSession(t, uid, 2)
+	time.Sleep(2 * time.Millisecond)
+	second, _, _ := createRandomSession(t, uid, 2)
+	time.Sleep(2 * time.Millisecond)
+	third, _, _ := createRandomSession(t, uid, 2)
+
+	sessions, err := testCache.ListSessions(context.Background(), uid.String())
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	require.Equal(t, third.ID, sessions[0].ID)
+	require.Equal(t, second.ID, sessions[1].ID)
+
+	// the access token of the evicted session is revoked
+	revoked, err := testCache.IsRevoked(context.Background(), *firstAT)
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	err = testCache.DeleteSession(context.Background(), uid.String(), first.ID)
+	require.ErrorIs(t, err, redis.Nil)
+}
+
+func TestRefreshSession(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+	session, _, rt := createRandomSession(t, uid, 3)
+
+	newAT, _ := token.NewPayload(token.TypeAccess, uid, token.Access{}, time.Minute)
+	newRT, _ := token.NewPayload(token.TypeRefresh, uid, token.Access{}, time.Minute)
+	refreshed, err := testCache.RefreshSession(context.Background(), *rt, *newAT, time.Minute, *newRT, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, session.ID, refreshed.ID)
+	require.Equal(t, newAT.ID.String(), refreshed.AccessTokenID)
+	require.Equal(t, newRT.ID.String(), refreshed.RefreshTokenID)
+	require.True(t, refreshed.LastUsedAt.After(session.LastUsedAt) || refreshed.LastUsedAt.Equal(session.LastUsedAt))
+
+	// the previous refresh token cannot be used twice
+	replayAT, _ := token.NewPayload(token.TypeAccess, uid, token.Access{}, time.Minute)
+	replayRT, _ := token.NewPayload(token.TypeRefresh, uid, token.Access{}, time.Minute)
+	_, err = testCache.RefreshSession(context.Background(), *rt, *replayAT, time.Minute, *replayRT, time.Minute)
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+}
+
+func TestRefreshSessionReuseRevokesFamily(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+	session, at, rt := createRandomSession(t, uid, 3)
+
+	at2, _ := token.NewPayload(token.TypeAccess, uid, token.Access{}, time.Minute)
+	rt2, _ := token.NewPayload(token.TypeRefresh, uid, token.Access{}, time.Minute)
+	_, err := testCache.RefreshSession(context.Background(), *rt, *at2, time.Minute, *rt2, time.Minute)
+	require.NoError(t, err)
+
+	at3, _ := token.NewPayload(token.TypeAccess, uid, token.Access{}, time.Minute)
+	rt3, _ := token.NewPayload(token.TypeRefresh, uid, token.Access{}, time.Minute)
+	_, err = testCache.RefreshSession(context.Background(), *rt2, *at3, time.Minute, *rt3, time.Minute)
+	require.NoError(t, err)
+
+	// replaying the first refresh token revokes every token of the family
+	at4, _ := token.NewPayload(token.TypeAccess, uid, token.Access{}, time.Minute)
+	rt4, _ := token.NewPayload(token.TypeRefresh, uid, token.Access{}, time.Minute)
+	revokedSession, err := testCache.RefreshSession(context.Background(), *rt, *at4, time.Minute, *rt4, time.Minute)
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+	require.Equal(t, session.ID, revokedSession.ID)
+
+	for _, payload := range []*token.Payload{at, at2, at3, rt3} {
+		revoked, err := testCache.IsRevoked(context.Background(), *payload)
+		require.NoError(t, err)
+		require.True(t, revoked)
+	}
+	// the reused token was never issued to the family
+	revoked, err := testCache.IsRevoked(context.Background(), *at4)
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	sessions, err := testCache.ListSessions(context.Background(), uid.String())
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+
+	// the latest refresh token does not belong to a session anymore
+	_, err = testCache.RefreshSession(context.Background(), *rt3, *at4, time.Minute, *rt4, time.Minute)
+	require.ErrorIs(t, err, redis.Nil)
+}
+
+func TestDeleteSession(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+	session1, at1, rt1 := createRandomSession(t, uid, 3)
+	session2, at2, _ := createRandomSession(t, uid, 3)
+
+	err := testCache.DeleteSession(context.Background(), uid.String(), session1.ID)
+	require.NoError(t, err)
+
+	revoked, err := testCache.IsRevoked(context.Background(), *at1)
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	revoked, err = testCache.IsRevoked(context.Background(), *at2)
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	_, err = testCache.RefreshSession(context.Background(), *rt1, *at1, time.Minute, *rt1, time.Minute)
+	require.ErrorIs(t, err, redis.Nil)
+
+	sessions, err := testCache.ListSessions(context.Background(), uid.String())
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, session2.ID, sessions[0].ID)
+}
+
+func TestLogoutUser(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+	_, at, rt := createRandomSession(t, uid, 3)
+
+	err := testCache.LogoutUser(context.Background(), *at, *rt)
+	require.NoError(t, err)
+
+	revoked, err := testCache.IsRevoked(context.Background(), *rt)
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	sessions, err := testCache.ListSessions(context.Background(), uid.String())
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+}
@@ -9,20 +9,54 @@ import (
 )
 
 type Cache interface {
-	// DeleteRefreshtoken deletes old refresh tokens
-	DeleteRefreshToken(ctx context.Context, userID string, tokenID string) error
-	// SetTokenData sets the access and refresh tokens related data into Redis cache.
-	SetTokenData(ctx context.Context, accessToken token.Payload, atd time.Duration, refreshToken token.Payload, rtd time.Duration) error
+	// CreateSession starts a new session with a fresh pair of tokens. When the user already has maxSessions
+	// sessions, the least recently used ones are revoked to make room for the new one.
+	CreateSession(ctx context.Context, session Session, accessToken token.Payload, atd time.Duration, refreshToken token.Payload, rtd time.Duration, maxSessions int) error
+	// RefreshSession rotates the previous refresh token of a session for a new pair of tokens. It returns
+	// redis.Nil when the refresh token does not belong to a session anymore. When the previous refresh token
+	// has already been rotated, the whole token family is revoked and ErrRefreshTokenReused is returned with
+	// the revoked session, if it still existed.
+	RefreshSession(ctx context.Context, previous token.Payload, accessToken token.Payload, atd time.Duration, refreshToken token.Payload, rtd time.Duration) (Session, error)
+	// ListSessions returns the active sessions of a user, most recently used first.
+	ListSessions(ctx context.Context, userID string) ([]Session, error)
+	// DeleteSession signs a session out by deleting it and revoking its tokens.
+	// It returns redis.Nil when the session does not exist.
+	DeleteSession(ctx context.Context, userID string, sessionID string) error
 	// LogoutUser deletes access and refresh tokens from Cache and revoke access and refresh tokens by setting them in Cache.
+	// The session of the refresh token is deleted as well.
 	LogoutUser(ctx context.Context, accessToken token.Payload, refreshToken token.Payload) error
 	// IsRevoked checks whether a token is revoked by checking the according key `rev:{{userID}}:{{tokenID}}`.
 	// If the key present, the token is revoked, else perhaps a server error and finally if none of the
-	// previous then token is not revoked.
+	// previous then token is not revoked. A token issued before the time stored at `rvb:{{userID}}` is
+	// revoked as well.
 	IsRevoked(ctx context.Context, token token.Payload) (bool, error)
+	// RevokeUserTokens signs the user out everywhere: all the sessions are deleted and every token
+	// issued before issuedBefore is revoked by setting the key `rvb:{{userID}}` for the given duration.
+	RevokeUserTokens(ctx context.Context, userID string, issuedBefore time.Time, ttl time.Duration) error
 	// IsRateLimited checks whether a user has surpassed the limit of login or refresh routes.
 	// The rate limit is imposed as 3 requests per IP per Identifier (Email for login or UserID for refresh)
 	// per quarter hour.
 	IsRateLimited(ctx context.Context, identifier string) (bool, error)
+	// ConsumeToken marks a single-use token as used with the key `used:{{userID}}:{{tokenID}}` until it expires.
+	// It returns false when the token was already used.
+	ConsumeToken(ctx context.Context, token token.Payload) (bool, error)
+	// ReleaseToken makes a consumed single-use token usable again, when the action it authorized has failed.
+	ReleaseToken(ctx context.Context, token token.Payload) error
+	// MFAFailures returns the number of failed two-factor authentication attempts of a user
+	// stored at `mfa:{{userID}}`.
+	MFAFailures(ctx context.Context, userID string) (int64, error)
+	// AddMFAFailure counts a failed two-factor authentication attempt and returns the number of failures.
+	// The count expires ttl after the first failure.
+	AddMFAFailure(ctx context.Context, userID string, ttl time.Duration) (int64, error)
+	// ResetMFAFailures forgets the failed two-factor authentication attempts of a user.
+	ResetMFAFailures(ctx context.Context, userID string) error
+	// LockIdempotencyKey claims the key `idem:{{userID}}:{{key}}` for a request fingerprint.
+	// When the key is already taken, the existing record is returned with acquired set to false.
+	LockIdempotencyKey(ctx context.Context, userID string, key string, fingerprint string, ttl time.Duration) (record IdempotencyRecord, acquired bool, err error)
+	// SaveIdempotentResponse stores the response of a request holding the idempotency key, to be replayed.
+	SaveIdempotentResponse(ctx context.Context, userID string, key string, record IdempotencyRecord, ttl time.Duration) error
+	// ReleaseIdempotencyKey deletes the idempotency key so that the request can be retried.
+	ReleaseIdempotencyKey(ctx context.Context, userID string, key string) error
 }
 
 type RedisStore struct {
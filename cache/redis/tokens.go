@@ -2,69 +2,44 @@ package cache
 
 import (
 	"context"
-	"errors"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/awakim/immoblock-backend/token"
 	"github.com/go-redis/redis/v8"
 )
 
-// DeleteRefreshtoken deletes old refresh tokens
-func (cache *RedisStore) DeleteRefreshToken(ctx context.Context, userID string, tokenID string) error {
-	key := fmt.Sprintf("rt:%s:%s", userID, tokenID)
-	response := cache.Client.Del(ctx, key)
-	if err := response.Err(); err != nil {
-		return err
-	}
-	if response.Val() < 1 {
-		return redis.Nil
-	}
-	return nil
-}
-
-// SetTokenData sets the access and refresh tokens related data into Redis cache.
-func (cache *RedisStore) SetTokenData(ctx context.Context, accessToken token.Payload, atd time.Duration, refreshToken token.Payload, rtd time.Duration) error {
-	pipe := cache.Client.TxPipeline()
+// LogoutUser deletes access and refresh tokens from Cache and revoke access and refresh tokens by setting them in Cache.
+// The session of the refresh token is deleted and its token family revoked as well.
+func (cache *RedisStore) LogoutUser(ctx context.Context, accessToken token.Payload, refreshToken token.Payload) error {
+	userID := refreshToken.UserID.String()
+	rtKey := refreshTokenKey(userID, refreshToken.ID.String())
 
-	if accessToken.UserID.String() == "" || accessToken.ID.String() == "" {
-		return errors.New("invalid access token data to set in cache")
+	var sessions []Session
+	data, err := cache.Client.Get(ctx, rtKey).Bytes()
+	if err != nil && err != redis.Nil {
+		return err
 	}
-	at := fmt.Sprintf("at:%s:%s", accessToken.UserID.String(), accessToken.ID.String())
-	pipe.SetEX(ctx, at, 1, atd)
-
-	if refreshToken.UserID.String() == "" || refreshToken.ID.String() == "" {
-		return errors.New("invalid refresh token data to set in cache")
+	if err == nil {
+		var record refreshTokenRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return err
+		}
+		sessions, err = cache.getSessions(ctx, userID, []string{record.SessionID})
+		if err != nil {
+			return err
+		}
 	}
-	rt := fmt.Sprintf("rt:%s:%s", refreshToken.UserID.String(), refreshToken.ID.String())
-	pipe.SetEX(ctx, rt, 1, rtd)
-	atlKey := fmt.Sprintf("atl:%s", accessToken.UserID.String())
-	atlValue := []interface{}{accessToken.ID.String()}
-	pipe.LPush(ctx, atlKey, atlValue...)
-	pipe.Expire(ctx, atlKey, atd)
-
-	rtlKey := fmt.Sprintf("rtl:%s", refreshToken.UserID.String())
-	rtlValue := []interface{}{refreshToken.ID.String()}
-	pipe.LPush(ctx, rtlKey, rtlValue...)
-	pipe.Expire(ctx, rtlKey, rtd)
-
-	// allow for maximum 3 tokens simulateously.
-	pipe.LTrim(ctx, atlKey, 0, 2)
-	pipe.LTrim(ctx, rtlKey, 0, 2)
-
-	_, err := pipe.Exec(ctx)
-	return err
-}
 
-// LogoutUser deletes access and refresh tokens from Cache and revoke access and refresh tokens by setting them in Cache.
-func (cache *RedisStore) LogoutUser(ctx context.Context, accessToken token.Payload, refreshToken token.Payload) error {
 	pipe := cache.Client.TxPipeline()
+	revokeSessions(ctx, pipe, sessions)
 
-	keys := []string{
+	pipe.Del(ctx,
 		fmt.Sprintf("at:%s:%s", accessToken.UserID.String(), accessToken.ID.String()),
-		fmt.Sprintf("rt:%s:%s", refreshToken.UserID.String(), refreshToken.ID.String()),
-	}
-	pipe.Del(ctx, keys...)
+		rtKey,
+	)
 
 	// Revoke access and refresh token
 	revokedAccessTokenKey := fmt.Sprintf("rev:%s:%s", accessToken.UserID.String(), accessToken.ID.String())
@@ -74,23 +49,52 @@ func (cache *RedisStore) LogoutUser(ctx context.Context, accessToken token.Paylo
 	pipe.SetEX(ctx, revokedAccessTokenKey, 1, accessExpiry)
 	pipe.SetEX(ctx, revokedRefreshTokenKey, 1, refreshExpiry)
 
-	_, err := pipe.Exec(ctx)
+	_, err = pipe.Exec(ctx)
 	return err
 }
 
 // IsRevoked checks whether a token is revoked by checking the according key `rev:{{userID}}:{{tokenID}}`.
 // If the key present, the token is revoked, else perhaps a server error and finally if none of the
-// previous then token is not revoked.
+// previous then token is not revoked. A token issued before the second of the time stored at
+// `rvb:{{userID}}` is revoked as well.
 func (cache *RedisStore) IsRevoked(ctx context.Context, token token.Payload) (bool, error) {
 	key := fmt.Sprintf("rev:%s:%s", token.UserID.String(), token.ID.String())
-	_, err := cache.Client.Get(ctx, key).Result()
-	if err == redis.Nil {
-		return false, nil
-	} else if err != nil && err != redis.Nil {
+	rvbKey := fmt.Sprintf("rvb:%s", token.UserID.String())
+	vals, err := cache.Client.MGet(ctx, key, rvbKey).Result()
+	if err != nil {
 		return true, err
-	} else {
+	}
+	if vals[0] != nil {
 		return true, nil
 	}
+	if vals[1] != nil {
+		revokedBefore, err := strconv.ParseInt(vals[1].(string), 10, 64)
+		if err != nil {
+			return true, err
+		}
+		if token.IssuedBefore(time.Unix(0, revokedBefore)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RevokeUserTokens signs the user out everywhere: all the sessions are deleted and every token
+// issued before issuedBefore is revoked by setting the key `rvb:{{userID}}` for the given duration,
+// which should be at least the lifetime of the longest lived token.
+func (cache *RedisStore) RevokeUserTokens(ctx context.Context, userID string, issuedBefore time.Time, ttl time.Duration) error {
+	sessions, err := cache.ListSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	pipe := cache.Client.TxPipeline()
+	revokeSessions(ctx, pipe, sessions)
+	pipe.Del(ctx, sessionListKey(userID))
+	// the cutoff is kept in whole seconds, the tokens issued right after it in the same second stay valid
+	pipe.Set(ctx, fmt.Sprintf("rvb:%s", userID), issuedBefore.Truncate(time.Second).UnixNano(), ttl)
+	_, err = pipe.Exec(ctx)
+	return err
 }
 
 // IsRateLimited checks whether a user has surpassed the limit of login or refresh routes.
@@ -118,3 +122,19 @@ func (cache *RedisStore) IsRateLimited(ctx context.Context, identifier string) (
 
 	return rateLimited, err
 }
+
+// ConsumeToken marks a single-use token as used with the key `used:{{userID}}:{{tokenID}}` until it expires.
+// It returns false when the token was already used.
+func (cache *RedisStore) ConsumeToken(ctx context.Context, token token.Payload) (bool, error) {
+	expiry := token.ExpiredAt.Sub(time.Now().UTC()) + time.Minute
+	return cache.Client.SetNX(ctx, usedTokenKey(token), 1, expiry).Result()
+}
+
+// ReleaseToken makes a consumed single-use token usable again, when the action it authorized has failed.
+func (cache *RedisStore) ReleaseToken(ctx context.Context, token token.Payload) error {
+	return cache.Client.Del(ctx, usedTokenKey(token)).Err()
+}
+
+func usedTokenKey(token token.Payload) string {
+	return fmt.Sprintf("used:%s:%s", token.UserID.String(), token.ID.String())
+}
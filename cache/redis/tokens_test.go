@@ -6,48 +6,73 @@ import (
 	"time"
 
 	"github.com/awakim/immoblock-backend/token"
+	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
-func TestSetTokenData(t *testing.T) {
-	uid1, _ := uuid.NewRandom()
-	uid2, _ := uuid.NewRandom()
+func TestConsumeToken(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+	payload, _ := token.NewPayload(token.TypeAccess, uid, token.Access{}, time.Minute)
 
-	at, _ := token.NewPayload(uid1, false, time.Minute)
-	rt, _ := token.NewPayload(uid2, false, time.Minute)
+	used, err := testCache.ConsumeToken(context.Background(), *payload)
+	require.NoError(t, err)
+	require.True(t, used)
 
-	noErrorCase := struct {
-		accessToken  *token.Payload
-		refreshToken *token.Payload
-	}{
-		accessToken:  at,
-		refreshToken: rt,
-	}
+	used, err = testCache.ConsumeToken(context.Background(), *payload)
+	require.NoError(t, err)
+	require.False(t, used)
+}
 
-	err := testCache.SetTokenData(context.Background(), *noErrorCase.accessToken, time.Minute, *noErrorCase.refreshToken, time.Minute)
+func TestReleaseToken(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+	payload, _ := token.NewPayload(token.TypeAccess, uid, token.Access{}, time.Minute)
+
+	used, err := testCache.ConsumeToken(context.Background(), *payload)
 	require.NoError(t, err)
+	require.True(t, used)
+
+	err = testCache.ReleaseToken(context.Background(), *payload)
+	require.NoError(t, err)
+
+	used, err = testCache.ConsumeToken(context.Background(), *payload)
+	require.NoError(t, err)
+	require.True(t, used)
 }
 
-func TestDeleteRefreshToken(t *testing.T) {
-	uid1, _ := uuid.NewRandom()
-	uid2, _ := uuid.NewRandom()
+func TestRevokeUserTokens(t *testing.T) {
+	uid, _ := uuid.NewRandom()
 
-	at, _ := token.NewPayload(uid1, false, time.Minute)
-	rt, _ := token.NewPayload(uid2, false, time.Minute)
+	at, _ := token.NewPayload(token.TypeAccess, uid, token.Access{}, time.Minute)
+	rt, _ := token.NewPayload(token.TypeRefresh, uid, token.Access{}, time.Minute)
+	// the cutoff is compared in whole seconds, the tokens are issued the second before
+	at.IssuedAt = at.IssuedAt.Add(-time.Second)
+	rt.IssuedAt = rt.IssuedAt.Add(-time.Second)
 
-	noErrorCase := struct {
-		accessToken  *token.Payload
-		refreshToken *token.Payload
-	}{
-		accessToken:  at,
-		refreshToken: rt,
-	}
+	err := testCache.CreateSession(context.Background(), randomSession(uid), *at, time.Minute, *rt, time.Minute, 3)
+	require.NoError(t, err)
 
-	_ = testCache.SetTokenData(context.Background(), *noErrorCase.accessToken, time.Minute, *noErrorCase.refreshToken, time.Minute)
-	err := testCache.DeleteRefreshToken(context.Background(), rt.UserID.String(), rt.ID.String())
+	revoked, err := testCache.IsRevoked(context.Background(), *at)
 	require.NoError(t, err)
+	require.False(t, revoked)
 
-	err = testCache.DeleteRefreshToken(context.Background(), "invalid_key", rt.ID.String())
-	require.Error(t, err)
+	err = testCache.RevokeUserTokens(context.Background(), uid.String(), time.Now().UTC(), time.Minute)
+	require.NoError(t, err)
+
+	revoked, err = testCache.IsRevoked(context.Background(), *at)
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	sessions, err := testCache.ListSessions(context.Background(), uid.String())
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+
+	newRT, _ := token.NewPayload(token.TypeRefresh, uid, token.Access{}, time.Minute)
+	_, err = testCache.RefreshSession(context.Background(), *rt, *at, time.Minute, *newRT, time.Minute)
+	require.ErrorIs(t, err, redis.Nil)
+
+	newAT, _ := token.NewPayload(token.TypeAccess, uid, token.Access{}, time.Minute)
+	revoked, err = testCache.IsRevoked(context.Background(), *newAT)
+	require.NoError(t, err)
+	require.False(t, revoked)
 }
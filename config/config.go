@@ -4,25 +4,50 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/awakim/immoblock-backend/token"
 	"github.com/spf13/viper"
 )
 
 // Config stores all configuration of the application
 // The values are read by viper from a config file or env variables.
 type Config struct {
-	DBDriver             string        `mapstructure:"DB_DRIVER"`
-	DBSource             string        `mapstructure:"DB_SOURCE"`
-	RedisHost            string        `mapstructure:"REDIS_HOST"`
-	RedisPort            string        `mapstructure:"REDIS_PORT"`
-	ServerAddress        string        `mapstructure:"SERVER_ADDRESS"`
-	TokenSymmetricKey    string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
-	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
-	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
-	StrCorsOrigins       string        `mapstructure:"CORS_ORIGIN"`
-	CorsOrigins          []string
-	Auth0Domain          string `mapstructure:"AUTH0_DOMAIN"`
-	Auth0ClientID        string `mapstructure:"AUTH0_CLIENT_ID"`
-	Auth0ClientSecret    string `mapstructure:"AUTH0_CLIENT_SECRET"`
+	DBDriver                       string        `mapstructure:"DB_DRIVER"`
+	DBSource                       string        `mapstructure:"DB_SOURCE"`
+	RedisHost                      string        `mapstructure:"REDIS_HOST"`
+	RedisPort                      string        `mapstructure:"REDIS_PORT"`
+	ServerAddress                  string        `mapstructure:"SERVER_ADDRESS"`
+	TokenMaker                     string        `mapstructure:"TOKEN_MAKER"`
+	TokenSymmetricKey              string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	TokenIssuer                    string        `mapstructure:"TOKEN_ISSUER"`
+	TokenAudience                  string        `mapstructure:"TOKEN_AUDIENCE"`
+	AccessTokenDuration            time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	RefreshTokenDuration           time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	MaxSessions                    int           `mapstructure:"MAX_SESSIONS"`
+	IdempotencyKeyDuration         time.Duration `mapstructure:"IDEMPOTENCY_KEY_DURATION"`
+	EmailVerificationTokenDuration time.Duration `mapstructure:"EMAIL_VERIFICATION_TOKEN_DURATION"`
+	EmailVerificationURL           string        `mapstructure:"EMAIL_VERIFICATION_URL"`
+	PasswordResetTokenDuration     time.Duration `mapstructure:"PASSWORD_RESET_TOKEN_DURATION"`
+	PasswordResetURL               string        `mapstructure:"PASSWORD_RESET_URL"`
+	MFAIssuer                      string        `mapstructure:"MFA_ISSUER"`
+	MFAChallengeTokenDuration      time.Duration `mapstructure:"MFA_CHALLENGE_TOKEN_DURATION"`
+	MFATransferThreshold           int64         `mapstructure:"MFA_TRANSFER_THRESHOLD"`
+	MFAMaxAttempts                 int64         `mapstructure:"MFA_MAX_ATTEMPTS"`
+	MFALockoutDuration             time.Duration `mapstructure:"MFA_LOCKOUT_DURATION"`
+	MailDir                        string        `mapstructure:"MAIL_DIR"`
+	DocumentDir                    string        `mapstructure:"DOCUMENT_DIR"`
+	DocumentEncryptionKey          string        `mapstructure:"DOCUMENT_ENCRYPTION_KEY"`
+	DocumentMaxSize                int64         `mapstructure:"DOCUMENT_MAX_SIZE"`
+	KYCProvider                    string        `mapstructure:"KYC_PROVIDER"`
+	KYCProviderURL                 string        `mapstructure:"KYC_PROVIDER_URL"`
+	KYCAPIKey                      string        `mapstructure:"KYC_API_KEY"`
+	KYCWebhookSecret               string        `mapstructure:"KYC_WEBHOOK_SECRET"`
+	StrCorsOrigins                 string        `mapstructure:"CORS_ORIGIN"`
+	CorsOrigins                    []string
+	StrTokenKeys                   string `mapstructure:"TOKEN_KEYS"`
+	TokenKeys                      []token.Key
+	Auth0Domain                    string `mapstructure:"AUTH0_DOMAIN"`
+	Auth0ClientID                  string `mapstructure:"AUTH0_CLIENT_ID"`
+	Auth0ClientSecret              string `mapstructure:"AUTH0_CLIENT_SECRET"`
 }
 
 // LoadConfig reads configuration from file or environment variables.
@@ -40,6 +65,9 @@ func LoadConfig(path string) (config Config, err error) {
 
 	err = viper.Unmarshal(&config)
 	json.Unmarshal([]byte(config.StrCorsOrigins), &(config.CorsOrigins))
+	if err == nil && config.StrTokenKeys != "" {
+		err = json.Unmarshal([]byte(config.StrTokenKeys), &(config.TokenKeys))
+	}
 
 	return
 }
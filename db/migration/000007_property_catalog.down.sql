@@ -0,0 +1 @@
+ALTER TABLE properties DROP COLUMN archived;
\ No newline at end of file
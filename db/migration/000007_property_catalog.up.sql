@@ -0,0 +1,3 @@
+ALTER TABLE properties ADD COLUMN archived boolean NOT NULL DEFAULT FALSE;
+
+CREATE INDEX ON "properties" ("archived");
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE IF EXISTS "properties" DROP CONSTRAINT IF EXISTS "remaining_block_count_check";
+
+DROP TABLE IF EXISTS "purchases";
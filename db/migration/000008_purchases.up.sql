@@ -0,0 +1,19 @@
+CREATE TABLE "purchases" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "property_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "purchases" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "purchases" ADD FOREIGN KEY ("property_id") REFERENCES "properties" ("id");
+
+CREATE INDEX ON "purchases" ("account_id");
+
+CREATE INDEX ON "purchases" ("property_id");
+
+COMMENT ON COLUMN "purchases"."amount" IS 'must be positive';
+
+ALTER TABLE "properties" ADD CONSTRAINT "remaining_block_count_check" CHECK ("remaining_block_count" >= 0 AND "remaining_block_count" <= "initial_block_count");
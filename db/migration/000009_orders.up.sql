@@ -0,0 +1,57 @@
+CREATE TABLE "orders" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" uuid NOT NULL,
+  "account_id" bigint NOT NULL,
+  "property_id" bigint NOT NULL,
+  "side" varchar NOT NULL,
+  "price" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "remaining_amount" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'open',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "orders_side_check" CHECK ("side" IN ('buy', 'sell')),
+  CONSTRAINT "orders_status_check" CHECK ("status" IN ('open', 'filled', 'cancelled')),
+  CONSTRAINT "orders_remaining_amount_check" CHECK ("remaining_amount" >= 0 AND "remaining_amount" <= "amount")
+);
+
+CREATE TABLE "trades" (
+  "id" bigserial PRIMARY KEY,
+  "property_id" bigint NOT NULL,
+  "buy_order_id" bigint NOT NULL,
+  "sell_order_id" bigint NOT NULL,
+  "transfer_id" bigint NOT NULL,
+  "price" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "orders" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "orders" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "orders" ADD FOREIGN KEY ("property_id") REFERENCES "properties" ("id");
+
+ALTER TABLE "trades" ADD FOREIGN KEY ("property_id") REFERENCES "properties" ("id");
+
+ALTER TABLE "trades" ADD FOREIGN KEY ("buy_order_id") REFERENCES "orders" ("id");
+
+ALTER TABLE "trades" ADD FOREIGN KEY ("sell_order_id") REFERENCES "orders" ("id");
+
+ALTER TABLE "trades" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "orders" ("user_id");
+
+CREATE INDEX ON "orders" ("account_id");
+
+CREATE INDEX ON "orders" ("property_id", "side", "status", "price");
+
+CREATE INDEX ON "trades" ("property_id");
+
+COMMENT ON COLUMN "orders"."side" IS 'either buy or sell';
+
+COMMENT ON COLUMN "orders"."price" IS 'price per block, must be positive';
+
+COMMENT ON COLUMN "orders"."status" IS 'one of open, filled or cancelled';
+
+COMMENT ON COLUMN "trades"."price" IS 'execution price per block, the resting order price';
@@ -0,0 +1,7 @@
+ALTER TABLE properties DROP COLUMN currency;
+
+ALTER TABLE properties DROP COLUMN price_per_block;
+
+DROP TABLE IF EXISTS "wallet_entries";
+
+DROP TABLE IF EXISTS "wallets";
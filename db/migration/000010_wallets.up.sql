@@ -0,0 +1,41 @@
+CREATE TABLE "wallets" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" uuid NOT NULL,
+  "currency" varchar NOT NULL,
+  "balance" bigint NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "wallets_balance_check" CHECK ("balance" >= 0)
+);
+
+CREATE TABLE "wallet_entries" (
+  "id" bigserial PRIMARY KEY,
+  "wallet_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "kind" varchar NOT NULL,
+  "reference" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "wallets" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "wallets" ADD CONSTRAINT "user_id_currency_key" UNIQUE ("user_id", "currency");
+
+ALTER TABLE "wallet_entries" ADD FOREIGN KEY ("wallet_id") REFERENCES "wallets" ("id");
+
+CREATE INDEX ON "wallet_entries" ("wallet_id");
+
+ALTER TABLE properties ADD COLUMN price_per_block bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE properties ADD COLUMN currency varchar NOT NULL DEFAULT 'EUR';
+
+COMMENT ON COLUMN "wallets"."currency" IS 'ISO 4217 currency code';
+
+COMMENT ON COLUMN "wallets"."balance" IS 'in minor units, must be greater than or equal to zero';
+
+COMMENT ON COLUMN "wallet_entries"."amount" IS 'in minor units, can be negative or positive';
+
+COMMENT ON COLUMN "wallet_entries"."kind" IS 'deposit, withdrawal, purchase, trade...';
+
+COMMENT ON COLUMN "wallet_entries"."reference" IS 'payment provider or related record reference';
+
+COMMENT ON COLUMN "properties"."price_per_block" IS 'primary market price in minor units';
@@ -0,0 +1,50 @@
+CREATE TABLE "distributions" (
+  "id" bigserial PRIMARY KEY,
+  "property_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "distributed_amount" bigint NOT NULL DEFAULT 0,
+  "currency" varchar NOT NULL,
+  "period_start" date NOT NULL,
+  "period_end" date NOT NULL,
+  "snapshot_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "distributions_amount_check" CHECK ("amount" > 0 AND "distributed_amount" <= "amount"),
+  CONSTRAINT "distributions_period_check" CHECK ("period_end" >= "period_start")
+);
+
+CREATE TABLE "payouts" (
+  "id" bigserial PRIMARY KEY,
+  "distribution_id" bigint NOT NULL,
+  "account_id" bigint NOT NULL,
+  "user_id" uuid NOT NULL,
+  "wallet_entry_id" bigint NOT NULL,
+  "blocks" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "distributions" ADD FOREIGN KEY ("property_id") REFERENCES "properties" ("id");
+
+ALTER TABLE "distributions" ADD CONSTRAINT "property_id_period_key" UNIQUE ("property_id", "period_start", "period_end");
+
+ALTER TABLE "payouts" ADD FOREIGN KEY ("distribution_id") REFERENCES "distributions" ("id");
+
+ALTER TABLE "payouts" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "payouts" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "payouts" ADD FOREIGN KEY ("wallet_entry_id") REFERENCES "wallet_entries" ("id");
+
+CREATE INDEX ON "payouts" ("distribution_id");
+
+CREATE INDEX ON "payouts" ("user_id");
+
+CREATE INDEX ON "entries" ("account_id", "created_at");
+
+COMMENT ON COLUMN "distributions"."amount" IS 'total income to distribute in minor units, must be positive';
+
+COMMENT ON COLUMN "distributions"."distributed_amount" IS 'sum of the payouts, the rest belongs to unsold blocks or rounding';
+
+COMMENT ON COLUMN "distributions"."snapshot_at" IS 'holdings are taken at this time';
+
+COMMENT ON COLUMN "payouts"."blocks" IS 'blocks held at the distribution snapshot time';
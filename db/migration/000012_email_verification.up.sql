@@ -0,0 +1,6 @@
+ALTER TABLE users ADD COLUMN email_verified_at timestamptz NOT NULL DEFAULT '0001-01-01 00:00:00Z';
+
+-- accounts created before the verification flow keep their access
+UPDATE "users" SET "email_verified_at" = "created_at";
+
+COMMENT ON COLUMN "users"."email_verified_at" IS 'zero time until the email address is verified';
@@ -0,0 +1,27 @@
+CREATE TABLE "mfa_secrets" (
+  "user_id" uuid PRIMARY KEY,
+  "secret" varchar NOT NULL,
+  "confirmed_at" timestamptz NOT NULL DEFAULT '0001-01-01 00:00:00Z',
+  "last_used_step" bigint NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "recovery_codes" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" uuid NOT NULL,
+  "hashed_code" varchar NOT NULL,
+  "used_at" timestamptz NOT NULL DEFAULT '0001-01-01 00:00:00Z',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "mfa_secrets" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "recovery_codes" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+CREATE INDEX ON "recovery_codes" ("user_id");
+
+COMMENT ON COLUMN "mfa_secrets"."confirmed_at" IS 'zero time until the first code is confirmed';
+
+COMMENT ON COLUMN "mfa_secrets"."last_used_step" IS 'last accepted TOTP time step, codes cannot be replayed';
+
+COMMENT ON COLUMN "recovery_codes"."used_at" IS 'zero time until the code is used';
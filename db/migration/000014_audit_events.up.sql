@@ -0,0 +1,12 @@
+CREATE TABLE "audit_events" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" uuid NOT NULL,
+  "kind" varchar NOT NULL,
+  "ip" varchar NOT NULL DEFAULT '',
+  "details" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "audit_events" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+CREATE INDEX ON "audit_events" ("user_id", "created_at");
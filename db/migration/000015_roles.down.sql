@@ -0,0 +1,9 @@
+ALTER TABLE "users" ADD COLUMN "is_admin" boolean NOT NULL DEFAULT FALSE;
+
+UPDATE "users" SET "is_admin" = TRUE
+WHERE "id" IN (SELECT "user_id" FROM "user_roles" WHERE "role" = 'admin');
+
+DROP TABLE IF EXISTS "user_roles";
+DROP TABLE IF EXISTS "role_permissions";
+DROP TABLE IF EXISTS "permissions";
+DROP TABLE IF EXISTS "roles";
@@ -0,0 +1,70 @@
+CREATE TABLE "roles" (
+  "name" varchar PRIMARY KEY,
+  "description" varchar NOT NULL DEFAULT ''
+);
+
+CREATE TABLE "permissions" (
+  "name" varchar PRIMARY KEY,
+  "description" varchar NOT NULL DEFAULT ''
+);
+
+CREATE TABLE "role_permissions" (
+  "role" varchar NOT NULL,
+  "permission" varchar NOT NULL,
+  PRIMARY KEY ("role", "permission")
+);
+
+CREATE TABLE "user_roles" (
+  "user_id" uuid NOT NULL,
+  "role" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("user_id", "role")
+);
+
+ALTER TABLE "role_permissions" ADD FOREIGN KEY ("role") REFERENCES "roles" ("name") ON DELETE CASCADE;
+
+ALTER TABLE "role_permissions" ADD FOREIGN KEY ("permission") REFERENCES "permissions" ("name") ON DELETE CASCADE;
+
+ALTER TABLE "user_roles" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "user_roles" ADD FOREIGN KEY ("role") REFERENCES "roles" ("name") ON DELETE CASCADE;
+
+CREATE INDEX ON "user_roles" ("role");
+
+INSERT INTO "roles" ("name", "description") VALUES
+  ('investor', 'Buys property tokens and manages their own accounts'),
+  ('support', 'Assists users with their accounts'),
+  ('compliance_officer', 'Reviews the identity verifications and the audit trail'),
+  ('property_manager', 'Lists the properties and pays their distributions'),
+  ('admin', 'Has every permission');
+
+INSERT INTO "permissions" ("name", "description") VALUES
+  ('properties:write', 'Create, update and delete properties'),
+  ('distributions:write', 'Pay and list the distributions of a property'),
+  ('users:read', 'Search and view the users'),
+  ('users:write', 'Lock, unlock and log out the users'),
+  ('kyc:review', 'Approve or reject the identity verifications'),
+  ('kyc:documents:read', 'Download the identity documents'),
+  ('audit:read', 'Read the audit events'),
+  ('roles:write', 'Grant and revoke roles');
+
+INSERT INTO "role_permissions" ("role", "permission") VALUES
+  ('support', 'users:read'),
+  ('support', 'users:write'),
+  ('compliance_officer', 'users:read'),
+  ('compliance_officer', 'kyc:review'),
+  ('compliance_officer', 'kyc:documents:read'),
+  ('compliance_officer', 'audit:read'),
+  ('property_manager', 'properties:write'),
+  ('property_manager', 'distributions:write');
+
+INSERT INTO "role_permissions" ("role", "permission")
+SELECT 'admin', "name" FROM "permissions";
+
+INSERT INTO "user_roles" ("user_id", "role")
+SELECT "id", 'investor' FROM "users";
+
+INSERT INTO "user_roles" ("user_id", "role")
+SELECT "id", 'admin' FROM "users" WHERE "is_admin";
+
+ALTER TABLE "users" DROP COLUMN "is_admin";
@@ -0,0 +1,4 @@
+ALTER TABLE "audit_events" DROP COLUMN IF EXISTS "actor_id";
+ALTER TABLE "user_information" DROP COLUMN IF EXISTS "verification_reason";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "locked_reason";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "locked_at";
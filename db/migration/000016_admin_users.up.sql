@@ -0,0 +1,14 @@
+ALTER TABLE "users" ADD COLUMN "locked_at" timestamptz NOT NULL DEFAULT '0001-01-01 00:00:00Z';
+ALTER TABLE "users" ADD COLUMN "locked_reason" varchar NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN "users"."locked_at" IS 'zero time unless the user has been locked by the support team';
+
+ALTER TABLE "user_information" ADD COLUMN "verification_reason" varchar NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN "user_information"."verification_reason" IS 'why the verification was last rejected, empty otherwise';
+
+ALTER TABLE "audit_events" ADD COLUMN "actor_id" uuid;
+
+ALTER TABLE "audit_events" ADD FOREIGN KEY ("actor_id") REFERENCES "users" ("id");
+
+COMMENT ON COLUMN "audit_events"."actor_id" IS 'staff member who acted on the user, null when the user acted';
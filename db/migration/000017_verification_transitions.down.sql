@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS "verification_transitions";
+
+ALTER TABLE "user_information" DROP CONSTRAINT IF EXISTS "verification_step_check";
+ALTER TABLE "user_information" ALTER COLUMN "verification_step" SET DEFAULT 0;
+
+-- under review goes back to documents uploaded before approved takes its place as verified,
+-- rejected and expired go back to info submitted
+UPDATE "user_information" SET "verification_step" = 2 WHERE "verification_step" = 3;
+UPDATE "user_information" SET "verification_step" = 3 WHERE "verification_step" = 4;
+UPDATE "user_information" SET "verification_step" = 1 WHERE "verification_step" > 4;
+COMMENT ON COLUMN "user_information"."verification_step" IS NULL;
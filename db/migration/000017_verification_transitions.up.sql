@@ -0,0 +1,26 @@
+UPDATE "user_information" SET "verification_step" = 1 WHERE "verification_step" = 0;
+UPDATE "user_information" SET "verification_step" = 4 WHERE "verification_step" = 3;
+
+ALTER TABLE "user_information" ALTER COLUMN "verification_step" SET DEFAULT 1;
+
+ALTER TABLE "user_information" ADD CONSTRAINT "verification_step_check" CHECK ("verification_step" BETWEEN 1 AND 6);
+
+COMMENT ON COLUMN "user_information"."verification_step" IS '1 info submitted, 2 documents uploaded, 3 under review, 4 approved, 5 rejected, 6 expired';
+
+CREATE TABLE "verification_transitions" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" uuid NOT NULL,
+  "from_step" smallint NOT NULL,
+  "to_step" smallint NOT NULL,
+  "actor_id" uuid,
+  "reason" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "verification_transitions" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "verification_transitions" ADD FOREIGN KEY ("actor_id") REFERENCES "users" ("id");
+
+COMMENT ON COLUMN "verification_transitions"."actor_id" IS 'staff member who made the transition, null when the user or the verification provider did';
+
+CREATE INDEX ON "verification_transitions" ("user_id", "created_at");
@@ -0,0 +1,18 @@
+CREATE TABLE "identity_documents" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" uuid NOT NULL,
+  "kind" varchar NOT NULL,
+  "filename" varchar NOT NULL,
+  "content_type" varchar NOT NULL,
+  "size" bigint NOT NULL,
+  "storage_key" varchar UNIQUE NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "identity_documents" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "identity_documents" ADD CONSTRAINT "identity_document_kind_check" CHECK ("kind" IN ('id_card', 'passport', 'proof_of_address'));
+
+COMMENT ON COLUMN "identity_documents"."storage_key" IS 'key of the encrypted content in the document storage';
+
+CREATE INDEX ON "identity_documents" ("user_id", "created_at");
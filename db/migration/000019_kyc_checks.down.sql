@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "kyc_checks";
+
+ALTER TABLE "user_information" DROP COLUMN "kyc_applicant_id";
@@ -0,0 +1,26 @@
+ALTER TABLE "user_information" ADD COLUMN "kyc_applicant_id" varchar NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN "user_information"."kyc_applicant_id" IS 'ID of the user at the KYC provider, empty until submitted';
+
+CREATE UNIQUE INDEX ON "user_information" ("kyc_applicant_id") WHERE "kyc_applicant_id" <> '';
+
+CREATE TABLE "kyc_checks" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" uuid NOT NULL,
+  "event_id" varchar UNIQUE NOT NULL,
+  "applicant_id" varchar NOT NULL,
+  "decision" varchar NOT NULL,
+  "reason" varchar NOT NULL DEFAULT '',
+  "sanctions_hit" boolean NOT NULL DEFAULT false,
+  "pep_hit" boolean NOT NULL DEFAULT false,
+  "matches" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "kyc_checks" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+COMMENT ON COLUMN "kyc_checks"."event_id" IS 'ID of the provider webhook, a webhook delivered twice is recorded once';
+
+COMMENT ON COLUMN "kyc_checks"."matches" IS 'sanctions and PEP list entries matched, one per line';
+
+CREATE INDEX ON "kyc_checks" ("user_id", "created_at");
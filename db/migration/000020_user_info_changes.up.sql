@@ -0,0 +1,14 @@
+CREATE TABLE "user_info_changes" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" uuid NOT NULL,
+  "field" varchar NOT NULL,
+  "old_value" varchar NOT NULL,
+  "new_value" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "user_info_changes" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+COMMENT ON COLUMN "user_info_changes"."field" IS 'name of the changed column of user_information';
+
+CREATE INDEX ON "user_info_changes" ("user_id", "created_at");
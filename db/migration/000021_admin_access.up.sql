@@ -0,0 +1,7 @@
+INSERT INTO "permissions" ("name", "description") VALUES
+  ('admin:access', 'Use the back-office API under /admin');
+
+INSERT INTO "role_permissions" ("role", "permission") VALUES
+  ('support', 'admin:access'),
+  ('compliance_officer', 'admin:access'),
+  ('admin', 'admin:access');
@@ -51,6 +51,110 @@ func (mr *MockStoreMockRecorder) AddAccountBalance(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountBalance", reflect.TypeOf((*MockStore)(nil).AddAccountBalance), arg0, arg1)
 }
 
+// AddPropertyRemainingBlockCount mocks base method.
+func (m *MockStore) AddPropertyRemainingBlockCount(arg0 context.Context, arg1 db.AddPropertyRemainingBlockCountParams) (db.Property, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPropertyRemainingBlockCount", arg0, arg1)
+	ret0, _ := ret[0].(db.Property)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddPropertyRemainingBlockCount indicates an expected call of AddPropertyRemainingBlockCount.
+func (mr *MockStoreMockRecorder) AddPropertyRemainingBlockCount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPropertyRemainingBlockCount", reflect.TypeOf((*MockStore)(nil).AddPropertyRemainingBlockCount), arg0, arg1)
+}
+
+// AddUserRole mocks base method.
+func (m *MockStore) AddUserRole(arg0 context.Context, arg1 db.AddUserRoleParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUserRole", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddUserRole indicates an expected call of AddUserRole.
+func (mr *MockStoreMockRecorder) AddUserRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserRole", reflect.TypeOf((*MockStore)(nil).AddUserRole), arg0, arg1)
+}
+
+// AddWalletBalance mocks base method.
+func (m *MockStore) AddWalletBalance(arg0 context.Context, arg1 db.AddWalletBalanceParams) (db.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWalletBalance", arg0, arg1)
+	ret0, _ := ret[0].(db.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddWalletBalance indicates an expected call of AddWalletBalance.
+func (mr *MockStoreMockRecorder) AddWalletBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWalletBalance", reflect.TypeOf((*MockStore)(nil).AddWalletBalance), arg0, arg1)
+}
+
+// ArchiveProperty mocks base method.
+func (m *MockStore) ArchiveProperty(arg0 context.Context, arg1 int64) (db.Property, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveProperty", arg0, arg1)
+	ret0, _ := ret[0].(db.Property)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveProperty indicates an expected call of ArchiveProperty.
+func (mr *MockStoreMockRecorder) ArchiveProperty(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveProperty", reflect.TypeOf((*MockStore)(nil).ArchiveProperty), arg0, arg1)
+}
+
+// CancelOrder mocks base method.
+func (m *MockStore) CancelOrder(arg0 context.Context, arg1 int64) (db.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOrder", arg0, arg1)
+	ret0, _ := ret[0].(db.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelOrder indicates an expected call of CancelOrder.
+func (mr *MockStoreMockRecorder) CancelOrder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrder", reflect.TypeOf((*MockStore)(nil).CancelOrder), arg0, arg1)
+}
+
+// ConfirmMFASecret mocks base method.
+func (m *MockStore) ConfirmMFASecret(arg0 context.Context, arg1 uuid.UUID) (db.MfaSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmMFASecret", arg0, arg1)
+	ret0, _ := ret[0].(db.MfaSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmMFASecret indicates an expected call of ConfirmMFASecret.
+func (mr *MockStoreMockRecorder) ConfirmMFASecret(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmMFASecret", reflect.TypeOf((*MockStore)(nil).ConfirmMFASecret), arg0, arg1)
+}
+
+// ConfirmMFATx mocks base method.
+func (m *MockStore) ConfirmMFATx(arg0 context.Context, arg1 db.ConfirmMFATxParams) (db.ConfirmMFATxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmMFATx", arg0, arg1)
+	ret0, _ := ret[0].(db.ConfirmMFATxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmMFATx indicates an expected call of ConfirmMFATx.
+func (mr *MockStoreMockRecorder) ConfirmMFATx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmMFATx", reflect.TypeOf((*MockStore)(nil).ConfirmMFATx), arg0, arg1)
+}
+
 // CreateAccount mocks base method.
 func (m *MockStore) CreateAccount(arg0 context.Context, arg1 db.CreateAccountParams) (db.Account, error) {
 	m.ctrl.T.Helper()
@@ -66,6 +170,36 @@ func (mr *MockStoreMockRecorder) CreateAccount(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), arg0, arg1)
 }
 
+// CreateAuditEvent mocks base method.
+func (m *MockStore) CreateAuditEvent(arg0 context.Context, arg1 db.CreateAuditEventParams) (db.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuditEvent indicates an expected call of CreateAuditEvent.
+func (mr *MockStoreMockRecorder) CreateAuditEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditEvent", reflect.TypeOf((*MockStore)(nil).CreateAuditEvent), arg0, arg1)
+}
+
+// CreateDistribution mocks base method.
+func (m *MockStore) CreateDistribution(arg0 context.Context, arg1 db.CreateDistributionParams) (db.Distribution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDistribution", arg0, arg1)
+	ret0, _ := ret[0].(db.Distribution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDistribution indicates an expected call of CreateDistribution.
+func (mr *MockStoreMockRecorder) CreateDistribution(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDistribution", reflect.TypeOf((*MockStore)(nil).CreateDistribution), arg0, arg1)
+}
+
 // CreateEntry mocks base method.
 func (m *MockStore) CreateEntry(arg0 context.Context, arg1 db.CreateEntryParams) (db.Entry, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +215,96 @@ func (mr *MockStoreMockRecorder) CreateEntry(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), arg0, arg1)
 }
 
+// CreateIdentityDocument mocks base method.
+func (m *MockStore) CreateIdentityDocument(arg0 context.Context, arg1 db.CreateIdentityDocumentParams) (db.IdentityDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdentityDocument", arg0, arg1)
+	ret0, _ := ret[0].(db.IdentityDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIdentityDocument indicates an expected call of CreateIdentityDocument.
+func (mr *MockStoreMockRecorder) CreateIdentityDocument(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdentityDocument", reflect.TypeOf((*MockStore)(nil).CreateIdentityDocument), arg0, arg1)
+}
+
+// CreateIdentityDocumentTx mocks base method.
+func (m *MockStore) CreateIdentityDocumentTx(arg0 context.Context, arg1 db.CreateIdentityDocumentParams) (db.CreateIdentityDocumentTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdentityDocumentTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateIdentityDocumentTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIdentityDocumentTx indicates an expected call of CreateIdentityDocumentTx.
+func (mr *MockStoreMockRecorder) CreateIdentityDocumentTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdentityDocumentTx", reflect.TypeOf((*MockStore)(nil).CreateIdentityDocumentTx), arg0, arg1)
+}
+
+// CreateKYCCheck mocks base method.
+func (m *MockStore) CreateKYCCheck(arg0 context.Context, arg1 db.CreateKYCCheckParams) (db.KycCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateKYCCheck", arg0, arg1)
+	ret0, _ := ret[0].(db.KycCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateKYCCheck indicates an expected call of CreateKYCCheck.
+func (mr *MockStoreMockRecorder) CreateKYCCheck(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKYCCheck", reflect.TypeOf((*MockStore)(nil).CreateKYCCheck), arg0, arg1)
+}
+
+// CreateMFASecret mocks base method.
+func (m *MockStore) CreateMFASecret(arg0 context.Context, arg1 db.CreateMFASecretParams) (db.MfaSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMFASecret", arg0, arg1)
+	ret0, _ := ret[0].(db.MfaSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMFASecret indicates an expected call of CreateMFASecret.
+func (mr *MockStoreMockRecorder) CreateMFASecret(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMFASecret", reflect.TypeOf((*MockStore)(nil).CreateMFASecret), arg0, arg1)
+}
+
+// CreateOrder mocks base method.
+func (m *MockStore) CreateOrder(arg0 context.Context, arg1 db.CreateOrderParams) (db.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrder", arg0, arg1)
+	ret0, _ := ret[0].(db.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrder indicates an expected call of CreateOrder.
+func (mr *MockStoreMockRecorder) CreateOrder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockStore)(nil).CreateOrder), arg0, arg1)
+}
+
+// CreatePayout mocks base method.
+func (m *MockStore) CreatePayout(arg0 context.Context, arg1 db.CreatePayoutParams) (db.Payout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePayout", arg0, arg1)
+	ret0, _ := ret[0].(db.Payout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePayout indicates an expected call of CreatePayout.
+func (mr *MockStoreMockRecorder) CreatePayout(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayout", reflect.TypeOf((*MockStore)(nil).CreatePayout), arg0, arg1)
+}
+
 // CreateProperty mocks base method.
 func (m *MockStore) CreateProperty(arg0 context.Context, arg1 db.CreatePropertyParams) (db.Property, error) {
 	m.ctrl.T.Helper()
@@ -96,6 +320,51 @@ func (mr *MockStoreMockRecorder) CreateProperty(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProperty", reflect.TypeOf((*MockStore)(nil).CreateProperty), arg0, arg1)
 }
 
+// CreatePurchase mocks base method.
+func (m *MockStore) CreatePurchase(arg0 context.Context, arg1 db.CreatePurchaseParams) (db.Purchase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePurchase", arg0, arg1)
+	ret0, _ := ret[0].(db.Purchase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePurchase indicates an expected call of CreatePurchase.
+func (mr *MockStoreMockRecorder) CreatePurchase(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePurchase", reflect.TypeOf((*MockStore)(nil).CreatePurchase), arg0, arg1)
+}
+
+// CreateRecoveryCode mocks base method.
+func (m *MockStore) CreateRecoveryCode(arg0 context.Context, arg1 db.CreateRecoveryCodeParams) (db.RecoveryCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(db.RecoveryCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRecoveryCode indicates an expected call of CreateRecoveryCode.
+func (mr *MockStoreMockRecorder) CreateRecoveryCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecoveryCode", reflect.TypeOf((*MockStore)(nil).CreateRecoveryCode), arg0, arg1)
+}
+
+// CreateTrade mocks base method.
+func (m *MockStore) CreateTrade(arg0 context.Context, arg1 db.CreateTradeParams) (db.Trade, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTrade", arg0, arg1)
+	ret0, _ := ret[0].(db.Trade)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTrade indicates an expected call of CreateTrade.
+func (mr *MockStoreMockRecorder) CreateTrade(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTrade", reflect.TypeOf((*MockStore)(nil).CreateTrade), arg0, arg1)
+}
+
 // CreateTransfer mocks base method.
 func (m *MockStore) CreateTransfer(arg0 context.Context, arg1 db.CreateTransferParams) (db.Transfer, error) {
 	m.ctrl.T.Helper()
@@ -141,6 +410,81 @@ func (mr *MockStoreMockRecorder) CreateUserInfo(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserInfo", reflect.TypeOf((*MockStore)(nil).CreateUserInfo), arg0, arg1)
 }
 
+// CreateUserInfoChange mocks base method.
+func (m *MockStore) CreateUserInfoChange(arg0 context.Context, arg1 db.CreateUserInfoChangeParams) (db.UserInfoChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserInfoChange", arg0, arg1)
+	ret0, _ := ret[0].(db.UserInfoChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserInfoChange indicates an expected call of CreateUserInfoChange.
+func (mr *MockStoreMockRecorder) CreateUserInfoChange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserInfoChange", reflect.TypeOf((*MockStore)(nil).CreateUserInfoChange), arg0, arg1)
+}
+
+// CreateUserTx mocks base method.
+func (m *MockStore) CreateUserTx(arg0 context.Context, arg1 db.CreateUserTxParams) (db.CreateUserTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateUserTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserTx indicates an expected call of CreateUserTx.
+func (mr *MockStoreMockRecorder) CreateUserTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserTx", reflect.TypeOf((*MockStore)(nil).CreateUserTx), arg0, arg1)
+}
+
+// CreateVerificationTransition mocks base method.
+func (m *MockStore) CreateVerificationTransition(arg0 context.Context, arg1 db.CreateVerificationTransitionParams) (db.VerificationTransition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVerificationTransition", arg0, arg1)
+	ret0, _ := ret[0].(db.VerificationTransition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVerificationTransition indicates an expected call of CreateVerificationTransition.
+func (mr *MockStoreMockRecorder) CreateVerificationTransition(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVerificationTransition", reflect.TypeOf((*MockStore)(nil).CreateVerificationTransition), arg0, arg1)
+}
+
+// CreateWallet mocks base method.
+func (m *MockStore) CreateWallet(arg0 context.Context, arg1 db.CreateWalletParams) (db.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWallet", arg0, arg1)
+	ret0, _ := ret[0].(db.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWallet indicates an expected call of CreateWallet.
+func (mr *MockStoreMockRecorder) CreateWallet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWallet", reflect.TypeOf((*MockStore)(nil).CreateWallet), arg0, arg1)
+}
+
+// CreateWalletEntry mocks base method.
+func (m *MockStore) CreateWalletEntry(arg0 context.Context, arg1 db.CreateWalletEntryParams) (db.WalletEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWalletEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.WalletEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWalletEntry indicates an expected call of CreateWalletEntry.
+func (mr *MockStoreMockRecorder) CreateWalletEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWalletEntry", reflect.TypeOf((*MockStore)(nil).CreateWalletEntry), arg0, arg1)
+}
+
 // DeleteAccount mocks base method.
 func (m *MockStore) DeleteAccount(arg0 context.Context, arg1 int64) error {
 	m.ctrl.T.Helper()
@@ -155,6 +499,35 @@ func (mr *MockStoreMockRecorder) DeleteAccount(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), arg0, arg1)
 }
 
+// DeleteRecoveryCodes mocks base method.
+func (m *MockStore) DeleteRecoveryCodes(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecoveryCodes indicates an expected call of DeleteRecoveryCodes.
+func (mr *MockStoreMockRecorder) DeleteRecoveryCodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecoveryCodes", reflect.TypeOf((*MockStore)(nil).DeleteRecoveryCodes), arg0, arg1)
+}
+
+// DistributionTx mocks base method.
+func (m *MockStore) DistributionTx(arg0 context.Context, arg1 db.DistributionTxParams) (db.DistributionTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DistributionTx", arg0, arg1)
+	ret0, _ := ret[0].(db.DistributionTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DistributionTx indicates an expected call of DistributionTx.
+func (mr *MockStoreMockRecorder) DistributionTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributionTx", reflect.TypeOf((*MockStore)(nil).DistributionTx), arg0, arg1)
+}
+
 // ExistsUserInfo mocks base method.
 func (m *MockStore) ExistsUserInfo(arg0 context.Context, arg1 uuid.UUID) (bool, error) {
 	m.ctrl.T.Helper()
@@ -170,6 +543,21 @@ func (mr *MockStoreMockRecorder) ExistsUserInfo(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsUserInfo", reflect.TypeOf((*MockStore)(nil).ExistsUserInfo), arg0, arg1)
 }
 
+// FillOrder mocks base method.
+func (m *MockStore) FillOrder(arg0 context.Context, arg1 db.FillOrderParams) (db.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FillOrder", arg0, arg1)
+	ret0, _ := ret[0].(db.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FillOrder indicates an expected call of FillOrder.
+func (mr *MockStoreMockRecorder) FillOrder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FillOrder", reflect.TypeOf((*MockStore)(nil).FillOrder), arg0, arg1)
+}
+
 // GetAccount mocks base method.
 func (m *MockStore) GetAccount(arg0 context.Context, arg1 int64) (db.Account, error) {
 	m.ctrl.T.Helper()
@@ -185,6 +573,21 @@ func (mr *MockStoreMockRecorder) GetAccount(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockStore)(nil).GetAccount), arg0, arg1)
 }
 
+// GetAccountByProperty mocks base method.
+func (m *MockStore) GetAccountByProperty(arg0 context.Context, arg1 db.GetAccountByPropertyParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByProperty", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountByProperty indicates an expected call of GetAccountByProperty.
+func (mr *MockStoreMockRecorder) GetAccountByProperty(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByProperty", reflect.TypeOf((*MockStore)(nil).GetAccountByProperty), arg0, arg1)
+}
+
 // GetAccountForUpdate mocks base method.
 func (m *MockStore) GetAccountForUpdate(arg0 context.Context, arg1 int64) (db.Account, error) {
 	m.ctrl.T.Helper()
@@ -200,68 +603,263 @@ func (mr *MockStoreMockRecorder) GetAccountForUpdate(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountForUpdate), arg0, arg1)
 }
 
-// GetEntry mocks base method.
-func (m *MockStore) GetEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
+// GetBestBuyOrderForUpdate mocks base method.
+func (m *MockStore) GetBestBuyOrderForUpdate(arg0 context.Context, arg1 db.GetBestBuyOrderForUpdateParams) (db.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
-	ret0, _ := ret[0].(db.Entry)
+	ret := m.ctrl.Call(m, "GetBestBuyOrderForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEntry indicates an expected call of GetEntry.
-func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 interface{}) *gomock.Call {
+// GetBestBuyOrderForUpdate indicates an expected call of GetBestBuyOrderForUpdate.
+func (mr *MockStoreMockRecorder) GetBestBuyOrderForUpdate(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBestBuyOrderForUpdate", reflect.TypeOf((*MockStore)(nil).GetBestBuyOrderForUpdate), arg0, arg1)
 }
 
-// GetProperty mocks base method.
-func (m *MockStore) GetProperty(arg0 context.Context, arg1 int64) (db.Property, error) {
+// GetBestSellOrderForUpdate mocks base method.
+func (m *MockStore) GetBestSellOrderForUpdate(arg0 context.Context, arg1 db.GetBestSellOrderForUpdateParams) (db.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetProperty", arg0, arg1)
-	ret0, _ := ret[0].(db.Property)
+	ret := m.ctrl.Call(m, "GetBestSellOrderForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetProperty indicates an expected call of GetProperty.
-func (mr *MockStoreMockRecorder) GetProperty(arg0, arg1 interface{}) *gomock.Call {
+// GetBestSellOrderForUpdate indicates an expected call of GetBestSellOrderForUpdate.
+func (mr *MockStoreMockRecorder) GetBestSellOrderForUpdate(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProperty", reflect.TypeOf((*MockStore)(nil).GetProperty), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBestSellOrderForUpdate", reflect.TypeOf((*MockStore)(nil).GetBestSellOrderForUpdate), arg0, arg1)
 }
 
-// GetTransfer mocks base method.
-func (m *MockStore) GetTransfer(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+// GetDistribution mocks base method.
+func (m *MockStore) GetDistribution(arg0 context.Context, arg1 int64) (db.Distribution, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
-	ret0, _ := ret[0].(db.Transfer)
+	ret := m.ctrl.Call(m, "GetDistribution", arg0, arg1)
+	ret0, _ := ret[0].(db.Distribution)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTransfer indicates an expected call of GetTransfer.
-func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 interface{}) *gomock.Call {
+// GetDistribution indicates an expected call of GetDistribution.
+func (mr *MockStoreMockRecorder) GetDistribution(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDistribution", reflect.TypeOf((*MockStore)(nil).GetDistribution), arg0, arg1)
 }
 
-// GetUser mocks base method.
-func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+// GetEntry mocks base method.
+func (m *MockStore) GetEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+// GetEntry indicates an expected call of GetEntry.
+func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
 }
 
-// GetUserInfo mocks base method.
-func (m *MockStore) GetUserInfo(arg0 context.Context, arg1 uuid.UUID) (db.UserInformation, error) {
+// GetIdentityDocument mocks base method.
+func (m *MockStore) GetIdentityDocument(arg0 context.Context, arg1 db.GetIdentityDocumentParams) (db.IdentityDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdentityDocument", arg0, arg1)
+	ret0, _ := ret[0].(db.IdentityDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdentityDocument indicates an expected call of GetIdentityDocument.
+func (mr *MockStoreMockRecorder) GetIdentityDocument(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdentityDocument", reflect.TypeOf((*MockStore)(nil).GetIdentityDocument), arg0, arg1)
+}
+
+// GetMFASecret mocks base method.
+func (m *MockStore) GetMFASecret(arg0 context.Context, arg1 uuid.UUID) (db.MfaSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMFASecret", arg0, arg1)
+	ret0, _ := ret[0].(db.MfaSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMFASecret indicates an expected call of GetMFASecret.
+func (mr *MockStoreMockRecorder) GetMFASecret(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMFASecret", reflect.TypeOf((*MockStore)(nil).GetMFASecret), arg0, arg1)
+}
+
+// GetOpenBuyCost mocks base method.
+func (m *MockStore) GetOpenBuyCost(arg0 context.Context, arg1 db.GetOpenBuyCostParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenBuyCost", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenBuyCost indicates an expected call of GetOpenBuyCost.
+func (mr *MockStoreMockRecorder) GetOpenBuyCost(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenBuyCost", reflect.TypeOf((*MockStore)(nil).GetOpenBuyCost), arg0, arg1)
+}
+
+// GetOpenSellAmount mocks base method.
+func (m *MockStore) GetOpenSellAmount(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenSellAmount", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenSellAmount indicates an expected call of GetOpenSellAmount.
+func (mr *MockStoreMockRecorder) GetOpenSellAmount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenSellAmount", reflect.TypeOf((*MockStore)(nil).GetOpenSellAmount), arg0, arg1)
+}
+
+// GetOrder mocks base method.
+func (m *MockStore) GetOrder(arg0 context.Context, arg1 int64) (db.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", arg0, arg1)
+	ret0, _ := ret[0].(db.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockStoreMockRecorder) GetOrder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockStore)(nil).GetOrder), arg0, arg1)
+}
+
+// GetProperty mocks base method.
+func (m *MockStore) GetProperty(arg0 context.Context, arg1 int64) (db.Property, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProperty", arg0, arg1)
+	ret0, _ := ret[0].(db.Property)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProperty indicates an expected call of GetProperty.
+func (mr *MockStoreMockRecorder) GetProperty(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProperty", reflect.TypeOf((*MockStore)(nil).GetProperty), arg0, arg1)
+}
+
+// GetPropertyForUpdate mocks base method.
+func (m *MockStore) GetPropertyForUpdate(arg0 context.Context, arg1 int64) (db.Property, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPropertyForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Property)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPropertyForUpdate indicates an expected call of GetPropertyForUpdate.
+func (mr *MockStoreMockRecorder) GetPropertyForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPropertyForUpdate", reflect.TypeOf((*MockStore)(nil).GetPropertyForUpdate), arg0, arg1)
+}
+
+// GetPurchase mocks base method.
+func (m *MockStore) GetPurchase(arg0 context.Context, arg1 int64) (db.Purchase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurchase", arg0, arg1)
+	ret0, _ := ret[0].(db.Purchase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurchase indicates an expected call of GetPurchase.
+func (mr *MockStoreMockRecorder) GetPurchase(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurchase", reflect.TypeOf((*MockStore)(nil).GetPurchase), arg0, arg1)
+}
+
+// GetRole mocks base method.
+func (m *MockStore) GetRole(arg0 context.Context, arg1 string) (db.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRole", arg0, arg1)
+	ret0, _ := ret[0].(db.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRole indicates an expected call of GetRole.
+func (mr *MockStoreMockRecorder) GetRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRole", reflect.TypeOf((*MockStore)(nil).GetRole), arg0, arg1)
+}
+
+// GetTrade mocks base method.
+func (m *MockStore) GetTrade(arg0 context.Context, arg1 int64) (db.Trade, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrade", arg0, arg1)
+	ret0, _ := ret[0].(db.Trade)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrade indicates an expected call of GetTrade.
+func (mr *MockStoreMockRecorder) GetTrade(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrade", reflect.TypeOf((*MockStore)(nil).GetTrade), arg0, arg1)
+}
+
+// GetTransfer mocks base method.
+func (m *MockStore) GetTransfer(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransfer indicates an expected call of GetTransfer.
+func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+}
+
+// GetUser mocks base method.
+func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+}
+
+// GetUserByID mocks base method.
+func (m *MockStore) GetUserByID(arg0 context.Context, arg1 uuid.UUID) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockStoreMockRecorder) GetUserByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockStore)(nil).GetUserByID), arg0, arg1)
+}
+
+// GetUserInfo mocks base method.
+func (m *MockStore) GetUserInfo(arg0 context.Context, arg1 uuid.UUID) (db.UserInformation, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetUserInfo", arg0, arg1)
 	ret0, _ := ret[0].(db.UserInformation)
@@ -275,6 +873,96 @@ func (mr *MockStoreMockRecorder) GetUserInfo(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserInfo", reflect.TypeOf((*MockStore)(nil).GetUserInfo), arg0, arg1)
 }
 
+// GetUserInfoByKYCApplicant mocks base method.
+func (m *MockStore) GetUserInfoByKYCApplicant(arg0 context.Context, arg1 string) (db.UserInformation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserInfoByKYCApplicant", arg0, arg1)
+	ret0, _ := ret[0].(db.UserInformation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserInfoByKYCApplicant indicates an expected call of GetUserInfoByKYCApplicant.
+func (mr *MockStoreMockRecorder) GetUserInfoByKYCApplicant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserInfoByKYCApplicant", reflect.TypeOf((*MockStore)(nil).GetUserInfoByKYCApplicant), arg0, arg1)
+}
+
+// GetUserInfoForUpdate mocks base method.
+func (m *MockStore) GetUserInfoForUpdate(arg0 context.Context, arg1 uuid.UUID) (db.UserInformation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserInfoForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.UserInformation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserInfoForUpdate indicates an expected call of GetUserInfoForUpdate.
+func (mr *MockStoreMockRecorder) GetUserInfoForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserInfoForUpdate", reflect.TypeOf((*MockStore)(nil).GetUserInfoForUpdate), arg0, arg1)
+}
+
+// GetWallet mocks base method.
+func (m *MockStore) GetWallet(arg0 context.Context, arg1 int64) (db.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWallet", arg0, arg1)
+	ret0, _ := ret[0].(db.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWallet indicates an expected call of GetWallet.
+func (mr *MockStoreMockRecorder) GetWallet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWallet", reflect.TypeOf((*MockStore)(nil).GetWallet), arg0, arg1)
+}
+
+// GetWalletByCurrency mocks base method.
+func (m *MockStore) GetWalletByCurrency(arg0 context.Context, arg1 db.GetWalletByCurrencyParams) (db.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWalletByCurrency", arg0, arg1)
+	ret0, _ := ret[0].(db.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWalletByCurrency indicates an expected call of GetWalletByCurrency.
+func (mr *MockStoreMockRecorder) GetWalletByCurrency(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletByCurrency", reflect.TypeOf((*MockStore)(nil).GetWalletByCurrency), arg0, arg1)
+}
+
+// ListAccountEntries mocks base method.
+func (m *MockStore) ListAccountEntries(arg0 context.Context, arg1 db.ListAccountEntriesParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountEntries indicates an expected call of ListAccountEntries.
+func (mr *MockStoreMockRecorder) ListAccountEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountEntries", reflect.TypeOf((*MockStore)(nil).ListAccountEntries), arg0, arg1)
+}
+
+// ListAccountTransfers mocks base method.
+func (m *MockStore) ListAccountTransfers(arg0 context.Context, arg1 db.ListAccountTransfersParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountTransfers indicates an expected call of ListAccountTransfers.
+func (mr *MockStoreMockRecorder) ListAccountTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountTransfers", reflect.TypeOf((*MockStore)(nil).ListAccountTransfers), arg0, arg1)
+}
+
 // ListAccounts mocks base method.
 func (m *MockStore) ListAccounts(arg0 context.Context, arg1 db.ListAccountsParams) ([]db.Account, error) {
 	m.ctrl.T.Helper()
@@ -290,6 +978,36 @@ func (mr *MockStoreMockRecorder) ListAccounts(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), arg0, arg1)
 }
 
+// ListAuditEvents mocks base method.
+func (m *MockStore) ListAuditEvents(arg0 context.Context, arg1 db.ListAuditEventsParams) ([]db.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEvents", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEvents indicates an expected call of ListAuditEvents.
+func (mr *MockStoreMockRecorder) ListAuditEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEvents", reflect.TypeOf((*MockStore)(nil).ListAuditEvents), arg0, arg1)
+}
+
+// ListDistributions mocks base method.
+func (m *MockStore) ListDistributions(arg0 context.Context, arg1 db.ListDistributionsParams) ([]db.Distribution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDistributions", arg0, arg1)
+	ret0, _ := ret[0].([]db.Distribution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDistributions indicates an expected call of ListDistributions.
+func (mr *MockStoreMockRecorder) ListDistributions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDistributions", reflect.TypeOf((*MockStore)(nil).ListDistributions), arg0, arg1)
+}
+
 // ListEntries mocks base method.
 func (m *MockStore) ListEntries(arg0 context.Context, arg1 db.ListEntriesParams) ([]db.Entry, error) {
 	m.ctrl.T.Helper()
@@ -305,47 +1023,632 @@ func (mr *MockStoreMockRecorder) ListEntries(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), arg0, arg1)
 }
 
-// ListTransfers mocks base method.
-func (m *MockStore) ListTransfers(arg0 context.Context, arg1 db.ListTransfersParams) ([]db.Transfer, error) {
+// ListHoldingsAt mocks base method.
+func (m *MockStore) ListHoldingsAt(arg0 context.Context, arg1 db.ListHoldingsAtParams) ([]db.ListHoldingsAtRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTransfers", arg0, arg1)
-	ret0, _ := ret[0].([]db.Transfer)
+	ret := m.ctrl.Call(m, "ListHoldingsAt", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListHoldingsAtRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListTransfers indicates an expected call of ListTransfers.
-func (mr *MockStoreMockRecorder) ListTransfers(arg0, arg1 interface{}) *gomock.Call {
+// ListHoldingsAt indicates an expected call of ListHoldingsAt.
+func (mr *MockStoreMockRecorder) ListHoldingsAt(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockStore)(nil).ListTransfers), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHoldingsAt", reflect.TypeOf((*MockStore)(nil).ListHoldingsAt), arg0, arg1)
 }
 
-// TransferTx mocks base method.
-func (m *MockStore) TransferTx(arg0 context.Context, arg1 db.TransferTxParams) (db.TransferTxResult, error) {
+// ListIdentityDocuments mocks base method.
+func (m *MockStore) ListIdentityDocuments(arg0 context.Context, arg1 uuid.UUID) ([]db.IdentityDocument, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TransferTx", arg0, arg1)
-	ret0, _ := ret[0].(db.TransferTxResult)
+	ret := m.ctrl.Call(m, "ListIdentityDocuments", arg0, arg1)
+	ret0, _ := ret[0].([]db.IdentityDocument)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// TransferTx indicates an expected call of TransferTx.
-func (mr *MockStoreMockRecorder) TransferTx(arg0, arg1 interface{}) *gomock.Call {
+// ListIdentityDocuments indicates an expected call of ListIdentityDocuments.
+func (mr *MockStoreMockRecorder) ListIdentityDocuments(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTx", reflect.TypeOf((*MockStore)(nil).TransferTx), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIdentityDocuments", reflect.TypeOf((*MockStore)(nil).ListIdentityDocuments), arg0, arg1)
 }
 
-// UpdateAccount mocks base method.
-func (m *MockStore) UpdateAccount(arg0 context.Context, arg1 db.UpdateAccountParams) (db.Account, error) {
+// ListKYCChecks mocks base method.
+func (m *MockStore) ListKYCChecks(arg0 context.Context, arg1 db.ListKYCChecksParams) ([]db.KycCheck, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAccount", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "ListKYCChecks", arg0, arg1)
+	ret0, _ := ret[0].([]db.KycCheck)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateAccount indicates an expected call of UpdateAccount.
-func (mr *MockStoreMockRecorder) UpdateAccount(arg0, arg1 interface{}) *gomock.Call {
+// ListKYCChecks indicates an expected call of ListKYCChecks.
+func (mr *MockStoreMockRecorder) ListKYCChecks(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockStore)(nil).UpdateAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKYCChecks", reflect.TypeOf((*MockStore)(nil).ListKYCChecks), arg0, arg1)
+}
+
+// ListOrderBookDepth mocks base method.
+func (m *MockStore) ListOrderBookDepth(arg0 context.Context, arg1 int64) ([]db.ListOrderBookDepthRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrderBookDepth", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListOrderBookDepthRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrderBookDepth indicates an expected call of ListOrderBookDepth.
+func (mr *MockStoreMockRecorder) ListOrderBookDepth(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrderBookDepth", reflect.TypeOf((*MockStore)(nil).ListOrderBookDepth), arg0, arg1)
+}
+
+// ListOrders mocks base method.
+func (m *MockStore) ListOrders(arg0 context.Context, arg1 db.ListOrdersParams) ([]db.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", arg0, arg1)
+	ret0, _ := ret[0].([]db.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockStoreMockRecorder) ListOrders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockStore)(nil).ListOrders), arg0, arg1)
+}
+
+// ListPayouts mocks base method.
+func (m *MockStore) ListPayouts(arg0 context.Context, arg1 db.ListPayoutsParams) ([]db.ListPayoutsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPayouts", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListPayoutsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPayouts indicates an expected call of ListPayouts.
+func (mr *MockStoreMockRecorder) ListPayouts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPayouts", reflect.TypeOf((*MockStore)(nil).ListPayouts), arg0, arg1)
+}
+
+// ListProperties mocks base method.
+func (m *MockStore) ListProperties(arg0 context.Context, arg1 db.ListPropertiesParams) ([]db.Property, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProperties", arg0, arg1)
+	ret0, _ := ret[0].([]db.Property)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProperties indicates an expected call of ListProperties.
+func (mr *MockStoreMockRecorder) ListProperties(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProperties", reflect.TypeOf((*MockStore)(nil).ListProperties), arg0, arg1)
+}
+
+// ListPurchases mocks base method.
+func (m *MockStore) ListPurchases(arg0 context.Context, arg1 db.ListPurchasesParams) ([]db.Purchase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPurchases", arg0, arg1)
+	ret0, _ := ret[0].([]db.Purchase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPurchases indicates an expected call of ListPurchases.
+func (mr *MockStoreMockRecorder) ListPurchases(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPurchases", reflect.TypeOf((*MockStore)(nil).ListPurchases), arg0, arg1)
+}
+
+// ListRoles mocks base method.
+func (m *MockStore) ListRoles(arg0 context.Context) ([]db.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoles", arg0)
+	ret0, _ := ret[0].([]db.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRoles indicates an expected call of ListRoles.
+func (mr *MockStoreMockRecorder) ListRoles(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoles", reflect.TypeOf((*MockStore)(nil).ListRoles), arg0)
+}
+
+// ListTrades mocks base method.
+func (m *MockStore) ListTrades(arg0 context.Context, arg1 db.ListTradesParams) ([]db.Trade, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrades", arg0, arg1)
+	ret0, _ := ret[0].([]db.Trade)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrades indicates an expected call of ListTrades.
+func (mr *MockStoreMockRecorder) ListTrades(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrades", reflect.TypeOf((*MockStore)(nil).ListTrades), arg0, arg1)
+}
+
+// ListTransfers mocks base method.
+func (m *MockStore) ListTransfers(arg0 context.Context, arg1 db.ListTransfersParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransfers indicates an expected call of ListTransfers.
+func (mr *MockStoreMockRecorder) ListTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockStore)(nil).ListTransfers), arg0, arg1)
+}
+
+// ListUserActivity mocks base method.
+func (m *MockStore) ListUserActivity(arg0 context.Context, arg1 db.ListUserActivityParams) ([]db.ListUserActivityRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserActivity", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListUserActivityRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserActivity indicates an expected call of ListUserActivity.
+func (mr *MockStoreMockRecorder) ListUserActivity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserActivity", reflect.TypeOf((*MockStore)(nil).ListUserActivity), arg0, arg1)
+}
+
+// ListUserInfoChanges mocks base method.
+func (m *MockStore) ListUserInfoChanges(arg0 context.Context, arg1 db.ListUserInfoChangesParams) ([]db.UserInfoChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserInfoChanges", arg0, arg1)
+	ret0, _ := ret[0].([]db.UserInfoChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserInfoChanges indicates an expected call of ListUserInfoChanges.
+func (mr *MockStoreMockRecorder) ListUserInfoChanges(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserInfoChanges", reflect.TypeOf((*MockStore)(nil).ListUserInfoChanges), arg0, arg1)
+}
+
+// ListUserPermissions mocks base method.
+func (m *MockStore) ListUserPermissions(arg0 context.Context, arg1 uuid.UUID) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserPermissions", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserPermissions indicates an expected call of ListUserPermissions.
+func (mr *MockStoreMockRecorder) ListUserPermissions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserPermissions", reflect.TypeOf((*MockStore)(nil).ListUserPermissions), arg0, arg1)
+}
+
+// ListUserRoles mocks base method.
+func (m *MockStore) ListUserRoles(arg0 context.Context, arg1 uuid.UUID) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserRoles", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserRoles indicates an expected call of ListUserRoles.
+func (mr *MockStoreMockRecorder) ListUserRoles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserRoles", reflect.TypeOf((*MockStore)(nil).ListUserRoles), arg0, arg1)
+}
+
+// ListUserTransfers mocks base method.
+func (m *MockStore) ListUserTransfers(arg0 context.Context, arg1 db.ListUserTransfersParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserTransfers indicates an expected call of ListUserTransfers.
+func (mr *MockStoreMockRecorder) ListUserTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserTransfers", reflect.TypeOf((*MockStore)(nil).ListUserTransfers), arg0, arg1)
+}
+
+// ListVerificationTransitions mocks base method.
+func (m *MockStore) ListVerificationTransitions(arg0 context.Context, arg1 db.ListVerificationTransitionsParams) ([]db.VerificationTransition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVerificationTransitions", arg0, arg1)
+	ret0, _ := ret[0].([]db.VerificationTransition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVerificationTransitions indicates an expected call of ListVerificationTransitions.
+func (mr *MockStoreMockRecorder) ListVerificationTransitions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVerificationTransitions", reflect.TypeOf((*MockStore)(nil).ListVerificationTransitions), arg0, arg1)
+}
+
+// ListWalletEntries mocks base method.
+func (m *MockStore) ListWalletEntries(arg0 context.Context, arg1 db.ListWalletEntriesParams) ([]db.WalletEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWalletEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.WalletEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWalletEntries indicates an expected call of ListWalletEntries.
+func (mr *MockStoreMockRecorder) ListWalletEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWalletEntries", reflect.TypeOf((*MockStore)(nil).ListWalletEntries), arg0, arg1)
+}
+
+// ListWallets mocks base method.
+func (m *MockStore) ListWallets(arg0 context.Context, arg1 uuid.UUID) ([]db.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWallets", arg0, arg1)
+	ret0, _ := ret[0].([]db.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWallets indicates an expected call of ListWallets.
+func (mr *MockStoreMockRecorder) ListWallets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWallets", reflect.TypeOf((*MockStore)(nil).ListWallets), arg0, arg1)
+}
+
+// LockUser mocks base method.
+func (m *MockStore) LockUser(arg0 context.Context, arg1 db.LockUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockUser indicates an expected call of LockUser.
+func (mr *MockStoreMockRecorder) LockUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockUser", reflect.TypeOf((*MockStore)(nil).LockUser), arg0, arg1)
+}
+
+// PlaceOrderTx mocks base method.
+func (m *MockStore) PlaceOrderTx(arg0 context.Context, arg1 db.PlaceOrderTxParams) (db.PlaceOrderTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceOrderTx", arg0, arg1)
+	ret0, _ := ret[0].(db.PlaceOrderTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceOrderTx indicates an expected call of PlaceOrderTx.
+func (mr *MockStoreMockRecorder) PlaceOrderTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceOrderTx", reflect.TypeOf((*MockStore)(nil).PlaceOrderTx), arg0, arg1)
+}
+
+// PurchaseTx mocks base method.
+func (m *MockStore) PurchaseTx(arg0 context.Context, arg1 db.PurchaseTxParams) (db.PurchaseTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurchaseTx", arg0, arg1)
+	ret0, _ := ret[0].(db.PurchaseTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurchaseTx indicates an expected call of PurchaseTx.
+func (mr *MockStoreMockRecorder) PurchaseTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurchaseTx", reflect.TypeOf((*MockStore)(nil).PurchaseTx), arg0, arg1)
+}
+
+// RecordKYCCheckTx mocks base method.
+func (m *MockStore) RecordKYCCheckTx(arg0 context.Context, arg1 db.RecordKYCCheckTxParams) (db.RecordKYCCheckTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordKYCCheckTx", arg0, arg1)
+	ret0, _ := ret[0].(db.RecordKYCCheckTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordKYCCheckTx indicates an expected call of RecordKYCCheckTx.
+func (mr *MockStoreMockRecorder) RecordKYCCheckTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordKYCCheckTx", reflect.TypeOf((*MockStore)(nil).RecordKYCCheckTx), arg0, arg1)
+}
+
+// RemoveUserRole mocks base method.
+func (m *MockStore) RemoveUserRole(arg0 context.Context, arg1 db.RemoveUserRoleParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveUserRole", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveUserRole indicates an expected call of RemoveUserRole.
+func (mr *MockStoreMockRecorder) RemoveUserRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUserRole", reflect.TypeOf((*MockStore)(nil).RemoveUserRole), arg0, arg1)
+}
+
+// SearchUsers mocks base method.
+func (m *MockStore) SearchUsers(arg0 context.Context, arg1 db.SearchUsersParams) ([]db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchUsers", arg0, arg1)
+	ret0, _ := ret[0].([]db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchUsers indicates an expected call of SearchUsers.
+func (mr *MockStoreMockRecorder) SearchUsers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUsers", reflect.TypeOf((*MockStore)(nil).SearchUsers), arg0, arg1)
+}
+
+// SetDistributedAmount mocks base method.
+func (m *MockStore) SetDistributedAmount(arg0 context.Context, arg1 db.SetDistributedAmountParams) (db.Distribution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDistributedAmount", arg0, arg1)
+	ret0, _ := ret[0].(db.Distribution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDistributedAmount indicates an expected call of SetDistributedAmount.
+func (mr *MockStoreMockRecorder) SetDistributedAmount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDistributedAmount", reflect.TypeOf((*MockStore)(nil).SetDistributedAmount), arg0, arg1)
+}
+
+// SetKYCApplicant mocks base method.
+func (m *MockStore) SetKYCApplicant(arg0 context.Context, arg1 db.SetKYCApplicantParams) (db.UserInformation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetKYCApplicant", arg0, arg1)
+	ret0, _ := ret[0].(db.UserInformation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetKYCApplicant indicates an expected call of SetKYCApplicant.
+func (mr *MockStoreMockRecorder) SetKYCApplicant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKYCApplicant", reflect.TypeOf((*MockStore)(nil).SetKYCApplicant), arg0, arg1)
+}
+
+// SetWalletEntryReference mocks base method.
+func (m *MockStore) SetWalletEntryReference(arg0 context.Context, arg1 db.SetWalletEntryReferenceParams) (db.WalletEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWalletEntryReference", arg0, arg1)
+	ret0, _ := ret[0].(db.WalletEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetWalletEntryReference indicates an expected call of SetWalletEntryReference.
+func (mr *MockStoreMockRecorder) SetWalletEntryReference(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWalletEntryReference", reflect.TypeOf((*MockStore)(nil).SetWalletEntryReference), arg0, arg1)
+}
+
+// SubmitKYCApplicantTx mocks base method.
+func (m *MockStore) SubmitKYCApplicantTx(arg0 context.Context, arg1 db.SubmitKYCApplicantTxParams) (db.TransitionVerificationTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitKYCApplicantTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TransitionVerificationTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitKYCApplicantTx indicates an expected call of SubmitKYCApplicantTx.
+func (mr *MockStoreMockRecorder) SubmitKYCApplicantTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitKYCApplicantTx", reflect.TypeOf((*MockStore)(nil).SubmitKYCApplicantTx), arg0, arg1)
+}
+
+// TransferTx mocks base method.
+func (m *MockStore) TransferTx(arg0 context.Context, arg1 db.TransferTxParams) (db.TransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferTx indicates an expected call of TransferTx.
+func (mr *MockStoreMockRecorder) TransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTx", reflect.TypeOf((*MockStore)(nil).TransferTx), arg0, arg1)
+}
+
+// TransitionVerificationTx mocks base method.
+func (m *MockStore) TransitionVerificationTx(arg0 context.Context, arg1 db.TransitionVerificationTxParams) (db.TransitionVerificationTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransitionVerificationTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TransitionVerificationTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransitionVerificationTx indicates an expected call of TransitionVerificationTx.
+func (mr *MockStoreMockRecorder) TransitionVerificationTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionVerificationTx", reflect.TypeOf((*MockStore)(nil).TransitionVerificationTx), arg0, arg1)
+}
+
+// UnlockUser mocks base method.
+func (m *MockStore) UnlockUser(arg0 context.Context, arg1 uuid.UUID) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnlockUser indicates an expected call of UnlockUser.
+func (mr *MockStoreMockRecorder) UnlockUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockUser", reflect.TypeOf((*MockStore)(nil).UnlockUser), arg0, arg1)
+}
+
+// UpdateAccount mocks base method.
+func (m *MockStore) UpdateAccount(arg0 context.Context, arg1 db.UpdateAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccount indicates an expected call of UpdateAccount.
+func (mr *MockStoreMockRecorder) UpdateAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockStore)(nil).UpdateAccount), arg0, arg1)
+}
+
+// UpdateProperty mocks base method.
+func (m *MockStore) UpdateProperty(arg0 context.Context, arg1 db.UpdatePropertyParams) (db.Property, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProperty", arg0, arg1)
+	ret0, _ := ret[0].(db.Property)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProperty indicates an expected call of UpdateProperty.
+func (mr *MockStoreMockRecorder) UpdateProperty(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProperty", reflect.TypeOf((*MockStore)(nil).UpdateProperty), arg0, arg1)
+}
+
+// UpdateUserInfo mocks base method.
+func (m *MockStore) UpdateUserInfo(arg0 context.Context, arg1 db.UpdateUserInfoParams) (db.UserInformation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserInfo", arg0, arg1)
+	ret0, _ := ret[0].(db.UserInformation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserInfo indicates an expected call of UpdateUserInfo.
+func (mr *MockStoreMockRecorder) UpdateUserInfo(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserInfo", reflect.TypeOf((*MockStore)(nil).UpdateUserInfo), arg0, arg1)
+}
+
+// UpdateUserInfoTx mocks base method.
+func (m *MockStore) UpdateUserInfoTx(arg0 context.Context, arg1 db.UpdateUserInfoTxParams) (db.UpdateUserInfoTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserInfoTx", arg0, arg1)
+	ret0, _ := ret[0].(db.UpdateUserInfoTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserInfoTx indicates an expected call of UpdateUserInfoTx.
+func (mr *MockStoreMockRecorder) UpdateUserInfoTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserInfoTx", reflect.TypeOf((*MockStore)(nil).UpdateUserInfoTx), arg0, arg1)
+}
+
+// UpdateUserPassword mocks base method.
+func (m *MockStore) UpdateUserPassword(arg0 context.Context, arg1 db.UpdateUserPasswordParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserPassword", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserPassword indicates an expected call of UpdateUserPassword.
+func (mr *MockStoreMockRecorder) UpdateUserPassword(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPassword", reflect.TypeOf((*MockStore)(nil).UpdateUserPassword), arg0, arg1)
+}
+
+// UpdateVerificationStep mocks base method.
+func (m *MockStore) UpdateVerificationStep(arg0 context.Context, arg1 db.UpdateVerificationStepParams) (db.UserInformation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVerificationStep", arg0, arg1)
+	ret0, _ := ret[0].(db.UserInformation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateVerificationStep indicates an expected call of UpdateVerificationStep.
+func (mr *MockStoreMockRecorder) UpdateVerificationStep(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVerificationStep", reflect.TypeOf((*MockStore)(nil).UpdateVerificationStep), arg0, arg1)
+}
+
+// UseMFAStep mocks base method.
+func (m *MockStore) UseMFAStep(arg0 context.Context, arg1 db.UseMFAStepParams) (db.MfaSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseMFAStep", arg0, arg1)
+	ret0, _ := ret[0].(db.MfaSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseMFAStep indicates an expected call of UseMFAStep.
+func (mr *MockStoreMockRecorder) UseMFAStep(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseMFAStep", reflect.TypeOf((*MockStore)(nil).UseMFAStep), arg0, arg1)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockStore) UseRecoveryCode(arg0 context.Context, arg1 db.UseRecoveryCodeParams) (db.RecoveryCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(db.RecoveryCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockStoreMockRecorder) UseRecoveryCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockStore)(nil).UseRecoveryCode), arg0, arg1)
+}
+
+// VerifyUserEmail mocks base method.
+func (m *MockStore) VerifyUserEmail(arg0 context.Context, arg1 uuid.UUID) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyUserEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyUserEmail indicates an expected call of VerifyUserEmail.
+func (mr *MockStoreMockRecorder) VerifyUserEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyUserEmail", reflect.TypeOf((*MockStore)(nil).VerifyUserEmail), arg0, arg1)
+}
+
+// WalletTx mocks base method.
+func (m *MockStore) WalletTx(arg0 context.Context, arg1 db.WalletTxParams) (db.WalletTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WalletTx", arg0, arg1)
+	ret0, _ := ret[0].(db.WalletTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WalletTx indicates an expected call of WalletTx.
+func (mr *MockStoreMockRecorder) WalletTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WalletTx", reflect.TypeOf((*MockStore)(nil).WalletTx), arg0, arg1)
 }
@@ -37,4 +37,7 @@ RETURNING *;
 
 -- name: DeleteAccount :exec
 DELETE FROM accounts 
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+-- name: GetAccountByProperty :one
+SELECT * FROM accounts
+WHERE user_id = $1 AND property_id = $2 LIMIT 1;
@@ -0,0 +1,20 @@
+-- name: ListUserActivity :many
+SELECT kind, id, account_id, property_id, amount, direction, counterparty_account_id, created_at FROM (
+  SELECT 'transfer' AS kind, t.id, a.id AS account_id, a.property_id, -t.amount AS amount, 'out' AS direction, t.to_account_id AS counterparty_account_id, t.created_at
+  FROM transfers t
+  JOIN accounts a ON a.id = t.from_account_id
+  WHERE a.user_id = sqlc.arg(user_id)
+  UNION ALL
+  SELECT 'transfer' AS kind, t.id, a.id AS account_id, a.property_id, t.amount, 'in' AS direction, t.from_account_id AS counterparty_account_id, t.created_at
+  FROM transfers t
+  JOIN accounts a ON a.id = t.to_account_id
+  WHERE a.user_id = sqlc.arg(user_id)
+  UNION ALL
+  SELECT 'purchase' AS kind, p.id, a.id AS account_id, a.property_id, p.amount, 'in' AS direction, NULL::bigint AS counterparty_account_id, p.created_at
+  FROM purchases p
+  JOIN accounts a ON a.id = p.account_id
+  WHERE a.user_id = sqlc.arg(user_id)
+) AS activity
+WHERE (created_at, kind, id, account_id) < (sqlc.arg(cursor_time)::timestamptz, sqlc.arg(cursor_kind)::text, sqlc.arg(cursor_id)::bigint, sqlc.arg(cursor_account_id)::bigint)
+ORDER BY created_at DESC, kind DESC, id DESC, account_id DESC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,17 @@
+-- name: CreateAuditEvent :one
+INSERT INTO audit_events (
+  user_id,
+  kind,
+  ip,
+  details,
+  actor_id
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ListAuditEvents :many
+SELECT * FROM audit_events
+WHERE user_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
@@ -0,0 +1,67 @@
+-- name: CreateDistribution :one
+INSERT INTO distributions (
+  property_id,
+  amount,
+  currency,
+  period_start,
+  period_end,
+  snapshot_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetDistribution :one
+SELECT * FROM distributions
+WHERE id = $1 LIMIT 1;
+
+-- name: ListDistributions :many
+SELECT * FROM distributions
+WHERE property_id = $1
+ORDER BY period_start DESC, id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: SetDistributedAmount :one
+UPDATE distributions
+SET distributed_amount = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: ListHoldingsAt :many
+SELECT a.id AS account_id, a.user_id, SUM(e.amount)::bigint AS blocks FROM accounts a
+JOIN entries e ON e.account_id = a.id
+WHERE a.property_id = sqlc.arg(property_id) AND e.created_at <= sqlc.arg(snapshot_at)
+GROUP BY a.id, a.user_id
+HAVING SUM(e.amount) > 0
+ORDER BY a.id;
+
+-- name: CreatePayout :one
+INSERT INTO payouts (
+  distribution_id,
+  account_id,
+  user_id,
+  wallet_entry_id,
+  blocks,
+  amount
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: ListPayouts :many
+SELECT
+  p.id,
+  p.distribution_id,
+  d.property_id,
+  p.account_id,
+  p.blocks,
+  p.amount,
+  d.currency,
+  d.period_start,
+  d.period_end,
+  p.created_at
+FROM payouts p
+JOIN distributions d ON d.id = p.distribution_id
+WHERE p.user_id = $1
+ORDER BY p.id DESC
+LIMIT $2
+OFFSET $3;
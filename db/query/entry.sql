@@ -15,4 +15,14 @@ SELECT * FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
-OFFSET $3;
\ No newline at end of file
+OFFSET $3;
+
+-- name: ListAccountEntries :many
+SELECT * FROM entries
+WHERE
+    account_id = sqlc.arg(account_id) AND
+    id < sqlc.arg(cursor) AND
+    created_at >= sqlc.arg(start_time) AND
+    created_at < sqlc.arg(end_time)
+ORDER BY id DESC
+LIMIT sqlc.arg(limit);
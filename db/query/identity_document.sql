@@ -0,0 +1,21 @@
+-- name: CreateIdentityDocument :one
+INSERT INTO identity_documents (
+  user_id,
+  kind,
+  filename,
+  content_type,
+  size,
+  storage_key
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetIdentityDocument :one
+SELECT * FROM identity_documents
+WHERE id = $1 AND user_id = $2
+LIMIT 1;
+
+-- name: ListIdentityDocuments :many
+SELECT * FROM identity_documents
+WHERE user_id = $1
+ORDER BY id DESC;
@@ -0,0 +1,22 @@
+-- name: CreateKYCCheck :one
+INSERT INTO kyc_checks (
+  user_id,
+  event_id,
+  applicant_id,
+  decision,
+  reason,
+  sanctions_hit,
+  pep_hit,
+  matches
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+)
+ON CONFLICT (event_id) DO NOTHING
+RETURNING *;
+
+-- name: ListKYCChecks :many
+SELECT * FROM kyc_checks
+WHERE user_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
@@ -0,0 +1,45 @@
+-- name: CreateMFASecret :one
+INSERT INTO mfa_secrets (
+  user_id,
+  secret
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret, created_at = now()
+WHERE mfa_secrets.confirmed_at = '0001-01-01 00:00:00Z'
+RETURNING *;
+
+-- name: GetMFASecret :one
+SELECT * FROM mfa_secrets
+WHERE user_id = $1 LIMIT 1;
+
+-- name: ConfirmMFASecret :one
+UPDATE mfa_secrets
+SET confirmed_at = now()
+WHERE user_id = $1
+RETURNING *;
+
+-- name: UseMFAStep :one
+UPDATE mfa_secrets
+SET last_used_step = sqlc.arg(step)
+WHERE user_id = sqlc.arg(user_id) AND last_used_step < sqlc.arg(step)
+RETURNING *;
+
+-- name: CreateRecoveryCode :one
+INSERT INTO recovery_codes (
+  user_id,
+  hashed_code
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM recovery_codes
+WHERE user_id = $1;
+
+-- name: UseRecoveryCode :one
+UPDATE recovery_codes
+SET used_at = now()
+WHERE user_id = $1 AND hashed_code = $2 AND used_at = '0001-01-01 00:00:00Z'
+RETURNING *;
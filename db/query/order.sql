@@ -0,0 +1,79 @@
+-- name: CreateOrder :one
+INSERT INTO orders (
+  user_id,
+  account_id,
+  property_id,
+  side,
+  price,
+  amount,
+  remaining_amount
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $6
+) RETURNING *;
+
+-- name: GetOrder :one
+SELECT * FROM orders
+WHERE id = $1 LIMIT 1;
+
+-- name: ListOrders :many
+SELECT * FROM orders
+WHERE user_id = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: GetBestSellOrderForUpdate :one
+SELECT * FROM orders
+WHERE
+    property_id = $1 AND
+    side = 'sell' AND
+    status = 'open' AND
+    price <= $2 AND
+    user_id <> $3
+ORDER BY price, id
+LIMIT 1
+FOR UPDATE;
+
+-- name: GetBestBuyOrderForUpdate :one
+SELECT * FROM orders
+WHERE
+    property_id = $1 AND
+    side = 'buy' AND
+    status = 'open' AND
+    price >= $2 AND
+    user_id <> $3
+ORDER BY price DESC, id
+LIMIT 1
+FOR UPDATE;
+
+-- name: GetOpenSellAmount :one
+SELECT COALESCE(SUM(remaining_amount), 0)::bigint AS open_amount FROM orders
+WHERE account_id = $1 AND side = 'sell' AND status = 'open';
+
+-- name: FillOrder :one
+UPDATE orders
+SET
+  remaining_amount = remaining_amount - sqlc.arg(amount),
+  status = CASE WHEN remaining_amount = sqlc.arg(amount) THEN 'filled' ELSE status END,
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CancelOrder :one
+UPDATE orders
+SET
+  status = 'cancelled',
+  updated_at = now()
+WHERE id = $1 AND status = 'open'
+RETURNING *;
+
+-- name: ListOrderBookDepth :many
+SELECT side, price, SUM(remaining_amount)::bigint AS amount, COUNT(*) AS order_count FROM orders
+WHERE property_id = $1 AND status = 'open'
+GROUP BY side, price
+ORDER BY side, price;
+
+-- name: GetOpenBuyCost :one
+SELECT COALESCE(SUM(o.price * o.remaining_amount), 0)::bigint AS open_cost FROM orders o
+JOIN properties p ON p.id = o.property_id
+WHERE o.user_id = $1 AND o.side = 'buy' AND o.status = 'open' AND p.currency = $2;
@@ -3,11 +3,54 @@ INSERT INTO properties (
   "name",
   "description",
   initial_block_count,
-  remaining_block_count
+  remaining_block_count,
+  price_per_block,
+  currency
 ) VALUES (
-  $1, $2, $3, $4
+  $1, $2, $3, $4, $5, $6
 ) RETURNING *;
 
 -- name: GetProperty :one
 SELECT * FROM properties
-WHERE id = $1 LIMIT 1;
\ No newline at end of file
+WHERE id = $1 LIMIT 1;
+
+-- name: ListProperties :many
+SELECT * FROM properties
+WHERE
+    archived = FALSE AND
+    "name" ILIKE sqlc.arg(name_pattern) AND
+    remaining_block_count >= sqlc.arg(min_remaining_block_count)
+ORDER BY id
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: UpdateProperty :one
+UPDATE properties
+SET
+  "name" = $2,
+  "description" = $3,
+  price_per_block = $4,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ArchiveProperty :one
+UPDATE properties
+SET
+  archived = TRUE,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetPropertyForUpdate :one
+SELECT * FROM properties
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: AddPropertyRemainingBlockCount :one
+UPDATE properties
+SET
+  remaining_block_count = remaining_block_count + sqlc.arg(amount),
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
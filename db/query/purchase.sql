@@ -0,0 +1,19 @@
+-- name: CreatePurchase :one
+INSERT INTO purchases (
+  account_id,
+  property_id,
+  amount
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: GetPurchase :one
+SELECT * FROM purchases
+WHERE id = $1 LIMIT 1;
+
+-- name: ListPurchases :many
+SELECT * FROM purchases
+WHERE account_id = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
@@ -0,0 +1,30 @@
+-- name: ListRoles :many
+SELECT * FROM roles
+ORDER BY name;
+
+-- name: GetRole :one
+SELECT * FROM roles
+WHERE name = $1 LIMIT 1;
+
+-- name: ListUserRoles :many
+SELECT role FROM user_roles
+WHERE user_id = $1
+ORDER BY role;
+
+-- name: ListUserPermissions :many
+SELECT DISTINCT rp.permission FROM role_permissions rp
+JOIN user_roles ur ON ur.role = rp.role
+WHERE ur.user_id = $1
+ORDER BY rp.permission;
+
+-- name: AddUserRole :exec
+INSERT INTO user_roles (
+  user_id,
+  role
+) VALUES (
+  $1, $2
+) ON CONFLICT DO NOTHING;
+
+-- name: RemoveUserRole :execrows
+DELETE FROM user_roles
+WHERE user_id = $1 AND role = $2;
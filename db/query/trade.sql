@@ -0,0 +1,22 @@
+-- name: CreateTrade :one
+INSERT INTO trades (
+  property_id,
+  buy_order_id,
+  sell_order_id,
+  transfer_id,
+  price,
+  amount
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetTrade :one
+SELECT * FROM trades
+WHERE id = $1 LIMIT 1;
+
+-- name: ListTrades :many
+SELECT * FROM trades
+WHERE property_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
@@ -14,8 +14,27 @@ WHERE id = $1 LIMIT 1;
 -- name: ListTransfers :many
 SELECT * FROM transfers
 WHERE 
-    from_account_id = $1 OR
-    to_account_id = $2
+    from_account_id = sqlc.arg(account_id) OR
+    to_account_id = sqlc.arg(account_id)
 ORDER BY id
-LIMIT $3
-OFFSET $4;
\ No newline at end of file
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: ListAccountTransfers :many
+SELECT * FROM transfers
+WHERE
+    (from_account_id = sqlc.arg(account_id) OR to_account_id = sqlc.arg(account_id)) AND
+    id < sqlc.arg(cursor) AND
+    created_at >= sqlc.arg(start_time) AND
+    created_at < sqlc.arg(end_time)
+ORDER BY id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: ListUserTransfers :many
+SELECT * FROM transfers
+WHERE
+    from_account_id IN (SELECT id FROM accounts WHERE user_id = sqlc.arg(user_id)) OR
+    to_account_id IN (SELECT id FROM accounts WHERE user_id = sqlc.arg(user_id))
+ORDER BY id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
@@ -9,4 +9,39 @@ INSERT INTO users (
 
 -- name: GetUser :one
 SELECT * FROM users
-WHERE email = $1 LIMIT 1;
\ No newline at end of file
+WHERE email = $1 LIMIT 1;
+
+-- name: GetUserByID :one
+SELECT * FROM users
+WHERE id = $1 LIMIT 1;
+
+-- name: VerifyUserEmail :one
+UPDATE users
+SET email_verified_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateUserPassword :one
+UPDATE users
+SET hashed_password = $2, password_changed_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: SearchUsers :many
+SELECT * FROM users
+WHERE email ILIKE '%' || sqlc.arg(query)::text || '%' OR nickname ILIKE '%' || sqlc.arg(query)::text || '%'
+ORDER BY created_at DESC, id
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: LockUser :one
+UPDATE users
+SET locked_at = now(), locked_reason = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: UnlockUser :one
+UPDATE users
+SET locked_at = '0001-01-01 00:00:00Z', locked_reason = ''
+WHERE id = $1
+RETURNING *;
@@ -24,3 +24,55 @@ SELECT EXISTS(
   SELECT 1 FROM user_information
   WHERE user_id = $1 LIMIT 1
 );
+
+-- name: UpdateVerificationStep :one
+UPDATE user_information
+SET verification_step = $2, verification_reason = $3
+WHERE user_id = $1
+RETURNING *;
+
+-- name: GetUserInfoForUpdate :one
+SELECT * FROM user_information
+WHERE user_id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetUserInfoByKYCApplicant :one
+SELECT * FROM user_information
+WHERE kyc_applicant_id = $1 LIMIT 1;
+
+-- name: SetKYCApplicant :one
+UPDATE user_information
+SET kyc_applicant_id = $2
+WHERE user_id = $1
+RETURNING *;
+
+-- name: UpdateUserInfo :one
+UPDATE user_information
+SET
+  firstname = $2,
+  lastname = $3,
+  phone_number = $4,
+  nationality = $5,
+  address = $6,
+  postal_code = $7,
+  city = $8,
+  country = $9
+WHERE user_id = $1
+RETURNING *;
+
+-- name: CreateUserInfoChange :one
+INSERT INTO user_info_changes (
+  user_id,
+  field,
+  old_value,
+  new_value
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListUserInfoChanges :many
+SELECT * FROM user_info_changes
+WHERE user_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
@@ -0,0 +1,17 @@
+-- name: CreateVerificationTransition :one
+INSERT INTO verification_transitions (
+  user_id,
+  from_step,
+  to_step,
+  actor_id,
+  reason
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ListVerificationTransitions :many
+SELECT * FROM verification_transitions
+WHERE user_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
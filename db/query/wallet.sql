@@ -0,0 +1,49 @@
+-- name: CreateWallet :one
+INSERT INTO wallets (
+  user_id,
+  currency
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: GetWallet :one
+SELECT * FROM wallets
+WHERE id = $1 LIMIT 1;
+
+-- name: GetWalletByCurrency :one
+SELECT * FROM wallets
+WHERE user_id = $1 AND currency = $2 LIMIT 1;
+
+-- name: ListWallets :many
+SELECT * FROM wallets
+WHERE user_id = $1
+ORDER BY id;
+
+-- name: AddWalletBalance :one
+UPDATE wallets
+SET balance = balance + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateWalletEntry :one
+INSERT INTO wallet_entries (
+  wallet_id,
+  amount,
+  kind,
+  reference
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: SetWalletEntryReference :one
+UPDATE wallet_entries
+SET reference = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: ListWalletEntries :many
+SELECT * FROM wallet_entries
+WHERE wallet_id = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
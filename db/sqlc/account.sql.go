@@ -91,6 +91,29 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 	return i, err
 }
 
+const getAccountByProperty = `-- name: GetAccountByProperty :one
+SELECT id, user_id, balance, property_id, created_at FROM accounts
+WHERE user_id = $1 AND property_id = $2 LIMIT 1
+`
+
+type GetAccountByPropertyParams struct {
+	UserID     uuid.UUID `json:"user_id"`
+	PropertyID int64     `json:"property_id"`
+}
+
+func (q *Queries) GetAccountByProperty(ctx context.Context, arg GetAccountByPropertyParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getAccountByProperty, arg.UserID, arg.PropertyID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Balance,
+		&i.PropertyID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
 SELECT id, user_id, balance, property_id, created_at FROM accounts
 WHERE id = $1 LIMIT 1
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListUserActivity(t *testing.T) {
+	store := NewStore(testDB)
+	property, users := createPropertyWithHolders(t, 10)
+	user, other := users[0], createRandomUser(t)
+
+	account, err := testQueries.GetAccountByProperty(context.Background(), GetAccountByPropertyParams{
+		UserID:     user.ID,
+		PropertyID: property.ID,
+	})
+	require.NoError(t, err)
+
+	otherAccount, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		UserID:     other.ID,
+		Balance:    0,
+		PropertyID: property.ID,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID:   otherAccount.ID,
+		Amount:        3,
+	})
+	require.NoError(t, err)
+
+	arg := ListUserActivityParams{
+		UserID:          user.ID,
+		CursorTime:      time.Now().Add(time.Hour),
+		CursorID:        math.MaxInt64,
+		CursorAccountID: math.MaxInt64,
+		Limit:           1,
+	}
+
+	// the entries written by the transfer and the purchase are not listed on their own
+	page1, err := testQueries.ListUserActivity(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page1, 1)
+
+	require.Equal(t, "transfer", page1[0].Kind)
+	require.Equal(t, "out", page1[0].Direction)
+	require.Equal(t, int64(-3), page1[0].Amount)
+	require.Equal(t, otherAccount.ID, page1[0].CounterpartyAccountID.Int64)
+
+	last := page1[len(page1)-1]
+	arg.CursorTime = last.CreatedAt
+	arg.CursorKind = last.Kind
+	arg.CursorID = last.ID
+	arg.CursorAccountID = last.AccountID
+
+	arg.Limit = 5
+	page2, err := testQueries.ListUserActivity(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page2, 1)
+
+	require.Equal(t, "purchase", page2[0].Kind)
+	require.Equal(t, "in", page2[0].Direction)
+	require.Equal(t, int64(10), page2[0].Amount)
+	require.Equal(t, property.ID, page2[0].PropertyID)
+
+	// the receiving side sees the same transfer as incoming
+	arg = ListUserActivityParams{
+		UserID:          other.ID,
+		CursorTime:      time.Now().Add(time.Hour),
+		CursorID:        math.MaxInt64,
+		CursorAccountID: math.MaxInt64,
+		Limit:           5,
+	}
+	items, err := testQueries.ListUserActivity(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, "in", items[0].Direction)
+	require.Equal(t, account.ID, items[0].CounterpartyAccountID.Int64)
+}
@@ -0,0 +1,10 @@
+package db
+
+// Kinds of audit events
+const (
+	AuditRefreshTokenReused   = "refresh_token_reused"
+	AuditUserLocked           = "user_locked"
+	AuditUserUnlocked         = "user_unlocked"
+	AuditUserSignedOut        = "user_signed_out"
+	AuditIdentityDocumentRead = "identity_document_read"
+)
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditEvents(t *testing.T) {
+	user := createRandomUser(t)
+
+	for i := 0; i < 3; i++ {
+		arg := CreateAuditEventParams{
+			UserID:  user.ID,
+			Kind:    AuditRefreshTokenReused,
+			Ip:      "127.0.0.1",
+			Details: "details",
+		}
+		event, err := testQueries.CreateAuditEvent(context.Background(), arg)
+		require.NoError(t, err)
+		require.Equal(t, arg.UserID, event.UserID)
+		require.Equal(t, arg.Kind, event.Kind)
+		require.Equal(t, arg.Ip, event.Ip)
+		require.Equal(t, arg.Details, event.Details)
+		require.NotZero(t, event.CreatedAt)
+	}
+
+	events, err := testQueries.ListAuditEvents(context.Background(), ListAuditEventsParams{
+		UserID: user.ID,
+		Limit:  5,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	require.Greater(t, events[0].ID, events[1].ID)
+	require.False(t, events[0].ActorID.Valid)
+}
+
+func TestAuditEventActor(t *testing.T) {
+	user := createRandomUser(t)
+	actor := createRandomUser(t)
+
+	arg := CreateAuditEventParams{
+		UserID:  user.ID,
+		Kind:    AuditUserLocked,
+		Details: "fraud suspicion",
+		ActorID: uuid.NullUUID{UUID: actor.ID, Valid: true},
+	}
+	event, err := testQueries.CreateAuditEvent(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.ActorID, event.ActorID)
+}
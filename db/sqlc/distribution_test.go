@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createPropertyWithHolders(t *testing.T, blocks ...int64) (Property, []User) {
+	store := NewStore(testDB)
+
+	property, err := testQueries.CreateProperty(context.Background(), CreatePropertyParams{
+		Name:                util.RandomString(6),
+		Description:         util.RandomString(32),
+		InitialBlockCount:   100,
+		RemainingBlockCount: 100,
+		Currency:            "EUR",
+	})
+	require.NoError(t, err)
+
+	users := make([]User, len(blocks))
+	for i, amount := range blocks {
+		users[i] = createRandomUser(t)
+		_, err := store.PurchaseTx(context.Background(), PurchaseTxParams{
+			UserID:     users[i].ID,
+			PropertyID: property.ID,
+			Amount:     amount,
+		})
+		require.NoError(t, err)
+	}
+
+	return property, users
+}
+
+func TestDistributionTx(t *testing.T) {
+	store := NewStore(testDB)
+	property, users := createPropertyWithHolders(t, 30, 10, 1)
+
+	periodStart := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := time.Date(2021, time.March, 31, 0, 0, 0, 0, time.UTC)
+	result, err := store.DistributionTx(context.Background(), DistributionTxParams{
+		PropertyID:  property.ID,
+		Amount:      1050,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		SnapshotAt:  time.Now(),
+	})
+	require.NoError(t, err)
+
+	// 30% and 10% of the income, 1% rounded down, the unsold 59% stays undistributed
+	require.Equal(t, property.ID, result.Distribution.PropertyID)
+	require.Equal(t, property.Currency, result.Distribution.Currency)
+	require.Equal(t, int64(1050), result.Distribution.Amount)
+	require.Equal(t, int64(315+105+10), result.Distribution.DistributedAmount)
+	require.Len(t, result.Payouts, 3)
+
+	expected := map[int64]int64{30: 315, 10: 105, 1: 10}
+	for _, payout := range result.Payouts {
+		require.Equal(t, result.Distribution.ID, payout.DistributionID)
+		require.Equal(t, expected[payout.Blocks], payout.Amount)
+	}
+
+	wallet, err := store.GetWalletByCurrency(context.Background(), GetWalletByCurrencyParams{
+		UserID:   users[0].ID,
+		Currency: property.Currency,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(315), wallet.Balance)
+
+	payouts, err := store.ListPayouts(context.Background(), ListPayoutsParams{
+		UserID: users[0].ID,
+		Limit:  5,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, payouts, 1)
+	require.Equal(t, property.ID, payouts[0].PropertyID)
+	require.Equal(t, int64(30), payouts[0].Blocks)
+	require.Equal(t, int64(315), payouts[0].Amount)
+	require.Equal(t, periodStart, payouts[0].PeriodStart.UTC())
+	require.Equal(t, periodEnd, payouts[0].PeriodEnd.UTC())
+
+	// a second distribution for the same period is rejected
+	_, err = store.DistributionTx(context.Background(), DistributionTxParams{
+		PropertyID:  property.ID,
+		Amount:      1050,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		SnapshotAt:  time.Now(),
+	})
+	require.Error(t, err)
+}
+
+func TestDistributionTxSnapshot(t *testing.T) {
+	store := NewStore(testDB)
+	property, _ := createPropertyWithHolders(t, 20)
+
+	// blocks bought after the snapshot time do not earn income
+	result, err := store.DistributionTx(context.Background(), DistributionTxParams{
+		PropertyID:  property.ID,
+		Amount:      1000,
+		PeriodStart: time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2021, time.January, 31, 0, 0, 0, 0, time.UTC),
+		SnapshotAt:  time.Now().Add(-time.Hour),
+	})
+	require.NoError(t, err)
+	require.Empty(t, result.Payouts)
+	require.Zero(t, result.Distribution.DistributedAmount)
+}
+
+func TestListDistributions(t *testing.T) {
+	store := NewStore(testDB)
+	property, _ := createPropertyWithHolders(t, 50)
+
+	for i := 0; i < 3; i++ {
+		month := time.Month(i + 1)
+		_, err := store.DistributionTx(context.Background(), DistributionTxParams{
+			PropertyID:  property.ID,
+			Amount:      util.RandomInt(100, 1000),
+			PeriodStart: time.Date(2021, month, 1, 0, 0, 0, 0, time.UTC),
+			PeriodEnd:   time.Date(2021, month, 28, 0, 0, 0, 0, time.UTC),
+			SnapshotAt:  time.Now(),
+		})
+		require.NoError(t, err)
+	}
+
+	distributions, err := testQueries.ListDistributions(context.Background(), ListDistributionsParams{
+		PropertyID: property.ID,
+		Limit:      5,
+		Offset:     0,
+	})
+	require.NoError(t, err)
+	require.Len(t, distributions, 3)
+	require.True(t, distributions[0].PeriodStart.After(distributions[1].PeriodStart))
+}
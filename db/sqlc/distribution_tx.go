@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// DistributionTxParams contains the input parameters of the distribution transaction
+type DistributionTxParams struct {
+	PropertyID  int64     `json:"property_id"`
+	Amount      int64     `json:"amount"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	SnapshotAt  time.Time `json:"snapshot_at"`
+}
+
+// DistributionTxResult is the result of the distribution transaction
+type DistributionTxResult struct {
+	Distribution Distribution `json:"distribution"`
+	Payouts      []Payout     `json:"payouts"`
+}
+
+// DistributionTx distributes an income amount among the holders of a property.
+// Each holder receives amount * blocks / initial_block_count, rounded down, where blocks is
+// the account balance at the snapshot time rebuilt from its entries. The share of the unsold
+// blocks and the rounding remainder are not distributed.
+func (store *SQLStore) DistributionTx(ctx context.Context, arg DistributionTxParams) (DistributionTxResult, error) {
+	var result DistributionTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		property, err := q.GetPropertyForUpdate(ctx, arg.PropertyID)
+		if err != nil {
+			return err
+		}
+
+		result.Distribution, err = q.CreateDistribution(ctx, CreateDistributionParams{
+			PropertyID:  arg.PropertyID,
+			Amount:      arg.Amount,
+			Currency:    property.Currency,
+			PeriodStart: arg.PeriodStart,
+			PeriodEnd:   arg.PeriodEnd,
+			SnapshotAt:  arg.SnapshotAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		holdings, err := q.ListHoldingsAt(ctx, ListHoldingsAtParams{
+			PropertyID: arg.PropertyID,
+			SnapshotAt: arg.SnapshotAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		type credit struct {
+			holding ListHoldingsAtRow
+			wallet  Wallet
+			amount  int64
+		}
+
+		credits := make([]credit, 0, len(holdings))
+		for _, holding := range holdings {
+			amount := proRataShare(arg.Amount, holding.Blocks, property.InitialBlockCount)
+			if amount == 0 {
+				continue
+			}
+
+			wallet, err := getOrCreateWallet(ctx, q, holding.UserID, property.Currency)
+			if err != nil {
+				return err
+			}
+			credits = append(credits, credit{holding: holding, wallet: wallet, amount: amount})
+		}
+
+		// wallets are credited in ascending ID order, like transferCash, to avoid deadlocks
+		sort.Slice(credits, func(i, j int) bool {
+			return credits[i].wallet.ID < credits[j].wallet.ID
+		})
+
+		reference := fmt.Sprintf("distribution:%d", result.Distribution.ID)
+		result.Payouts = []Payout{}
+		var distributed int64
+		for _, c := range credits {
+			_, entry, err := addWalletEntry(ctx, q, c.wallet, c.amount, WalletEntryDistribution, reference)
+			if err != nil {
+				return err
+			}
+
+			payout, err := q.CreatePayout(ctx, CreatePayoutParams{
+				DistributionID: result.Distribution.ID,
+				AccountID:      c.holding.AccountID,
+				UserID:         c.holding.UserID,
+				WalletEntryID:  entry.ID,
+				Blocks:         c.holding.Blocks,
+				Amount:         c.amount,
+			})
+			if err != nil {
+				return err
+			}
+
+			result.Payouts = append(result.Payouts, payout)
+			distributed += c.amount
+		}
+
+		result.Distribution, err = q.SetDistributedAmount(ctx, SetDistributedAmountParams{
+			ID:                result.Distribution.ID,
+			DistributedAmount: distributed,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// proRataShare returns amount * blocks / total rounded down, without overflowing int64 on the product.
+func proRataShare(amount int64, blocks int64, total int64) int64 {
+	if total <= 0 {
+		return 0
+	}
+
+	share := new(big.Int).Mul(big.NewInt(amount), big.NewInt(blocks))
+	share.Quo(share, big.NewInt(total))
+	return share.Int64()
+}
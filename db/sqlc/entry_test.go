@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
 
@@ -67,3 +68,42 @@ func TestListEntries(t *testing.T) {
 		require.Equal(t, arg.AccountID, entry.AccountID)
 	}
 }
+
+func TestListAccountEntries(t *testing.T) {
+	account := createRandomAccount(t)
+	for i := 0; i < 10; i++ {
+		createRandomEntry(t, account)
+	}
+
+	arg := ListAccountEntriesParams{
+		AccountID: account.ID,
+		Cursor:    math.MaxInt64,
+		StartTime: time.Now().Add(-time.Hour),
+		EndTime:   time.Now().Add(time.Hour),
+		Limit:     5,
+	}
+
+	page1, err := testQueries.ListAccountEntries(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page1, 5)
+
+	arg.Cursor = page1[len(page1)-1].ID
+	page2, err := testQueries.ListAccountEntries(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page2, 5)
+
+	// pages are ordered newest first and do not overlap
+	entries := append(page1, page2...)
+	for i, entry := range entries {
+		require.Equal(t, account.ID, entry.AccountID)
+		if i > 0 {
+			require.Less(t, entry.ID, entries[i-1].ID)
+		}
+	}
+
+	arg.Cursor = math.MaxInt64
+	arg.EndTime = arg.StartTime.Add(time.Minute)
+	entries, err = testQueries.ListAccountEntries(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomIdentityDocumentParams(userInfo UserInformation, kind string) CreateIdentityDocumentParams {
+	return CreateIdentityDocumentParams{
+		UserID:      userInfo.UserID,
+		Kind:        kind,
+		Filename:    util.RandomString(8) + ".pdf",
+		ContentType: "application/pdf",
+		Size:        util.RandomInt(1, 1000),
+		StorageKey:  fmt.Sprintf("users/%s/documents/%s", userInfo.UserID, util.RandomString(16)),
+	}
+}
+
+func TestCreateIdentityDocumentTx(t *testing.T) {
+	store := NewStore(testDB)
+	userInfo := createRandomUserInfo(t)
+
+	arg := randomIdentityDocumentParams(userInfo, IdentityDocumentIDCard)
+	result, err := store.CreateIdentityDocumentTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, result.Document.ID)
+	require.Equal(t, arg.UserID, result.Document.UserID)
+	require.Equal(t, arg.Kind, result.Document.Kind)
+	require.Equal(t, arg.Filename, result.Document.Filename)
+	require.Equal(t, arg.ContentType, result.Document.ContentType)
+	require.Equal(t, arg.Size, result.Document.Size)
+	require.Equal(t, arg.StorageKey, result.Document.StorageKey)
+	require.Equal(t, VerificationDocumentsUploaded, result.UserInfo.VerificationStep)
+	require.Equal(t, VerificationInfoSubmitted, result.Transition.FromStep)
+	require.False(t, result.Transition.ActorID.Valid)
+
+	// another document can be added until the review starts
+	arg2 := randomIdentityDocumentParams(userInfo, IdentityDocumentProofOfAddress)
+	_, err = store.CreateIdentityDocumentTx(context.Background(), arg2)
+	require.NoError(t, err)
+
+	_, err = store.TransitionVerificationTx(context.Background(), TransitionVerificationTxParams{
+		UserID: userInfo.UserID,
+		ToStep: VerificationUnderReview,
+	})
+	require.NoError(t, err)
+
+	arg3 := randomIdentityDocumentParams(userInfo, IdentityDocumentPassport)
+	_, err = store.CreateIdentityDocumentTx(context.Background(), arg3)
+	require.True(t, errors.Is(err, ErrInvalidVerificationTransition))
+
+	documents, err := store.ListIdentityDocuments(context.Background(), userInfo.UserID)
+	require.NoError(t, err)
+	require.Len(t, documents, 2)
+	require.Equal(t, arg2.StorageKey, documents[0].StorageKey)
+	require.Equal(t, arg.StorageKey, documents[1].StorageKey)
+}
+
+func TestGetIdentityDocument(t *testing.T) {
+	store := NewStore(testDB)
+	userInfo := createRandomUserInfo(t)
+	other := createRandomUserInfo(t)
+
+	result, err := store.CreateIdentityDocumentTx(context.Background(), randomIdentityDocumentParams(userInfo, IdentityDocumentPassport))
+	require.NoError(t, err)
+
+	document, err := store.GetIdentityDocument(context.Background(), GetIdentityDocumentParams{
+		ID:     result.Document.ID,
+		UserID: userInfo.UserID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, result.Document, document)
+
+	// documents are only found with their owner
+	_, err = store.GetIdentityDocument(context.Background(), GetIdentityDocumentParams{
+		ID:     result.Document.ID,
+		UserID: other.UserID,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
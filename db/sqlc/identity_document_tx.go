@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of identity documents
+const (
+	IdentityDocumentIDCard         = "id_card"
+	IdentityDocumentPassport       = "passport"
+	IdentityDocumentProofOfAddress = "proof_of_address"
+)
+
+// CreateIdentityDocumentTxResult is the result of the identity document creation transaction
+type CreateIdentityDocumentTxResult struct {
+	Document   IdentityDocument       `json:"document"`
+	UserInfo   UserInformation        `json:"user_info"`
+	Transition VerificationTransition `json:"transition"`
+}
+
+// CreateIdentityDocumentTx records a document uploaded by the user and moves their verification
+// to documents uploaded. It fails with ErrInvalidVerificationTransition while the documents are
+// under review or once they have been approved.
+func (store *SQLStore) CreateIdentityDocumentTx(ctx context.Context, arg CreateIdentityDocumentParams) (CreateIdentityDocumentTxResult, error) {
+	var result CreateIdentityDocumentTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		transition, err := transitionVerification(ctx, q, TransitionVerificationTxParams{
+			UserID:  arg.UserID,
+			ToStep:  VerificationDocumentsUploaded,
+			ActorID: uuid.NullUUID{},
+			Reason:  arg.Kind + " uploaded",
+		})
+		if err != nil {
+			return err
+		}
+		result.UserInfo = transition.UserInfo
+		result.Transition = transition.Transition
+
+		result.Document, err = q.CreateIdentityDocument(ctx, arg)
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+// createUserInfoUnderReview creates a user whose documents have been submitted to the KYC provider
+func createUserInfoUnderReview(t *testing.T, store Store) UserInformation {
+	userInfo := createRandomUserInfo(t)
+
+	_, err := store.TransitionVerificationTx(context.Background(), TransitionVerificationTxParams{
+		UserID: userInfo.UserID,
+		ToStep: VerificationDocumentsUploaded,
+	})
+	require.NoError(t, err)
+
+	applicantID := "app_" + util.RandomString(12)
+	result, err := store.SubmitKYCApplicantTx(context.Background(), SubmitKYCApplicantTxParams{
+		UserID:      userInfo.UserID,
+		ApplicantID: applicantID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, VerificationUnderReview, result.UserInfo.VerificationStep)
+	require.Equal(t, applicantID, result.UserInfo.KycApplicantID)
+	require.Equal(t, VerificationDocumentsUploaded, result.Transition.FromStep)
+
+	userInfo2, err := store.GetUserInfoByKYCApplicant(context.Background(), applicantID)
+	require.NoError(t, err)
+	require.Equal(t, userInfo.UserID, userInfo2.UserID)
+
+	return result.UserInfo
+}
+
+func TestSubmitKYCApplicantTx(t *testing.T) {
+	store := NewStore(testDB)
+	createUserInfoUnderReview(t, store)
+
+	// the documents must be uploaded first
+	userInfo := createRandomUserInfo(t)
+	_, err := store.SubmitKYCApplicantTx(context.Background(), SubmitKYCApplicantTxParams{
+		UserID:      userInfo.UserID,
+		ApplicantID: "app_" + util.RandomString(12),
+	})
+	require.True(t, errors.Is(err, ErrInvalidVerificationTransition))
+}
+
+func TestRecordKYCCheckTx(t *testing.T) {
+	store := NewStore(testDB)
+	userInfo := createUserInfoUnderReview(t, store)
+
+	// a screening hit leaves the verification under review
+	arg := RecordKYCCheckTxParams{
+		CreateKYCCheckParams: CreateKYCCheckParams{
+			UserID:      userInfo.UserID,
+			EventID:     "evt_" + util.RandomString(12),
+			ApplicantID: userInfo.KycApplicantID,
+			Decision:    "review",
+			PepHit:      true,
+			Matches:     "John Doe, mayor",
+		},
+	}
+	result, err := store.RecordKYCCheckTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, result.Check.ID)
+	require.True(t, result.Check.PepHit)
+	require.Equal(t, VerificationUnderReview, result.UserInfo.VerificationStep)
+	require.Zero(t, result.Transition.ID)
+
+	// the same webhook delivered twice is recorded once
+	_, err = store.RecordKYCCheckTx(context.Background(), arg)
+	require.True(t, errors.Is(err, ErrKYCCheckRecorded))
+
+	arg2 := RecordKYCCheckTxParams{
+		CreateKYCCheckParams: CreateKYCCheckParams{
+			UserID:      userInfo.UserID,
+			EventID:     "evt_" + util.RandomString(12),
+			ApplicantID: userInfo.KycApplicantID,
+			Decision:    "approved",
+		},
+		ToStep: VerificationApproved,
+	}
+	result, err = store.RecordKYCCheckTx(context.Background(), arg2)
+	require.NoError(t, err)
+	require.Equal(t, VerificationApproved, result.UserInfo.VerificationStep)
+	require.Equal(t, VerificationUnderReview, result.Transition.FromStep)
+	require.False(t, result.Transition.ActorID.Valid)
+
+	// a late rejection is recorded without moving an approved verification
+	arg3 := RecordKYCCheckTxParams{
+		CreateKYCCheckParams: CreateKYCCheckParams{
+			UserID:      userInfo.UserID,
+			EventID:     "evt_" + util.RandomString(12),
+			ApplicantID: userInfo.KycApplicantID,
+			Decision:    "rejected",
+			Reason:      "document expired",
+		},
+		ToStep: VerificationRejected,
+	}
+	result, err = store.RecordKYCCheckTx(context.Background(), arg3)
+	require.NoError(t, err)
+	require.Equal(t, VerificationApproved, result.UserInfo.VerificationStep)
+	require.Zero(t, result.Transition.ID)
+
+	checks, err := store.ListKYCChecks(context.Background(), ListKYCChecksParams{
+		UserID: userInfo.UserID,
+		Limit:  10,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, checks, 3)
+	require.Equal(t, arg3.EventID, checks[0].EventID)
+}
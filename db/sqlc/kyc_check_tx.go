@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrKYCCheckRecorded is returned when the webhook of a KYC check has already been recorded
+var ErrKYCCheckRecorded = errors.New("KYC check already recorded")
+
+// SubmitKYCApplicantTxParams contains the input parameters of the KYC applicant submission transaction
+type SubmitKYCApplicantTxParams struct {
+	UserID      uuid.UUID `json:"user_id"`
+	ApplicantID string    `json:"applicant_id"`
+}
+
+// SubmitKYCApplicantTx records the ID of the user at the KYC provider and moves their
+// verification under review until the provider decides.
+func (store *SQLStore) SubmitKYCApplicantTx(ctx context.Context, arg SubmitKYCApplicantTxParams) (TransitionVerificationTxResult, error) {
+	var result TransitionVerificationTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = transitionVerification(ctx, q, TransitionVerificationTxParams{
+			UserID:  arg.UserID,
+			ToStep:  VerificationUnderReview,
+			ActorID: uuid.NullUUID{},
+			Reason:  "submitted to the KYC provider",
+		})
+		if err != nil {
+			return err
+		}
+
+		result.UserInfo, err = q.SetKYCApplicant(ctx, SetKYCApplicantParams{
+			UserID:         arg.UserID,
+			KycApplicantID: arg.ApplicantID,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// RecordKYCCheckTxParams contains the input parameters of the KYC check transaction
+type RecordKYCCheckTxParams struct {
+	CreateKYCCheckParams
+	// ToStep is the verification step the check leads to, none when zero
+	ToStep int16 `json:"to_step"`
+}
+
+// RecordKYCCheckTxResult is the result of the KYC check transaction
+type RecordKYCCheckTxResult struct {
+	Check      KycCheck               `json:"check"`
+	UserInfo   UserInformation        `json:"user_info"`
+	Transition VerificationTransition `json:"transition"`
+}
+
+// RecordKYCCheckTx records a decision of the KYC provider and moves the verification of the user
+// accordingly. A decision that does not apply to the current step, such as a late approval of a
+// verification rejected by the compliance team, is recorded without moving the verification.
+// It fails with ErrKYCCheckRecorded when the webhook has already been recorded.
+func (store *SQLStore) RecordKYCCheckTx(ctx context.Context, arg RecordKYCCheckTxParams) (RecordKYCCheckTxResult, error) {
+	var result RecordKYCCheckTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.Check, err = q.CreateKYCCheck(ctx, arg.CreateKYCCheckParams)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrKYCCheckRecorded
+			}
+			return err
+		}
+
+		result.UserInfo, err = q.GetUserInfoForUpdate(ctx, arg.UserID)
+		if err != nil {
+			return err
+		}
+		if arg.ToStep == 0 || !CanTransitionVerification(result.UserInfo.VerificationStep, arg.ToStep) {
+			return nil
+		}
+
+		transition, err := transitionVerification(ctx, q, TransitionVerificationTxParams{
+			UserID:  arg.UserID,
+			ToStep:  arg.ToStep,
+			ActorID: uuid.NullUUID{},
+			Reason:  arg.Reason,
+		})
+		if err != nil {
+			return err
+		}
+		result.UserInfo = transition.UserInfo
+		result.Transition = transition.Transition
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomMFASecret(t *testing.T, user User) MfaSecret {
+	secret, err := util.RandomTOTPSecret()
+	require.NoError(t, err)
+
+	arg := CreateMFASecretParams{
+		UserID: user.ID,
+		Secret: secret,
+	}
+	mfaSecret, err := testQueries.CreateMFASecret(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.UserID, mfaSecret.UserID)
+	require.Equal(t, arg.Secret, mfaSecret.Secret)
+	require.True(t, mfaSecret.ConfirmedAt.IsZero())
+	require.Zero(t, mfaSecret.LastUsedStep)
+
+	return mfaSecret
+}
+
+func TestCreateMFASecret(t *testing.T) {
+	user := createRandomUser(t)
+	secret1 := createRandomMFASecret(t, user)
+
+	// a pending enrollment can be restarted
+	secret2 := createRandomMFASecret(t, user)
+	require.NotEqual(t, secret1.Secret, secret2.Secret)
+
+	_, err := testQueries.ConfirmMFASecret(context.Background(), user.ID)
+	require.NoError(t, err)
+
+	// a confirmed secret is kept
+	_, err = testQueries.CreateMFASecret(context.Background(), CreateMFASecretParams{
+		UserID: user.ID,
+		Secret: secret1.Secret,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	secret3, err := testQueries.GetMFASecret(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, secret2.Secret, secret3.Secret)
+	require.WithinDuration(t, time.Now(), secret3.ConfirmedAt, time.Second)
+}
+
+func TestUseMFAStep(t *testing.T) {
+	user := createRandomUser(t)
+	createRandomMFASecret(t, user)
+
+	step := util.TOTPStep(time.Now())
+	secret, err := testQueries.UseMFAStep(context.Background(), UseMFAStepParams{UserID: user.ID, Step: step})
+	require.NoError(t, err)
+	require.Equal(t, step, secret.LastUsedStep)
+
+	_, err = testQueries.UseMFAStep(context.Background(), UseMFAStepParams{UserID: user.ID, Step: step})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = testQueries.UseMFAStep(context.Background(), UseMFAStepParams{UserID: user.ID, Step: step - 1})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestConfirmMFATx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	createRandomMFASecret(t, user)
+
+	codes := []string{"code1", "code2", "code3"}
+	hashedCodes := make([]string, len(codes))
+	for i, code := range codes {
+		hashedCodes[i] = util.HashRecoveryCode(code)
+	}
+
+	result, err := store.ConfirmMFATx(context.Background(), ConfirmMFATxParams{
+		UserID:              user.ID,
+		HashedRecoveryCodes: hashedCodes,
+	})
+	require.NoError(t, err)
+	require.False(t, result.Secret.ConfirmedAt.IsZero())
+	require.Len(t, result.RecoveryCodes, len(codes))
+
+	// recovery codes are single use
+	arg := UseRecoveryCodeParams{UserID: user.ID, HashedCode: hashedCodes[0]}
+	code, err := testQueries.UseRecoveryCode(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, code.UsedAt.IsZero())
+
+	_, err = testQueries.UseRecoveryCode(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// confirming again replaces the recovery codes
+	_, err = store.ConfirmMFATx(context.Background(), ConfirmMFATxParams{
+		UserID:              user.ID,
+		HashedRecoveryCodes: []string{util.HashRecoveryCode("code4")},
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.UseRecoveryCode(context.Background(), UseRecoveryCodeParams{UserID: user.ID, HashedCode: hashedCodes[1]})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
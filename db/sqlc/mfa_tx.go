@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ConfirmMFATxParams contains the input parameters of the MFA confirmation transaction
+type ConfirmMFATxParams struct {
+	UserID              uuid.UUID `json:"user_id"`
+	HashedRecoveryCodes []string  `json:"hashed_recovery_codes"`
+}
+
+// ConfirmMFATxResult is the result of the MFA confirmation transaction
+type ConfirmMFATxResult struct {
+	Secret        MfaSecret      `json:"secret"`
+	RecoveryCodes []RecoveryCode `json:"recovery_codes"`
+}
+
+// ConfirmMFATx enables two-factor authentication for the user and replaces the recovery codes.
+func (store *SQLStore) ConfirmMFATx(ctx context.Context, arg ConfirmMFATxParams) (ConfirmMFATxResult, error) {
+	var result ConfirmMFATxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Secret, err = q.ConfirmMFASecret(ctx, arg.UserID)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteRecoveryCodes(ctx, arg.UserID)
+		if err != nil {
+			return err
+		}
+
+		for _, hashedCode := range arg.HashedRecoveryCodes {
+			code, err := q.CreateRecoveryCode(ctx, CreateRecoveryCodeParams{
+				UserID:     arg.UserID,
+				HashedCode: hashedCode,
+			})
+			if err != nil {
+				return err
+			}
+			result.RecoveryCodes = append(result.RecoveryCodes, code)
+		}
+		return nil
+	})
+
+	return result, err
+}
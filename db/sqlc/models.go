@@ -17,6 +17,32 @@ type Account struct {
 	CreatedAt  time.Time `json:"created_at"`
 }
 
+type AuditEvent struct {
+	ID        int64     `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Kind      string    `json:"kind"`
+	Ip        string    `json:"ip"`
+	Details   string    `json:"details"`
+	CreatedAt time.Time `json:"created_at"`
+	// staff member who acted on the user, null when the user acted
+	ActorID uuid.NullUUID `json:"actor_id"`
+}
+
+type Distribution struct {
+	ID         int64 `json:"id"`
+	PropertyID int64 `json:"property_id"`
+	// total income to distribute in minor units, must be positive
+	Amount int64 `json:"amount"`
+	// sum of the payouts, the rest belongs to unsold blocks or rounding
+	DistributedAmount int64     `json:"distributed_amount"`
+	Currency          string    `json:"currency"`
+	PeriodStart       time.Time `json:"period_start"`
+	PeriodEnd         time.Time `json:"period_end"`
+	// holdings are taken at this time
+	SnapshotAt time.Time `json:"snapshot_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
 type Entry struct {
 	ID        int64 `json:"id"`
 	AccountID int64 `json:"account_id"`
@@ -25,6 +51,77 @@ type Entry struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type IdentityDocument struct {
+	ID          int64     `json:"id"`
+	UserID      uuid.UUID `json:"user_id"`
+	Kind        string    `json:"kind"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	// key of the encrypted content in the document storage
+	StorageKey string    `json:"storage_key"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type KycCheck struct {
+	ID     int64     `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	// ID of the provider webhook, a webhook delivered twice is recorded once
+	EventID      string `json:"event_id"`
+	ApplicantID  string `json:"applicant_id"`
+	Decision     string `json:"decision"`
+	Reason       string `json:"reason"`
+	SanctionsHit bool   `json:"sanctions_hit"`
+	PepHit       bool   `json:"pep_hit"`
+	// sanctions and PEP list entries matched, one per line
+	Matches   string    `json:"matches"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type MfaSecret struct {
+	UserID uuid.UUID `json:"user_id"`
+	Secret string    `json:"secret"`
+	// zero time until the first code is confirmed
+	ConfirmedAt time.Time `json:"confirmed_at"`
+	// last accepted TOTP time step, codes cannot be replayed
+	LastUsedStep int64     `json:"last_used_step"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type Order struct {
+	ID         int64     `json:"id"`
+	UserID     uuid.UUID `json:"user_id"`
+	AccountID  int64     `json:"account_id"`
+	PropertyID int64     `json:"property_id"`
+	// either buy or sell
+	Side string `json:"side"`
+	// price per block, must be positive
+	Price           int64 `json:"price"`
+	Amount          int64 `json:"amount"`
+	RemainingAmount int64 `json:"remaining_amount"`
+	// one of open, filled or cancelled
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Payout struct {
+	ID             int64     `json:"id"`
+	DistributionID int64     `json:"distribution_id"`
+	AccountID      int64     `json:"account_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	WalletEntryID  int64     `json:"wallet_entry_id"`
+	// blocks held at the distribution snapshot time
+	Blocks    int64     `json:"blocks"`
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Permission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
 type Property struct {
 	ID          int64  `json:"id"`
 	Name        string `json:"name"`
@@ -35,6 +132,50 @@ type Property struct {
 	RemainingBlockCount int64     `json:"remaining_block_count"`
 	CreatedAt           time.Time `json:"created_at"`
 	UpdatedAt           time.Time `json:"updated_at"`
+	Archived            bool      `json:"archived"`
+	// primary market price in minor units
+	PricePerBlock int64  `json:"price_per_block"`
+	Currency      string `json:"currency"`
+}
+
+type Purchase struct {
+	ID         int64 `json:"id"`
+	AccountID  int64 `json:"account_id"`
+	PropertyID int64 `json:"property_id"`
+	// must be positive
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RecoveryCode struct {
+	ID         int64     `json:"id"`
+	UserID     uuid.UUID `json:"user_id"`
+	HashedCode string    `json:"hashed_code"`
+	// zero time until the code is used
+	UsedAt    time.Time `json:"used_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Role struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type RolePermission struct {
+	Role       string `json:"role"`
+	Permission string `json:"permission"`
+}
+
+type Trade struct {
+	ID          int64 `json:"id"`
+	PropertyID  int64 `json:"property_id"`
+	BuyOrderID  int64 `json:"buy_order_id"`
+	SellOrderID int64 `json:"sell_order_id"`
+	TransferID  int64 `json:"transfer_id"`
+	// execution price per block, the resting order price
+	Price     int64     `json:"price"`
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type Transfer struct {
@@ -54,7 +195,21 @@ type User struct {
 	PhoneNumber       sql.NullString `json:"phone_number"`
 	PasswordChangedAt time.Time      `json:"password_changed_at"`
 	CreatedAt         time.Time      `json:"created_at"`
-	IsAdmin           bool           `json:"is_admin"`
+	// zero time until the email address is verified
+	EmailVerifiedAt time.Time `json:"email_verified_at"`
+	// zero time unless the user has been locked by the support team
+	LockedAt     time.Time `json:"locked_at"`
+	LockedReason string    `json:"locked_reason"`
+}
+
+type UserInfoChange struct {
+	ID     int64     `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	// name of the changed column of user_information
+	Field     string    `json:"field"`
+	OldValue  string    `json:"old_value"`
+	NewValue  string    `json:"new_value"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type UserInformation struct {
@@ -62,11 +217,55 @@ type UserInformation struct {
 	Firstname string    `json:"firstname"`
 	Lastname  string    `json:"lastname"`
 	// has to be E164 compliant
-	PhoneNumber      string `json:"phone_number"`
-	Nationality      string `json:"nationality"`
-	Address          string `json:"address"`
-	PostalCode       string `json:"postal_code"`
-	City             string `json:"city"`
-	Country          string `json:"country"`
-	VerificationStep int16  `json:"verification_step"`
+	PhoneNumber string `json:"phone_number"`
+	Nationality string `json:"nationality"`
+	Address     string `json:"address"`
+	PostalCode  string `json:"postal_code"`
+	City        string `json:"city"`
+	Country     string `json:"country"`
+	// 1 info submitted, 2 documents uploaded, 3 under review, 4 approved, 5 rejected, 6 expired
+	VerificationStep int16 `json:"verification_step"`
+	// why the verification was last rejected, empty otherwise
+	VerificationReason string `json:"verification_reason"`
+	// ID of the user at the KYC provider, empty until submitted
+	KycApplicantID string `json:"kyc_applicant_id"`
+}
+
+type UserRole struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type VerificationTransition struct {
+	ID       int64     `json:"id"`
+	UserID   uuid.UUID `json:"user_id"`
+	FromStep int16     `json:"from_step"`
+	ToStep   int16     `json:"to_step"`
+	// staff member who made the transition, null when the user or the verification provider did
+	ActorID   uuid.NullUUID `json:"actor_id"`
+	Reason    string        `json:"reason"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type Wallet struct {
+	ID     int64     `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	// ISO 4217 currency code
+	Currency string `json:"currency"`
+	// in minor units, must be greater than or equal to zero
+	Balance   int64     `json:"balance"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WalletEntry struct {
+	ID       int64 `json:"id"`
+	WalletID int64 `json:"wallet_id"`
+	// in minor units, can be negative or positive
+	Amount int64 `json:"amount"`
+	// deposit, withdrawal, purchase, trade...
+	Kind string `json:"kind"`
+	// payment provider or related record reference
+	Reference string    `json:"reference"`
+	CreatedAt time.Time `json:"created_at"`
 }
@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomOrder(t *testing.T, account Account, side string) Order {
+	arg := CreateOrderParams{
+		UserID:     account.UserID,
+		AccountID:  account.ID,
+		PropertyID: account.PropertyID,
+		Side:       side,
+		Price:      util.RandomInt(1, 1000),
+		Amount:     util.RandomInt(1, 10),
+	}
+
+	order, err := testQueries.CreateOrder(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, order)
+
+	require.Equal(t, arg.UserID, order.UserID)
+	require.Equal(t, arg.AccountID, order.AccountID)
+	require.Equal(t, arg.PropertyID, order.PropertyID)
+	require.Equal(t, arg.Side, order.Side)
+	require.Equal(t, arg.Price, order.Price)
+	require.Equal(t, arg.Amount, order.Amount)
+	require.Equal(t, arg.Amount, order.RemainingAmount)
+	require.Equal(t, OrderStatusOpen, order.Status)
+
+	require.NotZero(t, order.ID)
+	require.NotZero(t, order.CreatedAt)
+
+	return order
+}
+
+func TestCreateOrder(t *testing.T) {
+	account := createRandomAccount(t)
+	createRandomOrder(t, account, OrderSideBuy)
+}
+
+func TestGetOrder(t *testing.T) {
+	account := createRandomAccount(t)
+	order1 := createRandomOrder(t, account, OrderSideSell)
+	order2, err := testQueries.GetOrder(context.Background(), order1.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, order2)
+
+	require.Equal(t, order1.ID, order2.ID)
+	require.Equal(t, order1.Side, order2.Side)
+	require.Equal(t, order1.Price, order2.Price)
+	require.Equal(t, order1.RemainingAmount, order2.RemainingAmount)
+	require.WithinDuration(t, order1.CreatedAt, order2.CreatedAt, time.Second)
+}
+
+func TestFillOrder(t *testing.T) {
+	account := createRandomAccount(t)
+	order1 := createRandomOrder(t, account, OrderSideBuy)
+
+	order2, err := testQueries.FillOrder(context.Background(), FillOrderParams{
+		ID:     order1.ID,
+		Amount: order1.RemainingAmount,
+	})
+	require.NoError(t, err)
+	require.Zero(t, order2.RemainingAmount)
+	require.Equal(t, OrderStatusFilled, order2.Status)
+}
+
+func TestCancelOrder(t *testing.T) {
+	account := createRandomAccount(t)
+	order1 := createRandomOrder(t, account, OrderSideBuy)
+
+	order2, err := testQueries.CancelOrder(context.Background(), order1.ID)
+	require.NoError(t, err)
+	require.Equal(t, OrderStatusCancelled, order2.Status)
+
+	_, err = testQueries.CancelOrder(context.Background(), order1.ID)
+	require.Error(t, err)
+}
+
+func TestListOrderBookDepth(t *testing.T) {
+	account := createRandomAccount(t)
+	for i := 0; i < 5; i++ {
+		createRandomOrder(t, account, OrderSideBuy)
+	}
+
+	levels, err := testQueries.ListOrderBookDepth(context.Background(), account.PropertyID)
+	require.NoError(t, err)
+	require.NotEmpty(t, levels)
+
+	var total int64
+	for _, level := range levels {
+		require.Equal(t, OrderSideBuy, level.Side)
+		require.Positive(t, level.OrderCount)
+		total += level.Amount
+	}
+	require.Positive(t, total)
+}
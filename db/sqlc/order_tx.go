@@ -0,0 +1,249 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Order sides and statuses stored in the orders table
+const (
+	OrderSideBuy  = "buy"
+	OrderSideSell = "sell"
+
+	OrderStatusOpen      = "open"
+	OrderStatusFilled    = "filled"
+	OrderStatusCancelled = "cancelled"
+)
+
+// PlaceOrderTxParams contains the input parameters of the place order transaction
+type PlaceOrderTxParams struct {
+	UserID     uuid.UUID `json:"user_id"`
+	PropertyID int64     `json:"property_id"`
+	Side       string    `json:"side"`
+	Price      int64     `json:"price"`
+	Amount     int64     `json:"amount"`
+}
+
+// PlaceOrderTxResult is the result of the place order transaction
+type PlaceOrderTxResult struct {
+	Order  Order   `json:"order"`
+	Trades []Trade `json:"trades"`
+}
+
+// PlaceOrderTx records a limit order and matches it against the resting orders of the opposite side.
+// The property row is locked for the whole transaction so matching is serialized per property.
+// Each match is settled at the resting order price: blocks move through the same transfer as
+// TransferTx and the price moves from the buyer's wallet to the seller's wallet.
+func (store *SQLStore) PlaceOrderTx(ctx context.Context, arg PlaceOrderTxParams) (PlaceOrderTxResult, error) {
+	result := PlaceOrderTxResult{Trades: []Trade{}}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		property, err := q.GetPropertyForUpdate(ctx, arg.PropertyID)
+		if err != nil {
+			return err
+		}
+		if property.Archived {
+			return ErrPropertyNotForSale
+		}
+
+		// the total of the order bounds the cost of its trades
+		cost, err := blocksCost(arg.Price, arg.Amount)
+		if err != nil {
+			return err
+		}
+
+		account, err := getOrCreateAccount(ctx, q, arg.UserID, arg.PropertyID)
+		if err != nil {
+			return err
+		}
+
+		switch arg.Side {
+		case OrderSideSell:
+			reserved, err := q.GetOpenSellAmount(ctx, account.ID)
+			if err != nil {
+				return err
+			}
+			if account.Balance-reserved < arg.Amount {
+				return ErrInsufficientBalance
+			}
+		case OrderSideBuy:
+			wallet, err := getOrCreateWallet(ctx, q, arg.UserID, property.Currency)
+			if err != nil {
+				return err
+			}
+			reserved, err := q.GetOpenBuyCost(ctx, GetOpenBuyCostParams{
+				UserID:   arg.UserID,
+				Currency: property.Currency,
+			})
+			if err != nil {
+				return err
+			}
+			if wallet.Balance-reserved < cost {
+				return ErrInsufficientFunds
+			}
+		}
+
+		result.Order, err = q.CreateOrder(ctx, CreateOrderParams{
+			UserID:     arg.UserID,
+			AccountID:  account.ID,
+			PropertyID: arg.PropertyID,
+			Side:       arg.Side,
+			Price:      arg.Price,
+			Amount:     arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		for result.Order.RemainingAmount > 0 {
+			maker, err := bestOpposingOrder(ctx, q, result.Order)
+			if err == sql.ErrNoRows {
+				break
+			}
+			if err != nil {
+				return err
+			}
+
+			settle, err := canSettle(ctx, q, maker, result.Order.RemainingAmount, property.Currency)
+			if err != nil {
+				return err
+			}
+			if !settle {
+				// the resting order is no longer backed by blocks or cash, drop it.
+				if _, err := q.CancelOrder(ctx, maker.ID); err != nil {
+					return err
+				}
+				continue
+			}
+
+			trade, err := matchOrders(ctx, q, &result.Order, maker, property.Currency)
+			if err != nil {
+				return err
+			}
+			result.Trades = append(result.Trades, trade)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+func bestOpposingOrder(ctx context.Context, q *Queries, taker Order) (Order, error) {
+	if taker.Side == OrderSideBuy {
+		return q.GetBestSellOrderForUpdate(ctx, GetBestSellOrderForUpdateParams{
+			PropertyID: taker.PropertyID,
+			Price:      taker.Price,
+			UserID:     taker.UserID,
+		})
+	}
+	return q.GetBestBuyOrderForUpdate(ctx, GetBestBuyOrderForUpdateParams{
+		PropertyID: taker.PropertyID,
+		Price:      taker.Price,
+		UserID:     taker.UserID,
+	})
+}
+
+// canSettle checks that a resting order can still pay for or deliver its side of a trade
+// against a taker order with the given remaining amount.
+func canSettle(ctx context.Context, q *Queries, maker Order, takerAmount int64, currency string) (bool, error) {
+	amount := takerAmount
+	if maker.RemainingAmount < amount {
+		amount = maker.RemainingAmount
+	}
+
+	if maker.Side == OrderSideSell {
+		account, err := q.GetAccount(ctx, maker.AccountID)
+		if err != nil {
+			return false, err
+		}
+		return account.Balance >= amount, nil
+	}
+
+	wallet, err := q.GetWalletByCurrency(ctx, GetWalletByCurrencyParams{
+		UserID:   maker.UserID,
+		Currency: currency,
+	})
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return wallet.Balance >= maker.Price*amount, nil
+}
+
+// matchOrders executes a trade between the incoming taker order and a resting maker order.
+// The taker is updated in place with its new remaining amount and status.
+func matchOrders(ctx context.Context, q *Queries, taker *Order, maker Order, currency string) (Trade, error) {
+	amount := taker.RemainingAmount
+	if maker.RemainingAmount < amount {
+		amount = maker.RemainingAmount
+	}
+
+	buyOrder, sellOrder := *taker, maker
+	if taker.Side == OrderSideSell {
+		buyOrder, sellOrder = maker, *taker
+	}
+
+	seller, err := q.GetAccount(ctx, sellOrder.AccountID)
+	if err != nil {
+		return Trade{}, err
+	}
+	if seller.Balance < amount {
+		return Trade{}, ErrInsufficientBalance
+	}
+
+	transfer, err := transferBlocks(ctx, q, TransferTxParams{
+		FromAccountID: sellOrder.AccountID,
+		ToAccountID:   buyOrder.AccountID,
+		Amount:        amount,
+	})
+	if err != nil {
+		return Trade{}, err
+	}
+
+	_, err = q.FillOrder(ctx, FillOrderParams{
+		ID:     maker.ID,
+		Amount: amount,
+	})
+	if err != nil {
+		return Trade{}, err
+	}
+
+	*taker, err = q.FillOrder(ctx, FillOrderParams{
+		ID:     taker.ID,
+		Amount: amount,
+	})
+	if err != nil {
+		return Trade{}, err
+	}
+
+	trade, err := q.CreateTrade(ctx, CreateTradeParams{
+		PropertyID:  taker.PropertyID,
+		BuyOrderID:  buyOrder.ID,
+		SellOrderID: sellOrder.ID,
+		TransferID:  transfer.Transfer.ID,
+		Price:       maker.Price,
+		Amount:      amount,
+	})
+	if err != nil {
+		return trade, err
+	}
+
+	buyerWallet, err := getOrCreateWallet(ctx, q, buyOrder.UserID, currency)
+	if err != nil {
+		return trade, err
+	}
+	sellerWallet, err := getOrCreateWallet(ctx, q, sellOrder.UserID, currency)
+	if err != nil {
+		return trade, err
+	}
+
+	reference := fmt.Sprintf("trade:%d", trade.ID)
+	err = transferCash(ctx, q, buyerWallet, sellerWallet, trade.Price*trade.Amount, WalletEntryTrade, reference)
+	return trade, err
+}
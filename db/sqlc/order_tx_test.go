@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaceOrderTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	seller := createRandomAccount(t)
+	buyer := createRandomUser(t)
+	price := int64(100)
+	amount := seller.Balance / 2
+	if amount == 0 {
+		t.Skip("seller has no blocks to sell")
+	}
+
+	sell, err := store.PlaceOrderTx(context.Background(), PlaceOrderTxParams{
+		UserID:     seller.UserID,
+		PropertyID: seller.PropertyID,
+		Side:       OrderSideSell,
+		Price:      price,
+		Amount:     amount,
+	})
+	require.NoError(t, err)
+	require.Empty(t, sell.Trades)
+	require.Equal(t, OrderStatusOpen, sell.Order.Status)
+	require.Equal(t, amount, sell.Order.RemainingAmount)
+
+	_, err = store.WalletTx(context.Background(), WalletTxParams{
+		UserID:   buyer.ID,
+		Currency: "EUR",
+		Amount:   (price + 10) * (2*amount + 1),
+		Kind:     WalletEntryDeposit,
+	})
+	require.NoError(t, err)
+
+	// a buy order below the ask price rests on the book
+	low, err := store.PlaceOrderTx(context.Background(), PlaceOrderTxParams{
+		UserID:     buyer.ID,
+		PropertyID: seller.PropertyID,
+		Side:       OrderSideBuy,
+		Price:      price - 1,
+		Amount:     amount,
+	})
+	require.NoError(t, err)
+	require.Empty(t, low.Trades)
+
+	// a crossing buy order for more than available is partially filled at the ask price
+	buy, err := store.PlaceOrderTx(context.Background(), PlaceOrderTxParams{
+		UserID:     buyer.ID,
+		PropertyID: seller.PropertyID,
+		Side:       OrderSideBuy,
+		Price:      price + 10,
+		Amount:     amount + 1,
+	})
+	require.NoError(t, err)
+	require.Len(t, buy.Trades, 1)
+	require.Equal(t, price, buy.Trades[0].Price)
+	require.Equal(t, amount, buy.Trades[0].Amount)
+	require.Equal(t, OrderStatusOpen, buy.Order.Status)
+	require.Equal(t, int64(1), buy.Order.RemainingAmount)
+
+	filled, err := store.GetOrder(context.Background(), sell.Order.ID)
+	require.NoError(t, err)
+	require.Equal(t, OrderStatusFilled, filled.Status)
+	require.Zero(t, filled.RemainingAmount)
+
+	updatedSeller, err := store.GetAccount(context.Background(), seller.ID)
+	require.NoError(t, err)
+	require.Equal(t, seller.Balance-amount, updatedSeller.Balance)
+
+	buyerAccount, err := store.GetAccountByProperty(context.Background(), GetAccountByPropertyParams{
+		UserID:     buyer.ID,
+		PropertyID: seller.PropertyID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, amount, buyerAccount.Balance)
+
+	sellerWallet, err := store.GetWalletByCurrency(context.Background(), GetWalletByCurrencyParams{
+		UserID:   seller.UserID,
+		Currency: "EUR",
+	})
+	require.NoError(t, err)
+	require.Equal(t, price*amount, sellerWallet.Balance)
+}
+
+func TestPlaceOrderTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+	buyer := createRandomUser(t)
+
+	_, err := store.PlaceOrderTx(context.Background(), PlaceOrderTxParams{
+		UserID:     buyer.ID,
+		PropertyID: account.PropertyID,
+		Side:       OrderSideBuy,
+		Price:      100,
+		Amount:     1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestPlaceOrderTxCostTooLarge(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+	buyer := createRandomUser(t)
+
+	// the total would wrap around to a negative cost and pass the funds check
+	_, err := store.PlaceOrderTx(context.Background(), PlaceOrderTxParams{
+		UserID:     buyer.ID,
+		PropertyID: account.PropertyID,
+		Side:       OrderSideBuy,
+		Price:      3,
+		Amount:     math.MaxInt64 / 2,
+	})
+	require.ErrorIs(t, err, ErrCostTooLarge)
+}
+
+func TestPlaceOrderTxInsufficientBalance(t *testing.T) {
+	store := NewStore(testDB)
+
+	seller := createRandomAccount(t)
+
+	_, err := store.PlaceOrderTx(context.Background(), PlaceOrderTxParams{
+		UserID:     seller.UserID,
+		PropertyID: seller.PropertyID,
+		Side:       OrderSideSell,
+		Price:      100,
+		Amount:     seller.Balance + 1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientBalance)
+}
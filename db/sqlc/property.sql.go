@@ -7,15 +7,76 @@ import (
 	"context"
 )
 
+const addPropertyRemainingBlockCount = `-- name: AddPropertyRemainingBlockCount :one
+UPDATE properties
+SET
+  remaining_block_count = remaining_block_count + $1,
+  updated_at = now()
+WHERE id = $2
+RETURNING id, name, description, initial_block_count, remaining_block_count, created_at, updated_at, archived, price_per_block, currency
+`
+
+type AddPropertyRemainingBlockCountParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddPropertyRemainingBlockCount(ctx context.Context, arg AddPropertyRemainingBlockCountParams) (Property, error) {
+	row := q.db.QueryRowContext(ctx, addPropertyRemainingBlockCount, arg.Amount, arg.ID)
+	var i Property
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.InitialBlockCount,
+		&i.RemainingBlockCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Archived,
+		&i.PricePerBlock,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const archiveProperty = `-- name: ArchiveProperty :one
+UPDATE properties
+SET
+  archived = TRUE,
+  updated_at = now()
+WHERE id = $1
+RETURNING id, name, description, initial_block_count, remaining_block_count, created_at, updated_at, archived, price_per_block, currency
+`
+
+func (q *Queries) ArchiveProperty(ctx context.Context, id int64) (Property, error) {
+	row := q.db.QueryRowContext(ctx, archiveProperty, id)
+	var i Property
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.InitialBlockCount,
+		&i.RemainingBlockCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Archived,
+		&i.PricePerBlock,
+		&i.Currency,
+	)
+	return i, err
+}
+
 const createProperty = `-- name: CreateProperty :one
 INSERT INTO properties (
   "name",
   "description",
   initial_block_count,
-  remaining_block_count
+  remaining_block_count,
+  price_per_block,
+  currency
 ) VALUES (
-  $1, $2, $3, $4
-) RETURNING id, name, description, initial_block_count, remaining_block_count, created_at, updated_at
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, name, description, initial_block_count, remaining_block_count, created_at, updated_at, archived, price_per_block, currency
 `
 
 type CreatePropertyParams struct {
@@ -23,6 +84,8 @@ type CreatePropertyParams struct {
 	Description         string `json:"description"`
 	InitialBlockCount   int64  `json:"initial_block_count"`
 	RemainingBlockCount int64  `json:"remaining_block_count"`
+	PricePerBlock       int64  `json:"price_per_block"`
+	Currency            string `json:"currency"`
 }
 
 func (q *Queries) CreateProperty(ctx context.Context, arg CreatePropertyParams) (Property, error) {
@@ -31,6 +94,8 @@ func (q *Queries) CreateProperty(ctx context.Context, arg CreatePropertyParams)
 		arg.Description,
 		arg.InitialBlockCount,
 		arg.RemainingBlockCount,
+		arg.PricePerBlock,
+		arg.Currency,
 	)
 	var i Property
 	err := row.Scan(
@@ -41,12 +106,15 @@ func (q *Queries) CreateProperty(ctx context.Context, arg CreatePropertyParams)
 		&i.RemainingBlockCount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Archived,
+		&i.PricePerBlock,
+		&i.Currency,
 	)
 	return i, err
 }
 
 const getProperty = `-- name: GetProperty :one
-SELECT id, name, description, initial_block_count, remaining_block_count, created_at, updated_at FROM properties
+SELECT id, name, description, initial_block_count, remaining_block_count, created_at, updated_at, archived, price_per_block, currency FROM properties
 WHERE id = $1 LIMIT 1
 `
 
@@ -61,6 +129,131 @@ func (q *Queries) GetProperty(ctx context.Context, id int64) (Property, error) {
 		&i.RemainingBlockCount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Archived,
+		&i.PricePerBlock,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const getPropertyForUpdate = `-- name: GetPropertyForUpdate :one
+SELECT id, name, description, initial_block_count, remaining_block_count, created_at, updated_at, archived, price_per_block, currency FROM properties
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetPropertyForUpdate(ctx context.Context, id int64) (Property, error) {
+	row := q.db.QueryRowContext(ctx, getPropertyForUpdate, id)
+	var i Property
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.InitialBlockCount,
+		&i.RemainingBlockCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Archived,
+		&i.PricePerBlock,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const listProperties = `-- name: ListProperties :many
+SELECT id, name, description, initial_block_count, remaining_block_count, created_at, updated_at, archived, price_per_block, currency FROM properties
+WHERE
+    archived = FALSE AND
+    "name" ILIKE $1 AND
+    remaining_block_count >= $2
+ORDER BY id
+LIMIT $3
+OFFSET $4
+`
+
+type ListPropertiesParams struct {
+	NamePattern            string `json:"name_pattern"`
+	MinRemainingBlockCount int64  `json:"min_remaining_block_count"`
+	Limit                  int32  `json:"limit"`
+	Offset                 int32  `json:"offset"`
+}
+
+func (q *Queries) ListProperties(ctx context.Context, arg ListPropertiesParams) ([]Property, error) {
+	rows, err := q.db.QueryContext(ctx, listProperties,
+		arg.NamePattern,
+		arg.MinRemainingBlockCount,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Property{}
+	for rows.Next() {
+		var i Property
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.InitialBlockCount,
+			&i.RemainingBlockCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Archived,
+			&i.PricePerBlock,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateProperty = `-- name: UpdateProperty :one
+UPDATE properties
+SET
+  "name" = $2,
+  "description" = $3,
+  price_per_block = $4,
+  updated_at = now()
+WHERE id = $1
+RETURNING id, name, description, initial_block_count, remaining_block_count, created_at, updated_at, archived, price_per_block, currency
+`
+
+type UpdatePropertyParams struct {
+	ID            int64  `json:"id"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	PricePerBlock int64  `json:"price_per_block"`
+}
+
+func (q *Queries) UpdateProperty(ctx context.Context, arg UpdatePropertyParams) (Property, error) {
+	row := q.db.QueryRowContext(ctx, updateProperty,
+		arg.ID,
+		arg.Name,
+		arg.Description,
+		arg.PricePerBlock,
+	)
+	var i Property
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.InitialBlockCount,
+		&i.RemainingBlockCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Archived,
+		&i.PricePerBlock,
+		&i.Currency,
 	)
 	return i, err
 }
@@ -16,6 +16,8 @@ func createRandomProperty(t *testing.T) Property {
 		Description:         util.RandomString(32),
 		InitialBlockCount:   initialBlockCount,
 		RemainingBlockCount: initialBlockCount,
+		PricePerBlock:       util.RandomInt(100, 10000),
+		Currency:            "EUR",
 	}
 
 	property, err := testQueries.CreateProperty(context.Background(), arg)
@@ -26,6 +28,8 @@ func createRandomProperty(t *testing.T) Property {
 	require.Equal(t, arg.Description, property.Description)
 	require.Equal(t, arg.InitialBlockCount, property.InitialBlockCount)
 	require.Equal(t, arg.RemainingBlockCount, property.RemainingBlockCount)
+	require.Equal(t, arg.PricePerBlock, property.PricePerBlock)
+	require.Equal(t, arg.Currency, property.Currency)
 
 	require.NotZero(t, property.CreatedAt)
 	require.NotZero(t, property.UpdatedAt)
@@ -50,3 +54,71 @@ func TestGetProperty(t *testing.T) {
 	require.WithinDuration(t, property1.UpdatedAt, property2.UpdatedAt, time.Second)
 	require.WithinDuration(t, property1.CreatedAt, property2.CreatedAt, time.Second)
 }
+
+func TestUpdateProperty(t *testing.T) {
+	property1 := createRandomProperty(t)
+
+	arg := UpdatePropertyParams{
+		ID:            property1.ID,
+		Name:          util.RandomString(6),
+		Description:   util.RandomString(32),
+		PricePerBlock: util.RandomInt(100, 10000),
+	}
+
+	property2, err := testQueries.UpdateProperty(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, property2)
+
+	require.Equal(t, property1.ID, property2.ID)
+	require.Equal(t, arg.Name, property2.Name)
+	require.Equal(t, arg.Description, property2.Description)
+	require.Equal(t, arg.PricePerBlock, property2.PricePerBlock)
+	require.Equal(t, property1.InitialBlockCount, property2.InitialBlockCount)
+	require.Equal(t, property1.RemainingBlockCount, property2.RemainingBlockCount)
+	require.WithinDuration(t, property1.CreatedAt, property2.CreatedAt, time.Second)
+	require.False(t, property2.UpdatedAt.Before(property1.UpdatedAt))
+}
+
+func TestArchiveProperty(t *testing.T) {
+	property1 := createRandomProperty(t)
+	require.False(t, property1.Archived)
+
+	property2, err := testQueries.ArchiveProperty(context.Background(), property1.ID)
+	require.NoError(t, err)
+	require.True(t, property2.Archived)
+
+	properties, err := testQueries.ListProperties(context.Background(), ListPropertiesParams{
+		NamePattern: property1.Name,
+		Limit:       5,
+		Offset:      0,
+	})
+	require.NoError(t, err)
+	for _, property := range properties {
+		require.NotEqual(t, property1.ID, property.ID)
+	}
+}
+
+func TestListProperties(t *testing.T) {
+	var lastProperty Property
+	for i := 0; i < 10; i++ {
+		lastProperty = createRandomProperty(t)
+	}
+
+	arg := ListPropertiesParams{
+		NamePattern:            lastProperty.Name,
+		MinRemainingBlockCount: 1,
+		Limit:                  5,
+		Offset:                 0,
+	}
+
+	properties, err := testQueries.ListProperties(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, properties)
+
+	for _, property := range properties {
+		require.NotEmpty(t, property)
+		require.Equal(t, lastProperty.Name, property.Name)
+		require.False(t, property.Archived)
+		require.GreaterOrEqual(t, property.RemainingBlockCount, arg.MinRemainingBlockCount)
+	}
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPurchase(t *testing.T, account Account) Purchase {
+	arg := CreatePurchaseParams{
+		AccountID:  account.ID,
+		PropertyID: account.PropertyID,
+		Amount:     util.RandomInt(1, 10),
+	}
+
+	purchase, err := testQueries.CreatePurchase(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, purchase)
+
+	require.Equal(t, arg.AccountID, purchase.AccountID)
+	require.Equal(t, arg.PropertyID, purchase.PropertyID)
+	require.Equal(t, arg.Amount, purchase.Amount)
+
+	require.NotZero(t, purchase.ID)
+	require.NotZero(t, purchase.CreatedAt)
+
+	return purchase
+}
+
+func TestCreatePurchase(t *testing.T) {
+	account := createRandomAccount(t)
+	createRandomPurchase(t, account)
+}
+
+func TestGetPurchase(t *testing.T) {
+	account := createRandomAccount(t)
+	purchase1 := createRandomPurchase(t, account)
+	purchase2, err := testQueries.GetPurchase(context.Background(), purchase1.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, purchase2)
+
+	require.Equal(t, purchase1.ID, purchase2.ID)
+	require.Equal(t, purchase1.AccountID, purchase2.AccountID)
+	require.Equal(t, purchase1.PropertyID, purchase2.PropertyID)
+	require.Equal(t, purchase1.Amount, purchase2.Amount)
+	require.WithinDuration(t, purchase1.CreatedAt, purchase2.CreatedAt, time.Second)
+}
+
+func TestListPurchases(t *testing.T) {
+	account := createRandomAccount(t)
+	for i := 0; i < 10; i++ {
+		createRandomPurchase(t, account)
+	}
+
+	arg := ListPurchasesParams{
+		AccountID: account.ID,
+		Limit:     5,
+		Offset:    5,
+	}
+
+	purchases, err := testQueries.ListPurchases(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, purchases, 5)
+
+	for _, purchase := range purchases {
+		require.NotEmpty(t, purchase)
+		require.Equal(t, account.ID, purchase.AccountID)
+	}
+}
@@ -10,25 +10,101 @@ import (
 
 type Querier interface {
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	AddPropertyRemainingBlockCount(ctx context.Context, arg AddPropertyRemainingBlockCountParams) (Property, error)
+	AddUserRole(ctx context.Context, arg AddUserRoleParams) error
+	AddWalletBalance(ctx context.Context, arg AddWalletBalanceParams) (Wallet, error)
+	ArchiveProperty(ctx context.Context, id int64) (Property, error)
+	CancelOrder(ctx context.Context, id int64) (Order, error)
+	ConfirmMFASecret(ctx context.Context, userID uuid.UUID) (MfaSecret, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvent, error)
+	CreateDistribution(ctx context.Context, arg CreateDistributionParams) (Distribution, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateIdentityDocument(ctx context.Context, arg CreateIdentityDocumentParams) (IdentityDocument, error)
+	CreateKYCCheck(ctx context.Context, arg CreateKYCCheckParams) (KycCheck, error)
+	CreateMFASecret(ctx context.Context, arg CreateMFASecretParams) (MfaSecret, error)
+	CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error)
+	CreatePayout(ctx context.Context, arg CreatePayoutParams) (Payout, error)
 	CreateProperty(ctx context.Context, arg CreatePropertyParams) (Property, error)
+	CreatePurchase(ctx context.Context, arg CreatePurchaseParams) (Purchase, error)
+	CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) (RecoveryCode, error)
+	CreateTrade(ctx context.Context, arg CreateTradeParams) (Trade, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateUserInfo(ctx context.Context, arg CreateUserInfoParams) (UserInformation, error)
+	CreateUserInfoChange(ctx context.Context, arg CreateUserInfoChangeParams) (UserInfoChange, error)
+	CreateVerificationTransition(ctx context.Context, arg CreateVerificationTransitionParams) (VerificationTransition, error)
+	CreateWallet(ctx context.Context, arg CreateWalletParams) (Wallet, error)
+	CreateWalletEntry(ctx context.Context, arg CreateWalletEntryParams) (WalletEntry, error)
 	DeleteAccount(ctx context.Context, id int64) error
+	DeleteRecoveryCodes(ctx context.Context, userID uuid.UUID) error
 	ExistsUserInfo(ctx context.Context, userID uuid.UUID) (bool, error)
+	FillOrder(ctx context.Context, arg FillOrderParams) (Order, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
+	GetAccountByProperty(ctx context.Context, arg GetAccountByPropertyParams) (Account, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetBestBuyOrderForUpdate(ctx context.Context, arg GetBestBuyOrderForUpdateParams) (Order, error)
+	GetBestSellOrderForUpdate(ctx context.Context, arg GetBestSellOrderForUpdateParams) (Order, error)
+	GetDistribution(ctx context.Context, id int64) (Distribution, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetIdentityDocument(ctx context.Context, arg GetIdentityDocumentParams) (IdentityDocument, error)
+	GetMFASecret(ctx context.Context, userID uuid.UUID) (MfaSecret, error)
+	GetOpenBuyCost(ctx context.Context, arg GetOpenBuyCostParams) (int64, error)
+	GetOpenSellAmount(ctx context.Context, accountID int64) (int64, error)
+	GetOrder(ctx context.Context, id int64) (Order, error)
 	GetProperty(ctx context.Context, id int64) (Property, error)
+	GetPropertyForUpdate(ctx context.Context, id int64) (Property, error)
+	GetPurchase(ctx context.Context, id int64) (Purchase, error)
+	GetRole(ctx context.Context, name string) (Role, error)
+	GetTrade(ctx context.Context, id int64) (Trade, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
 	GetUser(ctx context.Context, email string) (User, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
 	GetUserInfo(ctx context.Context, userID uuid.UUID) (UserInformation, error)
+	GetUserInfoByKYCApplicant(ctx context.Context, kycApplicantID string) (UserInformation, error)
+	GetUserInfoForUpdate(ctx context.Context, userID uuid.UUID) (UserInformation, error)
+	GetWallet(ctx context.Context, id int64) (Wallet, error)
+	GetWalletByCurrency(ctx context.Context, arg GetWalletByCurrencyParams) (Wallet, error)
+	ListAccountEntries(ctx context.Context, arg ListAccountEntriesParams) ([]Entry, error)
+	ListAccountTransfers(ctx context.Context, arg ListAccountTransfersParams) ([]Transfer, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error)
+	ListDistributions(ctx context.Context, arg ListDistributionsParams) ([]Distribution, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	ListHoldingsAt(ctx context.Context, arg ListHoldingsAtParams) ([]ListHoldingsAtRow, error)
+	ListIdentityDocuments(ctx context.Context, userID uuid.UUID) ([]IdentityDocument, error)
+	ListKYCChecks(ctx context.Context, arg ListKYCChecksParams) ([]KycCheck, error)
+	ListOrderBookDepth(ctx context.Context, propertyID int64) ([]ListOrderBookDepthRow, error)
+	ListOrders(ctx context.Context, arg ListOrdersParams) ([]Order, error)
+	ListPayouts(ctx context.Context, arg ListPayoutsParams) ([]ListPayoutsRow, error)
+	ListProperties(ctx context.Context, arg ListPropertiesParams) ([]Property, error)
+	ListPurchases(ctx context.Context, arg ListPurchasesParams) ([]Purchase, error)
+	ListRoles(ctx context.Context) ([]Role, error)
+	ListTrades(ctx context.Context, arg ListTradesParams) ([]Trade, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
+	ListUserActivity(ctx context.Context, arg ListUserActivityParams) ([]ListUserActivityRow, error)
+	ListUserInfoChanges(ctx context.Context, arg ListUserInfoChangesParams) ([]UserInfoChange, error)
+	ListUserPermissions(ctx context.Context, userID uuid.UUID) ([]string, error)
+	ListUserRoles(ctx context.Context, userID uuid.UUID) ([]string, error)
+	ListUserTransfers(ctx context.Context, arg ListUserTransfersParams) ([]Transfer, error)
+	ListVerificationTransitions(ctx context.Context, arg ListVerificationTransitionsParams) ([]VerificationTransition, error)
+	ListWalletEntries(ctx context.Context, arg ListWalletEntriesParams) ([]WalletEntry, error)
+	ListWallets(ctx context.Context, userID uuid.UUID) ([]Wallet, error)
+	LockUser(ctx context.Context, arg LockUserParams) (User, error)
+	RemoveUserRole(ctx context.Context, arg RemoveUserRoleParams) (int64, error)
+	SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error)
+	SetDistributedAmount(ctx context.Context, arg SetDistributedAmountParams) (Distribution, error)
+	SetKYCApplicant(ctx context.Context, arg SetKYCApplicantParams) (UserInformation, error)
+	SetWalletEntryReference(ctx context.Context, arg SetWalletEntryReferenceParams) (WalletEntry, error)
+	UnlockUser(ctx context.Context, id uuid.UUID) (User, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	UpdateProperty(ctx context.Context, arg UpdatePropertyParams) (Property, error)
+	UpdateUserInfo(ctx context.Context, arg UpdateUserInfoParams) (UserInformation, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error)
+	UpdateVerificationStep(ctx context.Context, arg UpdateVerificationStepParams) (UserInformation, error)
+	UseMFAStep(ctx context.Context, arg UseMFAStepParams) (MfaSecret, error)
+	UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (RecoveryCode, error)
+	VerifyUserEmail(ctx context.Context, id uuid.UUID) (User, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,23 @@
+package db
+
+// Roles granted to the users
+const (
+	RoleInvestor          = "investor"
+	RoleSupport           = "support"
+	RoleComplianceOfficer = "compliance_officer"
+	RolePropertyManager   = "property_manager"
+	RoleAdmin             = "admin"
+)
+
+// Permissions granted by the roles
+const (
+	PermissionPropertiesWrite    = "properties:write"
+	PermissionDistributionsWrite = "distributions:write"
+	PermissionUsersRead          = "users:read"
+	PermissionUsersWrite         = "users:write"
+	PermissionKYCReview          = "kyc:review"
+	PermissionKYCDocumentsRead   = "kyc:documents:read"
+	PermissionAuditRead          = "audit:read"
+	PermissionRolesWrite         = "roles:write"
+	PermissionAdminAccess        = "admin:access"
+)
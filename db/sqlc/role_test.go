@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListRoles(t *testing.T) {
+	roles, err := testQueries.ListRoles(context.Background())
+	require.NoError(t, err)
+
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.Name
+		require.NotEmpty(t, role.Description)
+	}
+	require.Subset(t, names, []string{RoleInvestor, RoleSupport, RoleComplianceOfficer, RolePropertyManager, RoleAdmin})
+}
+
+func TestUserRoles(t *testing.T) {
+	user := createRandomUser(t)
+
+	roles, err := testQueries.ListUserRoles(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Empty(t, roles)
+
+	permissions, err := testQueries.ListUserPermissions(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Empty(t, permissions)
+
+	for _, role := range []string{RoleSupport, RoleComplianceOfficer, RoleSupport} {
+		err = testQueries.AddUserRole(context.Background(), AddUserRoleParams{UserID: user.ID, Role: role})
+		require.NoError(t, err)
+	}
+
+	roles, err = testQueries.ListUserRoles(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{RoleComplianceOfficer, RoleSupport}, roles)
+
+	// the permissions shared by the roles are only listed once
+	permissions, err = testQueries.ListUserPermissions(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		PermissionAdminAccess,
+		PermissionAuditRead,
+		PermissionKYCDocumentsRead,
+		PermissionKYCReview,
+		PermissionUsersRead,
+		PermissionUsersWrite,
+	}, permissions)
+
+	n, err := testQueries.RemoveUserRole(context.Background(), RemoveUserRoleParams{UserID: user.ID, Role: RoleSupport})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+
+	n, err = testQueries.RemoveUserRole(context.Background(), RemoveUserRoleParams{UserID: user.ID, Role: RoleSupport})
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	permissions, err = testQueries.ListUserPermissions(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.NotContains(t, permissions, PermissionUsersWrite)
+}
+
+func TestAddUserRoleUnknownRole(t *testing.T) {
+	user := createRandomUser(t)
+
+	err := testQueries.AddUserRole(context.Background(), AddUserRoleParams{UserID: user.ID, Role: util.RandomString(8)})
+	require.Error(t, err)
+}
+
+func TestCreateUserTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	hashedPassword, err := util.HashPassword(util.RandomString(6))
+	require.NoError(t, err)
+
+	result, err := store.CreateUserTx(context.Background(), CreateUserTxParams{
+		CreateUserParams: CreateUserParams{
+			HashedPassword: hashedPassword,
+			Nickname:       util.RandomString(6),
+			Email:          util.RandomEmail(),
+		},
+		Roles: []string{RoleInvestor},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, result.User)
+	require.Equal(t, []string{RoleInvestor}, result.Roles)
+
+	// an unknown role rolls the user back
+	email := util.RandomEmail()
+	_, err = store.CreateUserTx(context.Background(), CreateUserTxParams{
+		CreateUserParams: CreateUserParams{
+			HashedPassword: hashedPassword,
+			Nickname:       util.RandomString(6),
+			Email:          email,
+		},
+		Roles: []string{util.RandomString(8)},
+	})
+	require.Error(t, err)
+
+	_, err = testQueries.GetUser(context.Background(), email)
+	require.Error(t, err)
+
+	// so does a failure of the callback, which sees the user before the commit
+	errRegistration := errors.New("registration failed")
+	email = util.RandomEmail()
+	_, err = store.CreateUserTx(context.Background(), CreateUserTxParams{
+		CreateUserParams: CreateUserParams{
+			HashedPassword: hashedPassword,
+			Nickname:       util.RandomString(6),
+			Email:          email,
+		},
+		Roles: []string{RoleInvestor},
+		AfterCreate: func(user User) error {
+			require.Equal(t, email, user.Email)
+			return errRegistration
+		},
+	})
+	require.ErrorIs(t, err, errRegistration)
+
+	_, err = testQueries.GetUser(context.Background(), email)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -3,13 +3,45 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// Different types of error returned by the store transactions
+var (
+	ErrPropertyNotForSale  = errors.New("property is not for sale")
+	ErrInsufficientBlocks  = errors.New("not enough blocks remaining")
+	ErrInsufficientBalance = errors.New("account balance is insufficient")
+	ErrInsufficientFunds   = errors.New("wallet balance is insufficient")
+	ErrCostTooLarge        = errors.New("cost is too large")
 )
 
+// blocksCost returns the cost of amount blocks at price, or ErrCostTooLarge when it overflows
+func blocksCost(price int64, amount int64) (int64, error) {
+	if price > 0 && amount > math.MaxInt64/price {
+		return 0, ErrCostTooLarge
+	}
+	return price * amount, nil
+}
+
 // Store provides all functions to execute db queries and transactions
 type Store interface {
 	Querier
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	PurchaseTx(ctx context.Context, arg PurchaseTxParams) (PurchaseTxResult, error)
+	PlaceOrderTx(ctx context.Context, arg PlaceOrderTxParams) (PlaceOrderTxResult, error)
+	WalletTx(ctx context.Context, arg WalletTxParams) (WalletTxResult, error)
+	DistributionTx(ctx context.Context, arg DistributionTxParams) (DistributionTxResult, error)
+	ConfirmMFATx(ctx context.Context, arg ConfirmMFATxParams) (ConfirmMFATxResult, error)
+	CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error)
+	TransitionVerificationTx(ctx context.Context, arg TransitionVerificationTxParams) (TransitionVerificationTxResult, error)
+	CreateIdentityDocumentTx(ctx context.Context, arg CreateIdentityDocumentParams) (CreateIdentityDocumentTxResult, error)
+	SubmitKYCApplicantTx(ctx context.Context, arg SubmitKYCApplicantTxParams) (TransitionVerificationTxResult, error)
+	RecordKYCCheckTx(ctx context.Context, arg RecordKYCCheckTxParams) (RecordKYCCheckTxResult, error)
+	UpdateUserInfoTx(ctx context.Context, arg UpdateUserInfoTxParams) (UpdateUserInfoTxResult, error)
 }
 
 // SQLStore provides all functions to execute SQL queries and transactions
@@ -68,46 +100,49 @@ func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (Tr
 
 	err := store.execTx(ctx, func(q *Queries) error {
 		var err error
+		result, err = transferBlocks(ctx, q, arg)
+		return err
+	})
 
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: arg.FromAccountID,
-			ToAccountID:   arg.ToAccountID,
-			Amount:        arg.Amount,
-		})
-		if err != nil {
-			return err
-		}
+	return result, err
+}
 
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount,
-		})
-		if err != nil {
-			return err
-		}
+// transferBlocks creates the transfer record and account entries, then updates both balances.
+// Balances are always updated in ascending account ID order to avoid deadlocks.
+func transferBlocks(ctx context.Context, q *Queries, arg TransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+	var err error
 
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
-		})
-		if err != nil {
-			return err
-		}
+	result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+	})
+	if err != nil {
+		return result, err
+	}
 
-		if arg.FromAccountID < arg.ToAccountID {
-			result.FromAccount, result.ToAccount, err = addBlockAmount(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
-			if err != nil {
-				return err
-			}
-		} else {
-			result.ToAccount, result.FromAccount, err = addBlockAmount(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
-			if err != nil {
-				return err
-			}
-		}
-		return nil
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: arg.FromAccountID,
+		Amount:    -arg.Amount,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: arg.ToAccountID,
+		Amount:    arg.Amount,
 	})
+	if err != nil {
+		return result, err
+	}
 
+	if arg.FromAccountID < arg.ToAccountID {
+		result.FromAccount, result.ToAccount, err = addBlockAmount(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
+	} else {
+		result.ToAccount, result.FromAccount, err = addBlockAmount(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
+	}
 	return result, err
 }
 
@@ -133,3 +168,114 @@ func addBlockAmount(
 	})
 	return
 }
+
+// PurchaseTxParams contains the input parameters of the purchase transaction
+type PurchaseTxParams struct {
+	UserID     uuid.UUID `json:"user_id"`
+	PropertyID int64     `json:"property_id"`
+	Amount     int64     `json:"amount"`
+}
+
+// PurchaseTxResult is the result of the purchase transaction
+type PurchaseTxResult struct {
+	Purchase    Purchase    `json:"purchase"`
+	Property    Property    `json:"property"`
+	Account     Account     `json:"account"`
+	Entry       Entry       `json:"entry"`
+	Wallet      Wallet      `json:"wallet"`
+	WalletEntry WalletEntry `json:"wallet_entry"`
+}
+
+// PurchaseTx buys blocks of a property on the primary market.
+// It locks the property row so concurrent buyers are serialized, checks the remaining supply,
+// creates the buyer's account if missing, records the purchase and the account entry,
+// then debits the price from the buyer's wallet within a single database transaction.
+func (store *SQLStore) PurchaseTx(ctx context.Context, arg PurchaseTxParams) (PurchaseTxResult, error) {
+	var result PurchaseTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		property, err := q.GetPropertyForUpdate(ctx, arg.PropertyID)
+		if err != nil {
+			return err
+		}
+		if property.Archived {
+			return ErrPropertyNotForSale
+		}
+		if property.RemainingBlockCount < arg.Amount {
+			return ErrInsufficientBlocks
+		}
+		cost, err := blocksCost(property.PricePerBlock, arg.Amount)
+		if err != nil {
+			return err
+		}
+
+		account, err := getOrCreateAccount(ctx, q, arg.UserID, arg.PropertyID)
+		if err != nil {
+			return err
+		}
+
+		result.Purchase, err = q.CreatePurchase(ctx, CreatePurchaseParams{
+			AccountID:  account.ID,
+			PropertyID: arg.PropertyID,
+			Amount:     arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Entry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: account.ID,
+			Amount:    arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Property, err = q.AddPropertyRemainingBlockCount(ctx, AddPropertyRemainingBlockCountParams{
+			ID:     arg.PropertyID,
+			Amount: -arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Account, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:     account.ID,
+			Amount: arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		if cost == 0 {
+			return nil
+		}
+
+		wallet, err := getOrCreateWallet(ctx, q, arg.UserID, property.Currency)
+		if err != nil {
+			return err
+		}
+
+		reference := fmt.Sprintf("purchase:%d", result.Purchase.ID)
+		result.Wallet, result.WalletEntry, err = addWalletEntry(ctx, q, wallet, -cost, WalletEntryPurchase, reference)
+		return err
+	})
+
+	return result, err
+}
+
+// getOrCreateAccount returns the user's account for a property, opening an empty one if needed.
+func getOrCreateAccount(ctx context.Context, q *Queries, userID uuid.UUID, propertyID int64) (Account, error) {
+	account, err := q.GetAccountByProperty(ctx, GetAccountByPropertyParams{
+		UserID:     userID,
+		PropertyID: propertyID,
+	})
+	if err == sql.ErrNoRows {
+		return q.CreateAccount(ctx, CreateAccountParams{
+			UserID:     userID,
+			Balance:    0,
+			PropertyID: propertyID,
+		})
+	}
+	return account, err
+}
@@ -3,8 +3,10 @@ package db
 import (
 	"context"
 	"fmt"
+	"math"
 	"testing"
 
+	"github.com/awakim/immoblock-backend/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -162,3 +164,104 @@ func TestTransferTxDeadLock(t *testing.T) {
 	require.Equal(t, account1.Balance, updatedAccount1.Balance)
 	require.Equal(t, account2.Balance, updatedAccount2.Balance)
 }
+
+func TestPurchaseTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	property, err := testQueries.CreateProperty(context.Background(), CreatePropertyParams{
+		Name:                util.RandomString(6),
+		Description:         util.RandomString(32),
+		InitialBlockCount:   10,
+		RemainingBlockCount: 10,
+	})
+	require.NoError(t, err)
+	user := createRandomUser(t)
+	fmt.Println(">> before:", property.RemainingBlockCount)
+
+	// run more concurrent purchases than the property can supply
+	amount := int64(1)
+	n := int(property.RemainingBlockCount) + 5
+
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.PurchaseTx(context.Background(), PurchaseTxParams{
+				UserID:     user.ID,
+				PropertyID: property.ID,
+				Amount:     amount,
+			})
+
+			errs <- err
+		}()
+	}
+
+	// check results
+	succeeded := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err != nil {
+			require.ErrorIs(t, err, ErrInsufficientBlocks)
+			continue
+		}
+		succeeded++
+	}
+	require.Equal(t, int(property.RemainingBlockCount), succeeded)
+
+	// check the final property supply and buyer balance
+	updatedProperty, err := testQueries.GetProperty(context.Background(), property.ID)
+	require.NoError(t, err)
+	fmt.Println(">> after:", updatedProperty.RemainingBlockCount)
+	require.Zero(t, updatedProperty.RemainingBlockCount)
+
+	account, err := testQueries.GetAccountByProperty(context.Background(), GetAccountByPropertyParams{
+		UserID:     user.ID,
+		PropertyID: property.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, property.InitialBlockCount, account.Balance)
+}
+
+func TestPurchaseTxArchivedProperty(t *testing.T) {
+	store := NewStore(testDB)
+
+	property := createRandomProperty(t)
+	user := createRandomUser(t)
+
+	_, err := testQueries.ArchiveProperty(context.Background(), property.ID)
+	require.NoError(t, err)
+
+	_, err = store.PurchaseTx(context.Background(), PurchaseTxParams{
+		UserID:     user.ID,
+		PropertyID: property.ID,
+		Amount:     1,
+	})
+	require.ErrorIs(t, err, ErrPropertyNotForSale)
+}
+
+func TestPurchaseTxCostTooLarge(t *testing.T) {
+	store := NewStore(testDB)
+
+	property, err := testQueries.CreateProperty(context.Background(), CreatePropertyParams{
+		Name:                util.RandomString(6),
+		Description:         util.RandomString(32),
+		InitialBlockCount:   math.MaxInt64 / 2,
+		RemainingBlockCount: math.MaxInt64 / 2,
+		PricePerBlock:       3,
+		Currency:            "EUR",
+	})
+	require.NoError(t, err)
+	user := createRandomUser(t)
+
+	_, err = store.PurchaseTx(context.Background(), PurchaseTxParams{
+		UserID:     user.ID,
+		PropertyID: property.ID,
+		Amount:     property.RemainingBlockCount,
+	})
+	require.ErrorIs(t, err, ErrCostTooLarge)
+
+	// nothing has been sold
+	updatedProperty, err := testQueries.GetProperty(context.Background(), property.ID)
+	require.NoError(t, err)
+	require.Equal(t, property.RemainingBlockCount, updatedProperty.RemainingBlockCount)
+}
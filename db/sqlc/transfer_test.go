@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
 
@@ -62,10 +63,9 @@ func TestListTransfer(t *testing.T) {
 	}
 
 	arg := ListTransfersParams{
-		FromAccountID: account1.ID,
-		ToAccountID:   account1.ID,
-		Limit:         5,
-		Offset:        5,
+		AccountID: account1.ID,
+		Limit:     5,
+		Offset:    5,
 	}
 
 	transfers, err := testQueries.ListTransfers(context.Background(), arg)
@@ -77,3 +77,65 @@ func TestListTransfer(t *testing.T) {
 		require.True(t, transfer.FromAccountID == account1.ID || transfer.ToAccountID == account1.ID)
 	}
 }
+
+func TestListAccountTransfers(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	for i := 0; i < 5; i++ {
+		createRandomTransfer(t, account1, account2)
+		createRandomTransfer(t, account2, account1)
+	}
+
+	arg := ListAccountTransfersParams{
+		AccountID: account1.ID,
+		Cursor:    math.MaxInt64,
+		StartTime: time.Now().Add(-time.Hour),
+		EndTime:   time.Now().Add(time.Hour),
+		Limit:     5,
+	}
+
+	page1, err := testQueries.ListAccountTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page1, 5)
+
+	arg.Cursor = page1[len(page1)-1].ID
+	page2, err := testQueries.ListAccountTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page2, 5)
+
+	transfers := append(page1, page2...)
+	for i, transfer := range transfers {
+		require.True(t, transfer.FromAccountID == account1.ID || transfer.ToAccountID == account1.ID)
+		if i > 0 {
+			require.Less(t, transfer.ID, transfers[i-1].ID)
+		}
+	}
+}
+
+func TestListUserTransfers(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	account3 := createRandomAccount(t)
+
+	for i := 0; i < 3; i++ {
+		createRandomTransfer(t, account1, account2)
+		createRandomTransfer(t, account2, account1)
+	}
+	createRandomTransfer(t, account2, account3)
+
+	transfers, err := testQueries.ListUserTransfers(context.Background(), ListUserTransfersParams{
+		UserID: account1.UserID,
+		Limit:  10,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, transfers, 6)
+
+	for i, transfer := range transfers {
+		require.True(t, transfer.FromAccountID == account1.ID || transfer.ToAccountID == account1.ID)
+		if i > 0 {
+			require.Less(t, transfer.ID, transfers[i-1].ID)
+		}
+	}
+}
@@ -5,6 +5,9 @@ package db
 
 import (
 	"context"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 const createUser = `-- name: CreateUser :one
@@ -14,7 +17,7 @@ INSERT INTO users (
   email
 ) VALUES (
   $1, $2, $3
-) RETURNING id, email, hashed_password, nickname, phone_number, password_changed_at, created_at, is_admin
+) RETURNING id, email, hashed_password, nickname, phone_number, password_changed_at, created_at, email_verified_at, locked_at, locked_reason
 `
 
 type CreateUserParams struct {
@@ -34,13 +37,15 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.PhoneNumber,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
-		&i.IsAdmin,
+		&i.EmailVerifiedAt,
+		&i.LockedAt,
+		&i.LockedReason,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, email, hashed_password, nickname, phone_number, password_changed_at, created_at, is_admin FROM users
+SELECT id, email, hashed_password, nickname, phone_number, password_changed_at, created_at, email_verified_at, locked_at, locked_reason FROM users
 WHERE email = $1 LIMIT 1
 `
 
@@ -55,7 +60,191 @@ func (q *Queries) GetUser(ctx context.Context, email string) (User, error) {
 		&i.PhoneNumber,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
-		&i.IsAdmin,
+		&i.EmailVerifiedAt,
+		&i.LockedAt,
+		&i.LockedReason,
+	)
+	return i, err
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT id, email, hashed_password, nickname, phone_number, password_changed_at, created_at, email_verified_at, locked_at, locked_reason FROM users
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByID, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.HashedPassword,
+		&i.Nickname,
+		&i.PhoneNumber,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.EmailVerifiedAt,
+		&i.LockedAt,
+		&i.LockedReason,
+	)
+	return i, err
+}
+
+const lockUser = `-- name: LockUser :one
+UPDATE users
+SET locked_at = now(), locked_reason = $2
+WHERE id = $1
+RETURNING id, email, hashed_password, nickname, phone_number, password_changed_at, created_at, email_verified_at, locked_at, locked_reason
+`
+
+type LockUserParams struct {
+	ID           uuid.UUID `json:"id"`
+	LockedReason string    `json:"locked_reason"`
+}
+
+func (q *Queries) LockUser(ctx context.Context, arg LockUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, lockUser, arg.ID, arg.LockedReason)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.HashedPassword,
+		&i.Nickname,
+		&i.PhoneNumber,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.EmailVerifiedAt,
+		&i.LockedAt,
+		&i.LockedReason,
+	)
+	return i, err
+}
+
+const searchUsers = `-- name: SearchUsers :many
+SELECT id, email, hashed_password, nickname, phone_number, password_changed_at, created_at, email_verified_at, locked_at, locked_reason FROM users
+WHERE email ILIKE '%' || $1::text || '%' OR nickname ILIKE '%' || $1::text || '%'
+ORDER BY created_at DESC, id
+LIMIT $2
+OFFSET $3
+`
+
+type SearchUsersParams struct {
+	Query  string `json:"query"`
+	Limit  int32  `json:"limit"`
+	Offset int32  `json:"offset"`
+}
+
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, searchUsers, arg.Query, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.HashedPassword,
+			&i.Nickname,
+			&i.PhoneNumber,
+			&i.PasswordChangedAt,
+			&i.CreatedAt,
+			&i.EmailVerifiedAt,
+			&i.LockedAt,
+			&i.LockedReason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const unlockUser = `-- name: UnlockUser :one
+UPDATE users
+SET locked_at = '0001-01-01 00:00:00Z', locked_reason = ''
+WHERE id = $1
+RETURNING id, email, hashed_password, nickname, phone_number, password_changed_at, created_at, email_verified_at, locked_at, locked_reason
+`
+
+func (q *Queries) UnlockUser(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, unlockUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.HashedPassword,
+		&i.Nickname,
+		&i.PhoneNumber,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.EmailVerifiedAt,
+		&i.LockedAt,
+		&i.LockedReason,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :one
+UPDATE users
+SET hashed_password = $2, password_changed_at = $3
+WHERE id = $1
+RETURNING id, email, hashed_password, nickname, phone_number, password_changed_at, created_at, email_verified_at, locked_at, locked_reason
+`
+
+type UpdateUserPasswordParams struct {
+	ID                uuid.UUID `json:"id"`
+	HashedPassword    string    `json:"hashed_password"`
+	PasswordChangedAt time.Time `json:"password_changed_at"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserPassword, arg.ID, arg.HashedPassword, arg.PasswordChangedAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.HashedPassword,
+		&i.Nickname,
+		&i.PhoneNumber,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.EmailVerifiedAt,
+		&i.LockedAt,
+		&i.LockedReason,
+	)
+	return i, err
+}
+
+const verifyUserEmail = `-- name: VerifyUserEmail :one
+UPDATE users
+SET email_verified_at = now()
+WHERE id = $1
+RETURNING id, email, hashed_password, nickname, phone_number, password_changed_at, created_at, email_verified_at, locked_at, locked_reason
+`
+
+func (q *Queries) VerifyUserEmail(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, verifyUserEmail, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.HashedPassword,
+		&i.Nickname,
+		&i.PhoneNumber,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.EmailVerifiedAt,
+		&i.LockedAt,
+		&i.LockedReason,
 	)
 	return i, err
 }
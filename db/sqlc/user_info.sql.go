@@ -23,7 +23,7 @@ INSERT INTO user_information (
   verification_step
 ) VALUES (
   $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
-) RETURNING user_id, firstname, lastname, phone_number, nationality, address, postal_code, city, country, verification_step
+) RETURNING user_id, firstname, lastname, phone_number, nationality, address, postal_code, city, country, verification_step, verification_reason, kyc_applicant_id
 `
 
 type CreateUserInfoParams struct {
@@ -64,6 +64,45 @@ func (q *Queries) CreateUserInfo(ctx context.Context, arg CreateUserInfoParams)
 		&i.City,
 		&i.Country,
 		&i.VerificationStep,
+		&i.VerificationReason,
+		&i.KycApplicantID,
+	)
+	return i, err
+}
+
+const createUserInfoChange = `-- name: CreateUserInfoChange :one
+INSERT INTO user_info_changes (
+  user_id,
+  field,
+  old_value,
+  new_value
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, user_id, field, old_value, new_value, created_at
+`
+
+type CreateUserInfoChangeParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Field    string    `json:"field"`
+	OldValue string    `json:"old_value"`
+	NewValue string    `json:"new_value"`
+}
+
+func (q *Queries) CreateUserInfoChange(ctx context.Context, arg CreateUserInfoChangeParams) (UserInfoChange, error) {
+	row := q.db.QueryRowContext(ctx, createUserInfoChange,
+		arg.UserID,
+		arg.Field,
+		arg.OldValue,
+		arg.NewValue,
+	)
+	var i UserInfoChange
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Field,
+		&i.OldValue,
+		&i.NewValue,
+		&i.CreatedAt,
 	)
 	return i, err
 }
@@ -83,7 +122,7 @@ func (q *Queries) ExistsUserInfo(ctx context.Context, userID uuid.UUID) (bool, e
 }
 
 const getUserInfo = `-- name: GetUserInfo :one
-SELECT user_id, firstname, lastname, phone_number, nationality, address, postal_code, city, country, verification_step FROM user_information
+SELECT user_id, firstname, lastname, phone_number, nationality, address, postal_code, city, country, verification_step, verification_reason, kyc_applicant_id FROM user_information
 WHERE user_id = $1 LIMIT 1
 `
 
@@ -101,6 +140,225 @@ func (q *Queries) GetUserInfo(ctx context.Context, userID uuid.UUID) (UserInform
 		&i.City,
 		&i.Country,
 		&i.VerificationStep,
+		&i.VerificationReason,
+		&i.KycApplicantID,
+	)
+	return i, err
+}
+
+const getUserInfoByKYCApplicant = `-- name: GetUserInfoByKYCApplicant :one
+SELECT user_id, firstname, lastname, phone_number, nationality, address, postal_code, city, country, verification_step, verification_reason, kyc_applicant_id FROM user_information
+WHERE kyc_applicant_id = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserInfoByKYCApplicant(ctx context.Context, kycApplicantID string) (UserInformation, error) {
+	row := q.db.QueryRowContext(ctx, getUserInfoByKYCApplicant, kycApplicantID)
+	var i UserInformation
+	err := row.Scan(
+		&i.UserID,
+		&i.Firstname,
+		&i.Lastname,
+		&i.PhoneNumber,
+		&i.Nationality,
+		&i.Address,
+		&i.PostalCode,
+		&i.City,
+		&i.Country,
+		&i.VerificationStep,
+		&i.VerificationReason,
+		&i.KycApplicantID,
+	)
+	return i, err
+}
+
+const getUserInfoForUpdate = `-- name: GetUserInfoForUpdate :one
+SELECT user_id, firstname, lastname, phone_number, nationality, address, postal_code, city, country, verification_step, verification_reason, kyc_applicant_id FROM user_information
+WHERE user_id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetUserInfoForUpdate(ctx context.Context, userID uuid.UUID) (UserInformation, error) {
+	row := q.db.QueryRowContext(ctx, getUserInfoForUpdate, userID)
+	var i UserInformation
+	err := row.Scan(
+		&i.UserID,
+		&i.Firstname,
+		&i.Lastname,
+		&i.PhoneNumber,
+		&i.Nationality,
+		&i.Address,
+		&i.PostalCode,
+		&i.City,
+		&i.Country,
+		&i.VerificationStep,
+		&i.VerificationReason,
+		&i.KycApplicantID,
+	)
+	return i, err
+}
+
+const listUserInfoChanges = `-- name: ListUserInfoChanges :many
+SELECT id, user_id, field, old_value, new_value, created_at FROM user_info_changes
+WHERE user_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3
+`
+
+type ListUserInfoChangesParams struct {
+	UserID uuid.UUID `json:"user_id"`
+	Limit  int32     `json:"limit"`
+	Offset int32     `json:"offset"`
+}
+
+func (q *Queries) ListUserInfoChanges(ctx context.Context, arg ListUserInfoChangesParams) ([]UserInfoChange, error) {
+	rows, err := q.db.QueryContext(ctx, listUserInfoChanges, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []UserInfoChange{}
+	for rows.Next() {
+		var i UserInfoChange
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Field,
+			&i.OldValue,
+			&i.NewValue,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setKYCApplicant = `-- name: SetKYCApplicant :one
+UPDATE user_information
+SET kyc_applicant_id = $2
+WHERE user_id = $1
+RETURNING user_id, firstname, lastname, phone_number, nationality, address, postal_code, city, country, verification_step, verification_reason, kyc_applicant_id
+`
+
+type SetKYCApplicantParams struct {
+	UserID         uuid.UUID `json:"user_id"`
+	KycApplicantID string    `json:"kyc_applicant_id"`
+}
+
+func (q *Queries) SetKYCApplicant(ctx context.Context, arg SetKYCApplicantParams) (UserInformation, error) {
+	row := q.db.QueryRowContext(ctx, setKYCApplicant, arg.UserID, arg.KycApplicantID)
+	var i UserInformation
+	err := row.Scan(
+		&i.UserID,
+		&i.Firstname,
+		&i.Lastname,
+		&i.PhoneNumber,
+		&i.Nationality,
+		&i.Address,
+		&i.PostalCode,
+		&i.City,
+		&i.Country,
+		&i.VerificationStep,
+		&i.VerificationReason,
+		&i.KycApplicantID,
+	)
+	return i, err
+}
+
+const updateUserInfo = `-- name: UpdateUserInfo :one
+UPDATE user_information
+SET
+  firstname = $2,
+  lastname = $3,
+  phone_number = $4,
+  nationality = $5,
+  address = $6,
+  postal_code = $7,
+  city = $8,
+  country = $9
+WHERE user_id = $1
+RETURNING user_id, firstname, lastname, phone_number, nationality, address, postal_code, city, country, verification_step, verification_reason, kyc_applicant_id
+`
+
+type UpdateUserInfoParams struct {
+	UserID      uuid.UUID `json:"user_id"`
+	Firstname   string    `json:"firstname"`
+	Lastname    string    `json:"lastname"`
+	PhoneNumber string    `json:"phone_number"`
+	Nationality string    `json:"nationality"`
+	Address     string    `json:"address"`
+	PostalCode  string    `json:"postal_code"`
+	City        string    `json:"city"`
+	Country     string    `json:"country"`
+}
+
+func (q *Queries) UpdateUserInfo(ctx context.Context, arg UpdateUserInfoParams) (UserInformation, error) {
+	row := q.db.QueryRowContext(ctx, updateUserInfo,
+		arg.UserID,
+		arg.Firstname,
+		arg.Lastname,
+		arg.PhoneNumber,
+		arg.Nationality,
+		arg.Address,
+		arg.PostalCode,
+		arg.City,
+		arg.Country,
+	)
+	var i UserInformation
+	err := row.Scan(
+		&i.UserID,
+		&i.Firstname,
+		&i.Lastname,
+		&i.PhoneNumber,
+		&i.Nationality,
+		&i.Address,
+		&i.PostalCode,
+		&i.City,
+		&i.Country,
+		&i.VerificationStep,
+		&i.VerificationReason,
+		&i.KycApplicantID,
+	)
+	return i, err
+}
+
+const updateVerificationStep = `-- name: UpdateVerificationStep :one
+UPDATE user_information
+SET verification_step = $2, verification_reason = $3
+WHERE user_id = $1
+RETURNING user_id, firstname, lastname, phone_number, nationality, address, postal_code, city, country, verification_step, verification_reason, kyc_applicant_id
+`
+
+type UpdateVerificationStepParams struct {
+	UserID             uuid.UUID `json:"user_id"`
+	VerificationStep   int16     `json:"verification_step"`
+	VerificationReason string    `json:"verification_reason"`
+}
+
+func (q *Queries) UpdateVerificationStep(ctx context.Context, arg UpdateVerificationStepParams) (UserInformation, error) {
+	row := q.db.QueryRowContext(ctx, updateVerificationStep, arg.UserID, arg.VerificationStep, arg.VerificationReason)
+	var i UserInformation
+	err := row.Scan(
+		&i.UserID,
+		&i.Firstname,
+		&i.Lastname,
+		&i.PhoneNumber,
+		&i.Nationality,
+		&i.Address,
+		&i.PostalCode,
+		&i.City,
+		&i.Country,
+		&i.VerificationStep,
+		&i.VerificationReason,
+		&i.KycApplicantID,
 	)
 	return i, err
 }
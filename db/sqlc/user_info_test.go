@@ -2,9 +2,12 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"testing"
 
 	"github.com/awakim/immoblock-backend/util"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 )
 
@@ -12,15 +15,16 @@ func createRandomUserInfo(t *testing.T) UserInformation {
 	user := createRandomUser(t)
 
 	arg := CreateUserInfoParams{
-		UserID:      user.ID,
-		Firstname:   util.RandomString(6),
-		Lastname:    util.RandomString(6),
-		PhoneNumber: util.RandomPhoneNumber(),
-		Nationality: util.RandomString(6),
-		Address:     util.RandomString(16),
-		PostalCode:  util.RandomString(6),
-		City:        util.RandomString(6),
-		Country:     util.RandomString(6),
+		UserID:           user.ID,
+		Firstname:        util.RandomString(6),
+		Lastname:         util.RandomString(6),
+		PhoneNumber:      util.RandomPhoneNumber(),
+		Nationality:      util.RandomString(6),
+		Address:          util.RandomString(16),
+		PostalCode:       util.RandomString(6),
+		City:             util.RandomString(6),
+		Country:          util.RandomString(6),
+		VerificationStep: VerificationInfoSubmitted,
 	}
 
 	userInfo, err := testQueries.CreateUserInfo(context.Background(), arg)
@@ -36,6 +40,7 @@ func createRandomUserInfo(t *testing.T) UserInformation {
 	require.Equal(t, arg.PostalCode, userInfo.PostalCode)
 	require.Equal(t, arg.City, userInfo.City)
 	require.Equal(t, arg.Country, userInfo.Country)
+	require.Equal(t, arg.VerificationStep, userInfo.VerificationStep)
 
 	return userInfo
 }
@@ -60,3 +65,148 @@ func TestGetUserInfo(t *testing.T) {
 	require.Equal(t, userInfo2.City, userInfo.City)
 	require.Equal(t, userInfo2.Country, userInfo.Country)
 }
+
+func TestUpdateVerificationStep(t *testing.T) {
+	userInfo := createRandomUserInfo(t)
+
+	arg := UpdateVerificationStepParams{
+		UserID:             userInfo.UserID,
+		VerificationStep:   userInfo.VerificationStep + 1,
+		VerificationReason: "documents are blurry",
+	}
+	userInfo2, err := testQueries.UpdateVerificationStep(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.VerificationStep, userInfo2.VerificationStep)
+	require.Equal(t, arg.VerificationReason, userInfo2.VerificationReason)
+	require.Equal(t, userInfo.Firstname, userInfo2.Firstname)
+}
+
+func TestUpdateUserInfoTx(t *testing.T) {
+	store := NewStore(testDB)
+	userInfo := createRandomUserInfo(t)
+
+	_, err := store.TransitionVerificationTx(context.Background(), TransitionVerificationTxParams{
+		UserID: userInfo.UserID,
+		ToStep: VerificationDocumentsUploaded,
+	})
+	require.NoError(t, err)
+
+	// contact details are changed without touching the verification
+	arg := UpdateUserInfoTxParams{
+		UserID:      userInfo.UserID,
+		PhoneNumber: sql.NullString{String: util.RandomPhoneNumber(), Valid: true},
+		Address:     sql.NullString{String: util.RandomString(16), Valid: true},
+		City:        sql.NullString{String: userInfo.City, Valid: true},
+	}
+	result, err := store.UpdateUserInfoTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.PhoneNumber.String, result.UserInfo.PhoneNumber)
+	require.Equal(t, arg.Address.String, result.UserInfo.Address)
+	require.Equal(t, userInfo.Firstname, result.UserInfo.Firstname)
+	require.Equal(t, VerificationDocumentsUploaded, result.UserInfo.VerificationStep)
+	require.Zero(t, result.Transition.ID)
+
+	require.Len(t, result.Changes, 2)
+	require.Equal(t, "phone_number", result.Changes[0].Field)
+	require.Equal(t, userInfo.PhoneNumber, result.Changes[0].OldValue)
+	require.Equal(t, arg.PhoneNumber.String, result.Changes[0].NewValue)
+	require.Equal(t, "address", result.Changes[1].Field)
+
+	// a change of the legal identity is verified again
+	arg2 := UpdateUserInfoTxParams{
+		UserID:   userInfo.UserID,
+		Lastname: sql.NullString{String: util.RandomString(6), Valid: true},
+	}
+	result, err = store.UpdateUserInfoTx(context.Background(), arg2)
+	require.NoError(t, err)
+	require.Equal(t, arg2.Lastname.String, result.UserInfo.Lastname)
+	require.Equal(t, VerificationInfoSubmitted, result.UserInfo.VerificationStep)
+	require.Equal(t, VerificationDocumentsUploaded, result.Transition.FromStep)
+	require.Len(t, result.Changes, 1)
+
+	changes, err := store.ListUserInfoChanges(context.Background(), ListUserInfoChangesParams{
+		UserID: userInfo.UserID,
+		Limit:  10,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	require.Equal(t, "lastname", changes[0].Field)
+}
+
+func TestUpdateUserInfoTxValidate(t *testing.T) {
+	store := NewStore(testDB)
+	userInfo := createRandomUserInfo(t)
+	errInvalid := errors.New("invalid postal code")
+
+	city := util.RandomString(8)
+	_, err := store.UpdateUserInfoTx(context.Background(), UpdateUserInfoTxParams{
+		UserID:     userInfo.UserID,
+		PostalCode: sql.NullString{String: util.RandomString(5), Valid: true},
+		City:       sql.NullString{String: city, Valid: true},
+		Validate: func(merged UserInformation) error {
+			// the stored fields are merged with the changes
+			require.Equal(t, userInfo.Country, merged.Country)
+			require.Equal(t, city, merged.City)
+			return errInvalid
+		},
+	})
+	require.ErrorIs(t, err, errInvalid)
+
+	stored, err := store.GetUserInfo(context.Background(), userInfo.UserID)
+	require.NoError(t, err)
+	require.Equal(t, userInfo.PostalCode, stored.PostalCode)
+	require.Equal(t, userInfo.City, stored.City)
+
+	changes, err := store.ListUserInfoChanges(context.Background(), ListUserInfoChangesParams{
+		UserID: userInfo.UserID,
+		Limit:  10,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestUpdateUserInfoTxForgetsKYCApplicant(t *testing.T) {
+	store := NewStore(testDB)
+	userInfo := createUserInfoUnderReview(t, store)
+
+	result, err := store.UpdateUserInfoTx(context.Background(), UpdateUserInfoTxParams{
+		UserID:      userInfo.UserID,
+		Nationality: sql.NullString{String: util.RandomString(6), Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, VerificationInfoSubmitted, result.UserInfo.VerificationStep)
+	require.Empty(t, result.UserInfo.KycApplicantID)
+
+	// a late decision on the previous identity no longer finds the user
+	_, err = store.GetUserInfoByKYCApplicant(context.Background(), userInfo.KycApplicantID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	stored, err := store.GetUserInfo(context.Background(), userInfo.UserID)
+	require.NoError(t, err)
+	require.Empty(t, stored.KycApplicantID)
+}
+
+func TestUpdateUserInfoTxPhoneTaken(t *testing.T) {
+	store := NewStore(testDB)
+	userInfo1 := createRandomUserInfo(t)
+	userInfo2 := createRandomUserInfo(t)
+
+	_, err := store.UpdateUserInfoTx(context.Background(), UpdateUserInfoTxParams{
+		UserID:      userInfo2.UserID,
+		PhoneNumber: sql.NullString{String: userInfo1.PhoneNumber, Valid: true},
+	})
+	require.Error(t, err)
+	pqErr, ok := err.(*pq.Error)
+	require.True(t, ok)
+	require.Equal(t, "unique_violation", pqErr.Code.Name())
+
+	changes, err := store.ListUserInfoChanges(context.Background(), ListUserInfoChangesParams{
+		UserID: userInfo2.UserID,
+		Limit:  10,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
@@ -0,0 +1,149 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+// legalIdentityFields are the fields of the user information checked by the identity verification
+var legalIdentityFields = map[string]bool{
+	"firstname":   true,
+	"lastname":    true,
+	"nationality": true,
+}
+
+// UpdateUserInfoTxParams contains the input parameters of the user information update transaction.
+// Only the valid fields are changed.
+type UpdateUserInfoTxParams struct {
+	UserID      uuid.UUID      `json:"user_id"`
+	Firstname   sql.NullString `json:"firstname"`
+	Lastname    sql.NullString `json:"lastname"`
+	PhoneNumber sql.NullString `json:"phone_number"`
+	Nationality sql.NullString `json:"nationality"`
+	Address     sql.NullString `json:"address"`
+	PostalCode  sql.NullString `json:"postal_code"`
+	City        sql.NullString `json:"city"`
+	Country     sql.NullString `json:"country"`
+	// Validate is called with the information merged with the changes before it is saved,
+	// an error rolls the update back
+	Validate func(userInfo UserInformation) error `json:"-"`
+}
+
+// UpdateUserInfoTxResult is the result of the user information update transaction
+type UpdateUserInfoTxResult struct {
+	UserInfo   UserInformation        `json:"user_info"`
+	Changes    []UserInfoChange       `json:"changes"`
+	Transition VerificationTransition `json:"transition"`
+}
+
+// UpdateUserInfoTx changes the information of the user and records every changed field. Contact
+// details can be changed freely, a change of the legal identity sends the verification back to
+// info submitted so that it is verified again, and forgets the applicant submitted to the KYC provider.
+func (store *SQLStore) UpdateUserInfoTx(ctx context.Context, arg UpdateUserInfoTxParams) (UpdateUserInfoTxResult, error) {
+	var result UpdateUserInfoTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		userInfo, err := q.GetUserInfoForUpdate(ctx, arg.UserID)
+		if err != nil {
+			return err
+		}
+
+		fields := []struct {
+			name   string
+			value  *string
+			change sql.NullString
+		}{
+			{"firstname", &userInfo.Firstname, arg.Firstname},
+			{"lastname", &userInfo.Lastname, arg.Lastname},
+			{"phone_number", &userInfo.PhoneNumber, arg.PhoneNumber},
+			{"nationality", &userInfo.Nationality, arg.Nationality},
+			{"address", &userInfo.Address, arg.Address},
+			{"postal_code", &userInfo.PostalCode, arg.PostalCode},
+			{"city", &userInfo.City, arg.City},
+			{"country", &userInfo.Country, arg.Country},
+		}
+
+		legalIdentityChanged := false
+		for _, field := range fields {
+			if !field.change.Valid || field.change.String == *field.value {
+				continue
+			}
+
+			change, err := q.CreateUserInfoChange(ctx, CreateUserInfoChangeParams{
+				UserID:   arg.UserID,
+				Field:    field.name,
+				OldValue: *field.value,
+				NewValue: field.change.String,
+			})
+			if err != nil {
+				return err
+			}
+			result.Changes = append(result.Changes, change)
+
+			*field.value = field.change.String
+			legalIdentityChanged = legalIdentityChanged || legalIdentityFields[field.name]
+		}
+
+		result.UserInfo = userInfo
+		if len(result.Changes) == 0 {
+			return nil
+		}
+
+		if arg.Validate != nil {
+			if err := arg.Validate(userInfo); err != nil {
+				return err
+			}
+		}
+
+		result.UserInfo, err = q.UpdateUserInfo(ctx, UpdateUserInfoParams{
+			UserID:      userInfo.UserID,
+			Firstname:   userInfo.Firstname,
+			Lastname:    userInfo.Lastname,
+			PhoneNumber: userInfo.PhoneNumber,
+			Nationality: userInfo.Nationality,
+			Address:     userInfo.Address,
+			PostalCode:  userInfo.PostalCode,
+			City:        userInfo.City,
+			Country:     userInfo.Country,
+		})
+		if err != nil {
+			return err
+		}
+
+		if !legalIdentityChanged {
+			return nil
+		}
+
+		// the decisions of the KYC provider on the previous identity must not apply to the new one
+		if result.UserInfo.KycApplicantID != "" {
+			result.UserInfo, err = q.SetKYCApplicant(ctx, SetKYCApplicantParams{
+				UserID:         arg.UserID,
+				KycApplicantID: "",
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if userInfo.VerificationStep == VerificationInfoSubmitted {
+			return nil
+		}
+
+		transition, err := transitionVerification(ctx, q, TransitionVerificationTxParams{
+			UserID:  arg.UserID,
+			ToStep:  VerificationInfoSubmitted,
+			ActorID: uuid.NullUUID{},
+			Reason:  "legal identity changed",
+		})
+		if err != nil {
+			return err
+		}
+		result.UserInfo = transition.UserInfo
+		result.Transition = transition.Transition
+		return nil
+	})
+
+	return result, err
+}
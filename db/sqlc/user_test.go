@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -28,6 +29,7 @@ func createRandomUser(t *testing.T) User {
 	require.Equal(t, arg.Email, user.Email)
 
 	require.True(t, user.PasswordChangedAt.IsZero())
+	require.True(t, user.EmailVerifiedAt.IsZero())
 	require.NotZero(t, user.CreatedAt)
 
 	return user
@@ -49,5 +51,86 @@ func TestGetUser(t *testing.T) {
 	require.Equal(t, user1.Email, user2.Email)
 	require.WithinDuration(t, user1.PasswordChangedAt, user2.PasswordChangedAt, time.Second)
 	require.WithinDuration(t, user1.CreatedAt, user2.CreatedAt, time.Second)
-	require.Equal(t, user1.IsAdmin, user2.IsAdmin)
+}
+
+func TestGetUserByID(t *testing.T) {
+	user1 := createRandomUser(t)
+	user2, err := testQueries.GetUserByID(context.Background(), user1.ID)
+	require.NoError(t, err)
+	require.Equal(t, user1.Email, user2.Email)
+	require.Equal(t, user1.Nickname, user2.Nickname)
+}
+
+func TestVerifyUserEmail(t *testing.T) {
+	user1 := createRandomUser(t)
+	user2, err := testQueries.VerifyUserEmail(context.Background(), user1.ID)
+	require.NoError(t, err)
+	require.Equal(t, user1.ID, user2.ID)
+	require.WithinDuration(t, time.Now(), user2.EmailVerifiedAt, time.Second)
+}
+
+func TestUpdateUserPassword(t *testing.T) {
+	user1 := createRandomUser(t)
+
+	hashedPassword, err := util.HashPassword(util.RandomString(8))
+	require.NoError(t, err)
+
+	arg := UpdateUserPasswordParams{
+		ID:                user1.ID,
+		HashedPassword:    hashedPassword,
+		PasswordChangedAt: time.Now().UTC(),
+	}
+	user2, err := testQueries.UpdateUserPassword(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, user1.ID, user2.ID)
+	require.Equal(t, arg.HashedPassword, user2.HashedPassword)
+	require.WithinDuration(t, arg.PasswordChangedAt, user2.PasswordChangedAt, time.Millisecond)
+	require.True(t, user2.PasswordChangedAt.After(user1.PasswordChangedAt))
+}
+
+func TestSearchUsers(t *testing.T) {
+	user := createRandomUser(t)
+
+	for _, query := range []string{user.Email, strings.ToUpper(user.Nickname), user.Nickname[1:4]} {
+		users, err := testQueries.SearchUsers(context.Background(), SearchUsersParams{
+			Query:  query,
+			Limit:  100,
+			Offset: 0,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, users)
+
+		found := false
+		for _, u := range users {
+			found = found || u.ID == user.ID
+		}
+		require.True(t, found, "user not found by %q", query)
+	}
+
+	users, err := testQueries.SearchUsers(context.Background(), SearchUsersParams{
+		Query:  util.RandomString(12) + "@nowhere",
+		Limit:  100,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Empty(t, users)
+}
+
+func TestLockUser(t *testing.T) {
+	user := createRandomUser(t)
+	require.True(t, user.LockedAt.IsZero())
+	require.Empty(t, user.LockedReason)
+
+	locked, err := testQueries.LockUser(context.Background(), LockUserParams{
+		ID:           user.ID,
+		LockedReason: "fraud suspicion",
+	})
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now(), locked.LockedAt, time.Second)
+	require.Equal(t, "fraud suspicion", locked.LockedReason)
+
+	unlocked, err := testQueries.UnlockUser(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.True(t, unlocked.LockedAt.IsZero())
+	require.Empty(t, unlocked.LockedReason)
 }
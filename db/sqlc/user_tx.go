@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+)
+
+// CreateUserTxParams contains the input parameters of the user creation transaction
+type CreateUserTxParams struct {
+	CreateUserParams
+	Roles []string `json:"roles"`
+	// AfterCreate is called with the new user before the commit, an error rolls the user back
+	AfterCreate func(user User) error `json:"-"`
+}
+
+// CreateUserTxResult is the result of the user creation transaction
+type CreateUserTxResult struct {
+	User  User     `json:"user"`
+	Roles []string `json:"roles"`
+}
+
+// CreateUserTx creates a user along with its initial roles. The user is only committed once
+// AfterCreate succeeds, so that it can be registered with other services first.
+func (store *SQLStore) CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error) {
+	var result CreateUserTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.User, err = q.CreateUser(ctx, arg.CreateUserParams)
+		if err != nil {
+			return err
+		}
+
+		for _, role := range arg.Roles {
+			err = q.AddUserRole(ctx, AddUserRoleParams{
+				UserID: result.User.ID,
+				Role:   role,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Roles, err = q.ListUserRoles(ctx, result.User.ID)
+		if err != nil {
+			return err
+		}
+
+		if arg.AfterCreate != nil {
+			return arg.AfterCreate(result.User)
+		}
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,140 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Steps of the identity verification of a user, stored in user_information.verification_step
+const (
+	VerificationInfoSubmitted     int16 = 1
+	VerificationDocumentsUploaded int16 = 2
+	VerificationUnderReview       int16 = 3
+	VerificationApproved          int16 = 4
+	VerificationRejected          int16 = 5
+	VerificationExpired           int16 = 6
+)
+
+// ErrInvalidVerificationTransition is returned when the verification cannot move to the requested step
+var ErrInvalidVerificationTransition = errors.New("invalid verification transition")
+
+var verificationStatuses = map[int16]string{
+	VerificationInfoSubmitted:     "info_submitted",
+	VerificationDocumentsUploaded: "documents_uploaded",
+	VerificationUnderReview:       "under_review",
+	VerificationApproved:          "approved",
+	VerificationRejected:          "rejected",
+	VerificationExpired:           "expired",
+}
+
+// verificationTransitions lists the steps each step can move to. The documents can be uploaded
+// again after a rejection or an expiry, the other steps are taken by the compliance team or
+// the verification provider. A change of the legal identity of the user sends any verification
+// back to info submitted.
+var verificationTransitions = map[int16][]int16{
+	VerificationInfoSubmitted:     {VerificationDocumentsUploaded},
+	VerificationDocumentsUploaded: {VerificationInfoSubmitted, VerificationDocumentsUploaded, VerificationUnderReview, VerificationRejected},
+	VerificationUnderReview:       {VerificationInfoSubmitted, VerificationApproved, VerificationRejected},
+	VerificationApproved:          {VerificationInfoSubmitted, VerificationExpired},
+	VerificationRejected:          {VerificationInfoSubmitted, VerificationDocumentsUploaded},
+	VerificationExpired:           {VerificationInfoSubmitted, VerificationDocumentsUploaded},
+}
+
+// VerificationStatus returns the name of a verification step
+func VerificationStatus(step int16) string {
+	if status, ok := verificationStatuses[step]; ok {
+		return status
+	}
+	return fmt.Sprintf("unknown(%d)", step)
+}
+
+// ParseVerificationStatus returns the verification step of a name
+func ParseVerificationStatus(status string) (int16, bool) {
+	for step, name := range verificationStatuses {
+		if name == status {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// CanTransitionVerification tells if the verification can move from a step to another
+func CanTransitionVerification(from int16, to int16) bool {
+	for _, step := range verificationTransitions[from] {
+		if step == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionVerificationTxParams contains the input parameters of the verification transition transaction
+type TransitionVerificationTxParams struct {
+	UserID  uuid.UUID     `json:"user_id"`
+	ToStep  int16         `json:"to_step"`
+	ActorID uuid.NullUUID `json:"actor_id"`
+	Reason  string        `json:"reason"`
+}
+
+// TransitionVerificationTxResult is the result of the verification transition transaction
+type TransitionVerificationTxResult struct {
+	UserInfo   UserInformation        `json:"user_info"`
+	Transition VerificationTransition `json:"transition"`
+}
+
+// TransitionVerificationTx moves the identity verification of the user to another step and records
+// the transition. It fails with ErrInvalidVerificationTransition unless the current step allows it.
+func (store *SQLStore) TransitionVerificationTx(ctx context.Context, arg TransitionVerificationTxParams) (TransitionVerificationTxResult, error) {
+	var result TransitionVerificationTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = transitionVerification(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+// transitionVerification moves the verification within the transaction of q, locking the user information until it ends.
+func transitionVerification(ctx context.Context, q *Queries, arg TransitionVerificationTxParams) (TransitionVerificationTxResult, error) {
+	var result TransitionVerificationTxResult
+
+	userInfo, err := q.GetUserInfoForUpdate(ctx, arg.UserID)
+	if err != nil {
+		return result, err
+	}
+
+	from := userInfo.VerificationStep
+	if !CanTransitionVerification(from, arg.ToStep) {
+		return result, fmt.Errorf("%w: from %s to %s", ErrInvalidVerificationTransition,
+			VerificationStatus(from), VerificationStatus(arg.ToStep))
+	}
+
+	// the reason is only kept on the user information while the verification is rejected
+	reason := ""
+	if arg.ToStep == VerificationRejected {
+		reason = arg.Reason
+	}
+
+	result.UserInfo, err = q.UpdateVerificationStep(ctx, UpdateVerificationStepParams{
+		UserID:             arg.UserID,
+		VerificationStep:   arg.ToStep,
+		VerificationReason: reason,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Transition, err = q.CreateVerificationTransition(ctx, CreateVerificationTransitionParams{
+		UserID:   arg.UserID,
+		FromStep: from,
+		ToStep:   arg.ToStep,
+		ActorID:  arg.ActorID,
+		Reason:   arg.Reason,
+	})
+	return result, err
+}
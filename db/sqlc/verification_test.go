@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanTransitionVerification(t *testing.T) {
+	require.True(t, CanTransitionVerification(VerificationInfoSubmitted, VerificationDocumentsUploaded))
+	require.True(t, CanTransitionVerification(VerificationUnderReview, VerificationApproved))
+	require.True(t, CanTransitionVerification(VerificationRejected, VerificationDocumentsUploaded))
+	require.True(t, CanTransitionVerification(VerificationApproved, VerificationExpired))
+	require.True(t, CanTransitionVerification(VerificationApproved, VerificationInfoSubmitted))
+
+	require.False(t, CanTransitionVerification(VerificationInfoSubmitted, VerificationApproved))
+	require.False(t, CanTransitionVerification(VerificationDocumentsUploaded, VerificationApproved))
+	require.False(t, CanTransitionVerification(VerificationApproved, VerificationRejected))
+	require.False(t, CanTransitionVerification(VerificationExpired, VerificationApproved))
+	require.False(t, CanTransitionVerification(0, VerificationInfoSubmitted))
+	require.False(t, CanTransitionVerification(VerificationInfoSubmitted, VerificationInfoSubmitted))
+}
+
+func TestParseVerificationStatus(t *testing.T) {
+	for step := VerificationInfoSubmitted; step <= VerificationExpired; step++ {
+		parsed, ok := ParseVerificationStatus(VerificationStatus(step))
+		require.True(t, ok)
+		require.Equal(t, step, parsed)
+	}
+
+	_, ok := ParseVerificationStatus("verified")
+	require.False(t, ok)
+}
+
+func TestTransitionVerificationTx(t *testing.T) {
+	store := NewStore(testDB)
+	userInfo := createRandomUserInfo(t)
+	staff := createRandomUser(t)
+	actor := uuid.NullUUID{UUID: staff.ID, Valid: true}
+
+	steps := []struct {
+		to      int16
+		actorID uuid.NullUUID
+		reason  string
+	}{
+		{VerificationDocumentsUploaded, uuid.NullUUID{}, ""},
+		{VerificationUnderReview, actor, ""},
+		{VerificationRejected, actor, "documents are blurry"},
+		{VerificationDocumentsUploaded, uuid.NullUUID{}, ""},
+		{VerificationUnderReview, actor, ""},
+		{VerificationApproved, actor, ""},
+	}
+
+	from := userInfo.VerificationStep
+	for _, step := range steps {
+		result, err := store.TransitionVerificationTx(context.Background(), TransitionVerificationTxParams{
+			UserID:  userInfo.UserID,
+			ToStep:  step.to,
+			ActorID: step.actorID,
+			Reason:  step.reason,
+		})
+		require.NoError(t, err)
+		require.Equal(t, step.to, result.UserInfo.VerificationStep)
+		require.Equal(t, step.reason, result.UserInfo.VerificationReason)
+
+		require.Equal(t, userInfo.UserID, result.Transition.UserID)
+		require.Equal(t, from, result.Transition.FromStep)
+		require.Equal(t, step.to, result.Transition.ToStep)
+		require.Equal(t, step.actorID, result.Transition.ActorID)
+		require.Equal(t, step.reason, result.Transition.Reason)
+		from = step.to
+	}
+
+	// an approved verification cannot be rejected, and the step is left unchanged
+	_, err := store.TransitionVerificationTx(context.Background(), TransitionVerificationTxParams{
+		UserID:  userInfo.UserID,
+		ToStep:  VerificationRejected,
+		ActorID: actor,
+		Reason:  "changed my mind",
+	})
+	require.True(t, errors.Is(err, ErrInvalidVerificationTransition))
+
+	userInfo2, err := store.GetUserInfo(context.Background(), userInfo.UserID)
+	require.NoError(t, err)
+	require.Equal(t, VerificationApproved, userInfo2.VerificationStep)
+
+	transitions, err := store.ListVerificationTransitions(context.Background(), ListVerificationTransitionsParams{
+		UserID: userInfo.UserID,
+		Limit:  10,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, transitions, len(steps))
+	require.Equal(t, VerificationApproved, transitions[0].ToStep)
+	require.Equal(t, VerificationDocumentsUploaded, transitions[len(steps)-1].ToStep)
+}
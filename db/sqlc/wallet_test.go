@@ -0,0 +1,169 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomWallet(t *testing.T) Wallet {
+	user := createRandomUser(t)
+
+	arg := CreateWalletParams{
+		UserID:   user.ID,
+		Currency: "EUR",
+	}
+
+	wallet, err := testQueries.CreateWallet(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, wallet)
+
+	require.Equal(t, arg.UserID, wallet.UserID)
+	require.Equal(t, arg.Currency, wallet.Currency)
+	require.Zero(t, wallet.Balance)
+
+	require.NotZero(t, wallet.ID)
+	require.NotZero(t, wallet.CreatedAt)
+
+	return wallet
+}
+
+func TestCreateWallet(t *testing.T) {
+	createRandomWallet(t)
+}
+
+func TestGetWallet(t *testing.T) {
+	wallet1 := createRandomWallet(t)
+	wallet2, err := testQueries.GetWallet(context.Background(), wallet1.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, wallet2)
+
+	require.Equal(t, wallet1.ID, wallet2.ID)
+	require.Equal(t, wallet1.UserID, wallet2.UserID)
+	require.Equal(t, wallet1.Currency, wallet2.Currency)
+	require.Equal(t, wallet1.Balance, wallet2.Balance)
+	require.WithinDuration(t, wallet1.CreatedAt, wallet2.CreatedAt, time.Second)
+
+	wallet3, err := testQueries.GetWalletByCurrency(context.Background(), GetWalletByCurrencyParams{
+		UserID:   wallet1.UserID,
+		Currency: wallet1.Currency,
+	})
+	require.NoError(t, err)
+	require.Equal(t, wallet1.ID, wallet3.ID)
+}
+
+func TestAddWalletBalance(t *testing.T) {
+	wallet1 := createRandomWallet(t)
+	amount := util.RandomMoney() + 1
+
+	wallet2, err := testQueries.AddWalletBalance(context.Background(), AddWalletBalanceParams{
+		ID:     wallet1.ID,
+		Amount: amount,
+	})
+	require.NoError(t, err)
+	require.Equal(t, wallet1.Balance+amount, wallet2.Balance)
+
+	// the balance can never go below zero
+	_, err = testQueries.AddWalletBalance(context.Background(), AddWalletBalanceParams{
+		ID:     wallet1.ID,
+		Amount: -(amount + 1),
+	})
+	require.Error(t, err)
+}
+
+func TestListWalletEntries(t *testing.T) {
+	wallet := createRandomWallet(t)
+	for i := 0; i < 10; i++ {
+		_, err := testQueries.CreateWalletEntry(context.Background(), CreateWalletEntryParams{
+			WalletID:  wallet.ID,
+			Amount:    util.RandomMoney(),
+			Kind:      WalletEntryDeposit,
+			Reference: util.RandomString(12),
+		})
+		require.NoError(t, err)
+	}
+
+	entries, err := testQueries.ListWalletEntries(context.Background(), ListWalletEntriesParams{
+		WalletID: wallet.ID,
+		Limit:    5,
+		Offset:   5,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 5)
+
+	for _, entry := range entries {
+		require.NotEmpty(t, entry)
+		require.Equal(t, wallet.ID, entry.WalletID)
+	}
+}
+
+func TestSetWalletEntryReference(t *testing.T) {
+	wallet := createRandomWallet(t)
+	entry, err := testQueries.CreateWalletEntry(context.Background(), CreateWalletEntryParams{
+		WalletID: wallet.ID,
+		Amount:   -util.RandomMoney(),
+		Kind:     WalletEntryWithdrawal,
+	})
+	require.NoError(t, err)
+	require.Empty(t, entry.Reference)
+
+	reference := util.RandomString(12)
+	entry2, err := testQueries.SetWalletEntryReference(context.Background(), SetWalletEntryReferenceParams{
+		ID:        entry.ID,
+		Reference: reference,
+	})
+	require.NoError(t, err)
+	require.Equal(t, entry.ID, entry2.ID)
+	require.Equal(t, entry.Amount, entry2.Amount)
+	require.Equal(t, reference, entry2.Reference)
+}
+
+func TestWalletTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	amount := int64(1000)
+
+	deposit, err := store.WalletTx(context.Background(), WalletTxParams{
+		UserID:    user.ID,
+		Currency:  "EUR",
+		Amount:    amount,
+		Kind:      WalletEntryDeposit,
+		Reference: util.RandomString(12),
+	})
+	require.NoError(t, err)
+	require.Equal(t, amount, deposit.Wallet.Balance)
+	require.Equal(t, amount, deposit.Entry.Amount)
+
+	// run concurrent withdrawals totalling more than the balance
+	n := 5
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.WalletTx(context.Background(), WalletTxParams{
+				UserID:   user.ID,
+				Currency: "EUR",
+				Amount:   -amount / 2,
+				Kind:     WalletEntryWithdrawal,
+			})
+			errs <- err
+		}()
+	}
+
+	succeeded := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err != nil {
+			require.ErrorIs(t, err, ErrInsufficientFunds)
+			continue
+		}
+		succeeded++
+	}
+	require.Equal(t, 2, succeeded)
+
+	wallet, err := store.GetWallet(context.Background(), deposit.Wallet.ID)
+	require.NoError(t, err)
+	require.Zero(t, wallet.Balance)
+}
@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Kinds of wallet entries
+const (
+	WalletEntryDeposit            = "deposit"
+	WalletEntryWithdrawal         = "withdrawal"
+	WalletEntryWithdrawalReversal = "withdrawal_reversal"
+	WalletEntryPurchase           = "purchase"
+	WalletEntryTrade              = "trade"
+	WalletEntryDistribution       = "distribution"
+)
+
+// WalletTxParams contains the input parameters of the wallet transaction
+type WalletTxParams struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Currency  string    `json:"currency"`
+	Amount    int64     `json:"amount"`
+	Kind      string    `json:"kind"`
+	Reference string    `json:"reference"`
+}
+
+// WalletTxResult is the result of the wallet transaction
+type WalletTxResult struct {
+	Wallet Wallet      `json:"wallet"`
+	Entry  WalletEntry `json:"entry"`
+}
+
+// WalletTx credits (positive amount) or debits (negative amount) the user's wallet in a currency.
+// The wallet is opened on first use. Debits may not spend the cash reserved by open buy orders.
+func (store *SQLStore) WalletTx(ctx context.Context, arg WalletTxParams) (WalletTxResult, error) {
+	var result WalletTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		wallet, err := getOrCreateWallet(ctx, q, arg.UserID, arg.Currency)
+		if err != nil {
+			return err
+		}
+
+		result.Wallet, result.Entry, err = addWalletEntry(ctx, q, wallet, arg.Amount, arg.Kind, arg.Reference)
+		return err
+	})
+
+	return result, err
+}
+
+// getOrCreateWallet returns the user's wallet for a currency, opening an empty one if needed.
+func getOrCreateWallet(ctx context.Context, q *Queries, userID uuid.UUID, currency string) (Wallet, error) {
+	wallet, err := q.GetWalletByCurrency(ctx, GetWalletByCurrencyParams{
+		UserID:   userID,
+		Currency: currency,
+	})
+	if err == sql.ErrNoRows {
+		return q.CreateWallet(ctx, CreateWalletParams{
+			UserID:   userID,
+			Currency: currency,
+		})
+	}
+	return wallet, err
+}
+
+// addWalletEntry records a wallet entry and updates the wallet balance.
+// The balance update locks the wallet row, so the available funds check after it
+// holds against concurrent debits.
+func addWalletEntry(ctx context.Context, q *Queries, wallet Wallet, amount int64, kind string, reference string) (Wallet, WalletEntry, error) {
+	entry, err := q.CreateWalletEntry(ctx, CreateWalletEntryParams{
+		WalletID:  wallet.ID,
+		Amount:    amount,
+		Kind:      kind,
+		Reference: reference,
+	})
+	if err != nil {
+		return wallet, entry, err
+	}
+
+	wallet, err = q.AddWalletBalance(ctx, AddWalletBalanceParams{
+		ID:     wallet.ID,
+		Amount: amount,
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "check_violation" {
+			return wallet, entry, ErrInsufficientFunds
+		}
+		return wallet, entry, err
+	}
+
+	if amount < 0 {
+		reserved, err := q.GetOpenBuyCost(ctx, GetOpenBuyCostParams{
+			UserID:   wallet.UserID,
+			Currency: wallet.Currency,
+		})
+		if err != nil {
+			return wallet, entry, err
+		}
+		if wallet.Balance < reserved {
+			return wallet, entry, ErrInsufficientFunds
+		}
+	}
+
+	return wallet, entry, nil
+}
+
+// transferCash moves an amount between two wallets of the same currency.
+// Balances are always updated in ascending wallet ID order to avoid deadlocks.
+func transferCash(ctx context.Context, q *Queries, fromWallet Wallet, toWallet Wallet, amount int64, kind string, reference string) error {
+	if fromWallet.Currency != toWallet.Currency {
+		return fmt.Errorf("currency mismatch: %s vs %s", fromWallet.Currency, toWallet.Currency)
+	}
+
+	var err error
+	if fromWallet.ID < toWallet.ID {
+		_, _, err = addWalletEntry(ctx, q, fromWallet, -amount, kind, reference)
+		if err != nil {
+			return err
+		}
+		_, _, err = addWalletEntry(ctx, q, toWallet, amount, kind, reference)
+	} else {
+		_, _, err = addWalletEntry(ctx, q, toWallet, amount, kind, reference)
+		if err != nil {
+			return err
+		}
+		_, _, err = addWalletEntry(ctx, q, fromWallet, -amount, kind, reference)
+	}
+	return err
+}
@@ -1,7 +1,43 @@
 package identity
 
-import "github.com/auth0/go-auth0/management"
+import (
+	"github.com/auth0/go-auth0/management"
+	"github.com/google/uuid"
+)
+
+// Connection is the Auth0 database connection of the users.
+const Connection = "Username-Password-Authentication"
 
 type UserManager interface {
 	Create(u *management.User, opts ...management.RequestOption) error
+	Read(id string, opts ...management.RequestOption) (*management.User, error)
+	Update(id string, u *management.User, opts ...management.RequestOption) error
+	Delete(id string, opts ...management.RequestOption) error
+}
+
+// UserID returns the Auth0 ID of a user, the users are created in Auth0 with their ID in our database.
+func UserID(id uuid.UUID) string {
+	return "auth0|" + id.String()
+}
+
+// SetEmailVerified marks the email address of the user as verified.
+func SetEmailVerified(m UserManager, id uuid.UUID) error {
+	verified := true
+	return m.Update(UserID(id), &management.User{EmailVerified: &verified})
+}
+
+// SetPassword replaces the password of the user.
+func SetPassword(m UserManager, id uuid.UUID, password string) error {
+	connection := Connection
+	return m.Update(UserID(id), &management.User{Password: &password, Connection: &connection})
+}
+
+// Block prevents the user from logging in with Auth0, or allows them again.
+func Block(m UserManager, id uuid.UUID, blocked bool) error {
+	return m.Update(UserID(id), &management.User{Blocked: &blocked})
+}
+
+// Delete removes the user from Auth0.
+func Delete(m UserManager, id uuid.UUID) error {
+	return m.Delete(UserID(id))
 }
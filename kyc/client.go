@@ -0,0 +1,83 @@
+package kyc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// HTTPProvider is a Provider calling the REST API of a KYC provider.
+type HTTPProvider struct {
+	baseURL       string
+	apiKey        string
+	webhookSecret string
+	client        *http.Client
+}
+
+// NewHTTPProvider creates a new HTTPProvider calling the API at baseURL. The webhooks are
+// verified with webhookSecret.
+func NewHTTPProvider(baseURL string, apiKey string, webhookSecret string, client *http.Client) (*HTTPProvider, error) {
+	if baseURL == "" || apiKey == "" || webhookSecret == "" {
+		return nil, fmt.Errorf("KYC provider URL, API key and webhook secret are required")
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPProvider{
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		apiKey:        apiKey,
+		webhookSecret: webhookSecret,
+		client:        client,
+	}, nil
+}
+
+type submitApplicantResponse struct {
+	ID string `json:"id"`
+}
+
+// SubmitApplicant creates the applicant at the provider.
+func (provider *HTTPProvider) SubmitApplicant(ctx context.Context, applicant Applicant) (string, error) {
+	body, err := json.Marshal(applicant)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.baseURL+"/applicants", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+provider.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := provider.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return "", fmt.Errorf("KYC provider answered %d: %s", res.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	var rsp submitApplicantResponse
+	if err := json.Unmarshal(data, &rsp); err != nil {
+		return "", fmt.Errorf("cannot decode KYC provider response: %w", err)
+	}
+	if rsp.ID == "" {
+		return "", fmt.Errorf("KYC provider returned no applicant ID")
+	}
+	return rsp.ID, nil
+}
+
+// ParseWebhook verifies the signature of a webhook of the provider and decodes its event.
+func (provider *HTTPProvider) ParseWebhook(header http.Header, body []byte) (Event, error) {
+	return parseWebhook(provider.webhookSecret, header, body)
+}
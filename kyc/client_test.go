@@ -0,0 +1,59 @@
+package kyc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPProviderSubmitApplicant(t *testing.T) {
+	apiKey := util.RandomString(16)
+	applicant := Applicant{
+		UserID:    uuid.New(),
+		Firstname: "John",
+		Lastname:  "Doe",
+		Country:   "FR",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/applicants", r.URL.Path)
+		if r.Header.Get("Authorization") != "Bearer "+apiKey {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var received Applicant
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		require.Equal(t, applicant, received)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"app_42"}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewHTTPProvider(server.URL+"/", apiKey, util.RandomString(32), server.Client())
+	require.NoError(t, err)
+
+	id, err := provider.SubmitApplicant(context.Background(), applicant)
+	require.NoError(t, err)
+	require.Equal(t, "app_42", id)
+
+	provider, err = NewHTTPProvider(server.URL, util.RandomString(16), util.RandomString(32), server.Client())
+	require.NoError(t, err)
+
+	_, err = provider.SubmitApplicant(context.Background(), applicant)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "401")
+}
+
+func TestNewHTTPProviderMissingConfig(t *testing.T) {
+	_, err := NewHTTPProvider("", "key", "secret", nil)
+	require.Error(t, err)
+}
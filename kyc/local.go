@@ -0,0 +1,76 @@
+package kyc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LocalProvider is an in-process Provider for development and tests. Applicants are kept in
+// memory and decisions are made by calling Decide, which returns a webhook signed as the
+// provider would sign it.
+type LocalProvider struct {
+	WebhookSecret string
+
+	mu         sync.Mutex
+	Applicants map[string]Applicant
+}
+
+// NewLocalProvider creates a new LocalProvider signing its webhooks with webhookSecret.
+func NewLocalProvider(webhookSecret string) *LocalProvider {
+	return &LocalProvider{
+		WebhookSecret: webhookSecret,
+		Applicants:    make(map[string]Applicant),
+	}
+}
+
+// SubmitApplicant records the applicant.
+func (provider *LocalProvider) SubmitApplicant(ctx context.Context, applicant Applicant) (string, error) {
+	id := fmt.Sprintf("local_%s", uuid.New())
+
+	provider.mu.Lock()
+	provider.Applicants[id] = applicant
+	provider.mu.Unlock()
+
+	log.Printf("local KYC applicant %s submitted for user %s", id, applicant.UserID)
+	return id, nil
+}
+
+// ParseWebhook verifies the signature of a webhook made by Decide and decodes its event.
+func (provider *LocalProvider) ParseWebhook(header http.Header, body []byte) (Event, error) {
+	return parseWebhook(provider.WebhookSecret, header, body)
+}
+
+// Decide makes a decision on a submitted applicant and returns the webhook body and headers
+// the provider would send.
+func (provider *LocalProvider) Decide(applicantID string, decision string, reason string, screening Screening) ([]byte, http.Header, error) {
+	provider.mu.Lock()
+	applicant, ok := provider.Applicants[applicantID]
+	provider.mu.Unlock()
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown applicant %s", applicantID)
+	}
+
+	body, err := json.Marshal(Event{
+		ID:          fmt.Sprintf("evt_%s", uuid.New()),
+		ApplicantID: applicantID,
+		UserID:      applicant.UserID,
+		Decision:    decision,
+		Reason:      reason,
+		Screening:   screening,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set(SignatureHeader, Sign(provider.WebhookSecret, body, time.Now()))
+	return body, header, nil
+}
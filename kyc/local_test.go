@@ -0,0 +1,32 @@
+package kyc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalProvider(t *testing.T) {
+	provider := NewLocalProvider(util.RandomString(32))
+	applicant := Applicant{UserID: uuid.New(), Firstname: "John", Lastname: "Doe"}
+
+	id, err := provider.SubmitApplicant(context.Background(), applicant)
+	require.NoError(t, err)
+	require.Equal(t, applicant, provider.Applicants[id])
+
+	body, header, err := provider.Decide(id, DecisionRejected, "document expired", Screening{})
+	require.NoError(t, err)
+
+	event, err := provider.ParseWebhook(header, body)
+	require.NoError(t, err)
+	require.Equal(t, id, event.ApplicantID)
+	require.Equal(t, applicant.UserID, event.UserID)
+	require.Equal(t, DecisionRejected, event.Decision)
+	require.Equal(t, "document expired", event.Reason)
+
+	_, _, err = provider.Decide("unknown", DecisionApproved, "", Screening{})
+	require.Error(t, err)
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/awakim/immoblock-backend/kyc (interfaces: Provider)
+
+// Package mockkyc is a generated GoMock package.
+package mockkyc
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	kyc "github.com/awakim/immoblock-backend/kyc"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockProvider is a mock of Provider interface.
+type MockProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockProviderMockRecorder
+}
+
+// MockProviderMockRecorder is the mock recorder for MockProvider.
+type MockProviderMockRecorder struct {
+	mock *MockProvider
+}
+
+// NewMockProvider creates a new mock instance.
+func NewMockProvider(ctrl *gomock.Controller) *MockProvider {
+	mock := &MockProvider{ctrl: ctrl}
+	mock.recorder = &MockProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProvider) EXPECT() *MockProviderMockRecorder {
+	return m.recorder
+}
+
+// ParseWebhook mocks base method.
+func (m *MockProvider) ParseWebhook(arg0 http.Header, arg1 []byte) (kyc.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseWebhook", arg0, arg1)
+	ret0, _ := ret[0].(kyc.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseWebhook indicates an expected call of ParseWebhook.
+func (mr *MockProviderMockRecorder) ParseWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseWebhook", reflect.TypeOf((*MockProvider)(nil).ParseWebhook), arg0, arg1)
+}
+
+// SubmitApplicant mocks base method.
+func (m *MockProvider) SubmitApplicant(arg0 context.Context, arg1 kyc.Applicant) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitApplicant", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitApplicant indicates an expected call of SubmitApplicant.
+func (mr *MockProviderMockRecorder) SubmitApplicant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitApplicant", reflect.TypeOf((*MockProvider)(nil).SubmitApplicant), arg0, arg1)
+}
@@ -0,0 +1,154 @@
+package kyc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Decisions of the provider on an applicant
+const (
+	DecisionApproved = "approved"
+	DecisionRejected = "rejected"
+	DecisionReview   = "review"
+)
+
+// SignatureHeader is the header carrying the signature of the webhooks
+const SignatureHeader = "X-KYC-Signature"
+
+// SignatureTolerance is how old a webhook can be, to limit replays
+const SignatureTolerance = 5 * time.Minute
+
+var (
+	// ErrInvalidSignature is returned when a webhook is not signed with the secret or is too old
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrInvalidEvent is returned when a signed webhook cannot be decoded
+	ErrInvalidEvent = errors.New("invalid webhook event")
+)
+
+// Applicant is a user whose identity is checked by the provider.
+type Applicant struct {
+	UserID      uuid.UUID `json:"external_id"`
+	Firstname   string    `json:"first_name"`
+	Lastname    string    `json:"last_name"`
+	PhoneNumber string    `json:"phone_number"`
+	Nationality string    `json:"nationality"`
+	Address     string    `json:"address"`
+	PostalCode  string    `json:"postal_code"`
+	City        string    `json:"city"`
+	Country     string    `json:"country"`
+}
+
+// Screening is the result of the sanctions and politically exposed persons screening of an applicant.
+type Screening struct {
+	SanctionsHit bool     `json:"sanctions_hit"`
+	PEPHit       bool     `json:"pep_hit"`
+	Matches      []string `json:"matches,omitempty"`
+}
+
+// Hit tells if the screening matched a sanctions list or a politically exposed person
+func (screening Screening) Hit() bool {
+	return screening.SanctionsHit || screening.PEPHit
+}
+
+// Event is a decision of the provider on an applicant, received through a webhook.
+type Event struct {
+	ID          string    `json:"id"`
+	ApplicantID string    `json:"applicant_id"`
+	UserID      uuid.UUID `json:"external_id"`
+	Decision    string    `json:"decision"`
+	Reason      string    `json:"reason,omitempty"`
+	Screening   Screening `json:"screening"`
+}
+
+// Provider checks the identity of the applicants and screens them against sanctions and PEP lists.
+type Provider interface {
+	// SubmitApplicant sends the applicant for checking and returns their ID at the provider.
+	// The decision is sent later to the webhook.
+	SubmitApplicant(ctx context.Context, applicant Applicant) (string, error)
+	// ParseWebhook verifies the signature of a webhook and returns its event.
+	ParseWebhook(header http.Header, body []byte) (Event, error)
+}
+
+func signature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sign returns the signature header value of a webhook body sent at the time given,
+// in the form t=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<body>">.
+func Sign(secret string, body []byte, at time.Time) string {
+	timestamp := at.Unix()
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, signature(secret, timestamp, body))
+}
+
+// VerifySignature checks the signature header value of a webhook body received at the time given.
+func VerifySignature(secret string, value string, body []byte, now time.Time) error {
+	var timestamp int64
+	var signatures []string
+	for _, part := range strings.Split(value, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			t, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil {
+				return ErrInvalidSignature
+			}
+			timestamp = t
+		case "v1":
+			signatures = append(signatures, kv[1])
+		}
+	}
+	if timestamp == 0 || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	age := now.Sub(time.Unix(timestamp, 0))
+	if age > SignatureTolerance || age < -SignatureTolerance {
+		return fmt.Errorf("%w: timestamp out of tolerance", ErrInvalidSignature)
+	}
+
+	expected := signature(secret, timestamp, body)
+	for _, sig := range signatures {
+		if hmac.Equal([]byte(sig), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// parseWebhook verifies the signature of a webhook with the secret and decodes its event.
+func parseWebhook(secret string, header http.Header, body []byte) (Event, error) {
+	if err := VerifySignature(secret, header.Get(SignatureHeader), body, time.Now()); err != nil {
+		return Event{}, err
+	}
+
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return Event{}, fmt.Errorf("%w: %s", ErrInvalidEvent, err)
+	}
+	if event.ID == "" || event.ApplicantID == "" {
+		return Event{}, fmt.Errorf("%w: missing id", ErrInvalidEvent)
+	}
+	switch event.Decision {
+	case DecisionApproved, DecisionRejected, DecisionReview:
+	default:
+		return Event{}, fmt.Errorf("%w: unknown decision %q", ErrInvalidEvent, event.Decision)
+	}
+	return event, nil
+}
@@ -0,0 +1,81 @@
+package kyc
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySignature(t *testing.T) {
+	secret := util.RandomString(32)
+	body := []byte(`{"id":"evt_1"}`)
+	now := time.Now()
+
+	value := Sign(secret, body, now)
+	require.NoError(t, VerifySignature(secret, value, body, now))
+	require.NoError(t, VerifySignature(secret, value, body, now.Add(SignatureTolerance-time.Second)))
+
+	// rotated secrets are sent as several v1 signatures
+	require.NoError(t, VerifySignature(secret, "v1=0000,"+value, body, now))
+
+	testCases := []struct {
+		name  string
+		value string
+		body  []byte
+		now   time.Time
+	}{
+		{"Empty", "", body, now},
+		{"OtherSecret", Sign(util.RandomString(32), body, now), body, now},
+		{"OtherBody", value, []byte(`{"id":"evt_2"}`), now},
+		{"Expired", value, body, now.Add(SignatureTolerance + time.Second)},
+		{"Future", value, body, now.Add(-SignatureTolerance - time.Second)},
+		{"NoTimestamp", "v1=" + signature(secret, 0, body), body, now},
+		{"BadTimestamp", "t=abc,v1=00", body, now},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			err := VerifySignature(secret, tc.value, tc.body, tc.now)
+			require.True(t, errors.Is(err, ErrInvalidSignature))
+		})
+	}
+}
+
+func TestParseWebhook(t *testing.T) {
+	secret := util.RandomString(32)
+
+	event := Event{
+		ID:          "evt_1",
+		ApplicantID: "app_1",
+		UserID:      uuid.New(),
+		Decision:    DecisionApproved,
+		Screening:   Screening{PEPHit: true, Matches: []string{"John Doe, mayor"}},
+	}
+	body, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	header := http.Header{}
+	header.Set(SignatureHeader, Sign(secret, body, time.Now()))
+
+	parsed, err := parseWebhook(secret, header, body)
+	require.NoError(t, err)
+	require.Equal(t, event, parsed)
+	require.True(t, parsed.Screening.Hit())
+
+	_, err = parseWebhook(util.RandomString(32), header, body)
+	require.True(t, errors.Is(err, ErrInvalidSignature))
+
+	event.Decision = "maybe"
+	body, err = json.Marshal(event)
+	require.NoError(t, err)
+	header.Set(SignatureHeader, Sign(secret, body, time.Now()))
+	_, err = parseWebhook(secret, header, body)
+	require.True(t, errors.Is(err, ErrInvalidEvent))
+}
@@ -0,0 +1,55 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// Message is an email sent to a single recipient.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails to the users.
+type Mailer interface {
+	// Send delivers a message or returns an error when it cannot be accepted.
+	Send(ctx context.Context, msg Message) error
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9@._-]`)
+
+// LocalMailer is a Mailer for development and tests.
+// Messages are logged and, when Dir is set, written to a file per message in that directory.
+type LocalMailer struct {
+	Dir string
+}
+
+// NewLocalMailer creates a new LocalMailer writing to dir, or only logging when dir is empty.
+func NewLocalMailer(dir string) (*LocalMailer, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("cannot create mail directory: %w", err)
+		}
+	}
+	return &LocalMailer{Dir: dir}, nil
+}
+
+// Send logs the message and writes it to the mail directory.
+func (mailer *LocalMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("local mail to %s: %s", msg.To, msg.Subject)
+	if mailer.Dir == "" {
+		return nil
+	}
+
+	name := fmt.Sprintf("%d_%s.eml", time.Now().UnixNano(), unsafeFileChars.ReplaceAllString(msg.To, "_"))
+	content := fmt.Sprintf("To: %s\r\nSubject: %s\r\n\r\n%s\r\n", msg.To, msg.Subject, msg.Body)
+	return ioutil.WriteFile(filepath.Join(mailer.Dir, name), []byte(content), 0o600)
+}
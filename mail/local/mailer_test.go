@@ -0,0 +1,43 @@
+package mail
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	mailer, err := NewLocalMailer(dir)
+	require.NoError(t, err)
+
+	msg := Message{
+		To:      "john/doe@example.com",
+		Subject: "Hello",
+		Body:    "World",
+	}
+	err = mailer.Send(context.Background(), msg)
+	require.NoError(t, err)
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Contains(t, files[0].Name(), "john_doe@example.com")
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	require.Contains(t, string(data), "To: john/doe@example.com")
+	require.Contains(t, string(data), "Subject: Hello")
+	require.Contains(t, string(data), "World")
+}
+
+func TestLocalMailerLogOnly(t *testing.T) {
+	mailer, err := NewLocalMailer("")
+	require.NoError(t, err)
+
+	err = mailer.Send(context.Background(), Message{To: "john@example.com"})
+	require.NoError(t, err)
+}
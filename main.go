@@ -16,6 +16,10 @@ import (
 	"github.com/awakim/immoblock-backend/config"
 	db "github.com/awakim/immoblock-backend/db/sqlc"
 	identity "github.com/awakim/immoblock-backend/identity/auth0"
+	"github.com/awakim/immoblock-backend/kyc"
+	mail "github.com/awakim/immoblock-backend/mail/local"
+	payment "github.com/awakim/immoblock-backend/payment/local"
+	storage "github.com/awakim/immoblock-backend/storage/local"
 	"github.com/go-redis/redis/v8"
 	_ "github.com/lib/pq"
 )
@@ -52,8 +56,34 @@ func main() {
 	store := db.NewStore(conn)
 	cache := cache.NewCache(rdb)
 	userManager := identity.NewUserManager(m)
+	paymentProvider := payment.NewLocalProvider()
+	mailer, err := mail.NewLocalMailer(config.MailDir)
+	if err != nil {
+		log.Fatal("cannot create mailer:", err)
+	}
+	localStorage, err := storage.NewLocalStorage(config.DocumentDir)
+	if err != nil {
+		log.Fatal("cannot create document storage:", err)
+	}
+	documentStorage, err := storage.NewEncryptedStorage(localStorage, []byte(config.DocumentEncryptionKey))
+	if err != nil {
+		log.Fatal("cannot create document storage:", err)
+	}
+
+	var kycProvider kyc.Provider
+	switch config.KYCProvider {
+	case "http":
+		kycProvider, err = kyc.NewHTTPProvider(config.KYCProviderURL, config.KYCAPIKey, config.KYCWebhookSecret, &http.Client{Timeout: 10 * time.Second})
+		if err != nil {
+			log.Fatal("cannot create KYC provider:", err)
+		}
+	case "local", "":
+		kycProvider = kyc.NewLocalProvider(config.KYCWebhookSecret)
+	default:
+		log.Fatalf("unknown KYC provider %q", config.KYCProvider)
+	}
 
-	server, err := api.NewServer(config, store, cache, userManager)
+	server, err := api.NewServer(config, store, cache, userManager, paymentProvider, mailer, documentStorage, kycProvider)
 	if err != nil {
 		log.Fatal("cannot create server:", err)
 	}
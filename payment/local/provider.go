@@ -0,0 +1,113 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// ErrRejected is wrapped by the errors of the operations the provider has definitely refused.
+// Any other error leaves the outcome of the operation unknown.
+var ErrRejected = errors.New("payment rejected")
+
+// Provider moves money between the users' payment methods and the platform.
+type Provider interface {
+	// Charge collects an amount in minor units from the user and returns the provider reference.
+	Charge(ctx context.Context, userID uuid.UUID, amount int64, currency string) (string, error)
+	// Payout sends an amount in minor units to the user and returns the provider reference.
+	// The key identifies the payout, a payout sent again with the same key is only paid once.
+	Payout(ctx context.Context, key string, userID uuid.UUID, amount int64, currency string) (string, error)
+	// Refund gives a charge back in full and returns the provider reference of the refund.
+	Refund(ctx context.Context, reference string) (string, error)
+}
+
+// Operation is a money movement recorded by the LocalProvider.
+type Operation struct {
+	Reference string
+	Key       string
+	Kind      string
+	UserID    uuid.UUID
+	Amount    int64
+	Currency  string
+}
+
+// LocalProvider is an in-process Provider for development and tests.
+// Every charge and payout succeeds and is kept in memory.
+type LocalProvider struct {
+	mu         sync.Mutex
+	Operations []Operation
+}
+
+// NewLocalProvider creates a new LocalProvider
+func NewLocalProvider() *LocalProvider {
+	return &LocalProvider{}
+}
+
+// Charge records a successful charge
+func (provider *LocalProvider) Charge(ctx context.Context, userID uuid.UUID, amount int64, currency string) (string, error) {
+	return provider.record("charge", "", userID, amount, currency)
+}
+
+// Payout records a successful payout, once per key
+func (provider *LocalProvider) Payout(ctx context.Context, key string, userID uuid.UUID, amount int64, currency string) (string, error) {
+	provider.mu.Lock()
+	for _, operation := range provider.Operations {
+		if operation.Key == key && operation.Kind == "payout" {
+			provider.mu.Unlock()
+			return operation.Reference, nil
+		}
+	}
+	provider.mu.Unlock()
+
+	return provider.record("payout", key, userID, amount, currency)
+}
+
+// Refund records a successful refund of a charge
+func (provider *LocalProvider) Refund(ctx context.Context, reference string) (string, error) {
+	provider.mu.Lock()
+	var charge Operation
+	found := false
+	for _, operation := range provider.Operations {
+		if operation.Reference == reference && operation.Kind == "charge" {
+			charge, found = operation, true
+			break
+		}
+	}
+	provider.mu.Unlock()
+
+	if !found {
+		return "", fmt.Errorf("unknown charge %s", reference)
+	}
+	return provider.record("refund", "", charge.UserID, charge.Amount, charge.Currency)
+}
+
+func (provider *LocalProvider) record(kind string, key string, userID uuid.UUID, amount int64, currency string) (string, error) {
+	if amount <= 0 {
+		return "", fmt.Errorf("invalid %s amount %d: %w", kind, amount, ErrRejected)
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return "", err
+	}
+
+	op := Operation{
+		Reference: fmt.Sprintf("local_%s", id.String()),
+		Key:       key,
+		Kind:      kind,
+		UserID:    userID,
+		Amount:    amount,
+		Currency:  currency,
+	}
+
+	provider.mu.Lock()
+	provider.Operations = append(provider.Operations, op)
+	provider.mu.Unlock()
+
+	log.Printf("local payment %s %s: %d %s for user %s", op.Kind, op.Reference, amount, currency, userID)
+	return op.Reference, nil
+}
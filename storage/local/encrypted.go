@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// ErrCorruptedBlob is returned when a blob cannot be decrypted with the key of the storage
+var ErrCorruptedBlob = errors.New("blob cannot be decrypted")
+
+// EncryptedStorage encrypts the blobs with AES-256-GCM before handing them to another Storage,
+// so that they are encrypted at rest whatever the backend. Each blob is sealed as a whole with
+// a random nonce stored in front of it, which suits documents of a few megabytes.
+type EncryptedStorage struct {
+	storage Storage
+	aead    cipher.AEAD
+}
+
+// NewEncryptedStorage creates a new EncryptedStorage over storage with a 32 bytes key.
+func NewEncryptedStorage(storage Storage, key []byte) (*EncryptedStorage, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("invalid key size: must be exactly 32 bytes")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedStorage{storage: storage, aead: aead}, nil
+}
+
+// Put encrypts the content and stores it. The key is authenticated along with the content,
+// so that a blob moved under another key cannot be decrypted.
+func (storage *EncryptedStorage) Put(ctx context.Context, key string, r io.Reader) error {
+	plaintext, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, storage.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	sealed := storage.aead.Seal(nonce, nonce, plaintext, []byte(key))
+	return storage.storage.Put(ctx, key, bytes.NewReader(sealed))
+}
+
+// Get reads and decrypts the blob.
+func (storage *EncryptedStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	blob, err := storage.storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer blob.Close()
+
+	sealed, err := ioutil.ReadAll(blob)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := storage.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, ErrCorruptedBlob
+	}
+
+	plaintext, err := storage.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(key))
+	if err != nil {
+		return nil, ErrCorruptedBlob
+	}
+	return ioutil.NopCloser(bytes.NewReader(plaintext)), nil
+}
+
+// Delete removes the blob.
+func (storage *EncryptedStorage) Delete(ctx context.Context, key string) error {
+	return storage.storage.Delete(ctx, key)
+}
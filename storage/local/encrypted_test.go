@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedStorage(t *testing.T) {
+	local, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	storage, err := NewEncryptedStorage(local, []byte(util.RandomString(32)))
+	require.NoError(t, err)
+
+	content := []byte("%PDF-1.4 identity card of John Doe")
+	err = storage.Put(context.Background(), "users/42/id_card", bytes.NewReader(content))
+	require.NoError(t, err)
+
+	// the file on disk does not contain the content
+	raw, err := ioutil.ReadFile(filepath.Join(local.Dir, "users", "42", "id_card"))
+	require.NoError(t, err)
+	require.NotContains(t, string(raw), "John Doe")
+
+	blob, err := storage.Get(context.Background(), "users/42/id_card")
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(blob)
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+
+	// a blob moved under another key cannot be decrypted
+	err = os.Rename(filepath.Join(local.Dir, "users", "42", "id_card"), filepath.Join(local.Dir, "users", "42", "passport"))
+	require.NoError(t, err)
+	_, err = storage.Get(context.Background(), "users/42/passport")
+	require.ErrorIs(t, err, ErrCorruptedBlob)
+
+	err = storage.Delete(context.Background(), "users/42/passport")
+	require.NoError(t, err)
+	_, err = storage.Get(context.Background(), "users/42/passport")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestEncryptedStorageWrongKey(t *testing.T) {
+	local, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	storage1, err := NewEncryptedStorage(local, []byte(util.RandomString(32)))
+	require.NoError(t, err)
+	storage2, err := NewEncryptedStorage(local, []byte(util.RandomString(32)))
+	require.NoError(t, err)
+
+	err = storage1.Put(context.Background(), "blob", bytes.NewReader([]byte("secret")))
+	require.NoError(t, err)
+
+	_, err = storage2.Get(context.Background(), "blob")
+	require.ErrorIs(t, err, ErrCorruptedBlob)
+}
+
+func TestNewEncryptedStorageInvalidKey(t *testing.T) {
+	_, err := NewEncryptedStorage(nil, []byte("short"))
+	require.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// ErrNotFound is returned when no blob is stored under the key
+var ErrNotFound = errors.New("blob not found")
+
+// ErrInvalidKey is returned when a key cannot be used to store a blob
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// Storage stores blobs such as the identity documents of the users under a key.
+type Storage interface {
+	// Put stores the content read from r under the key, replacing any previous blob.
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Get opens the blob stored under the key, or returns ErrNotFound.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob stored under the key, it does nothing when there is none.
+	Delete(ctx context.Context, key string) error
+}
+
+// keys are made of slash separated segments, none of them can be "." or ".."
+var validKey = regexp.MustCompile(`^[a-zA-Z0-9_-][a-zA-Z0-9._-]*(/[a-zA-Z0-9_-][a-zA-Z0-9._-]*)*$`)
+
+// LocalStorage is a Storage keeping the blobs in files under Dir, for development and single
+// node deployments. An S3 compatible Storage can replace it without changes to the callers.
+type LocalStorage struct {
+	Dir string
+}
+
+// NewLocalStorage creates a new LocalStorage writing to dir.
+func NewLocalStorage(dir string) (*LocalStorage, error) {
+	if dir == "" {
+		return nil, errors.New("storage directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("cannot create storage directory: %w", err)
+	}
+	return &LocalStorage{Dir: dir}, nil
+}
+
+func (storage *LocalStorage) path(key string) (string, error) {
+	if !validKey.MatchString(key) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return filepath.Join(storage.Dir, filepath.FromSlash(key)), nil
+}
+
+// Put writes the blob to a temporary file renamed once complete, so that a failed write never
+// leaves a partial blob behind.
+func (storage *LocalStorage) Put(ctx context.Context, key string, r io.Reader) error {
+	path, err := storage.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get opens the file of the blob.
+func (storage *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := storage.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+// Delete removes the file of the blob.
+func (storage *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := storage.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage(t *testing.T) {
+	storage, err := NewLocalStorage(filepath.Join(t.TempDir(), "blobs"))
+	require.NoError(t, err)
+
+	key := "users/42/passport"
+	err = storage.Put(context.Background(), key, bytes.NewReader([]byte("first")))
+	require.NoError(t, err)
+	err = storage.Put(context.Background(), key, bytes.NewReader([]byte("second")))
+	require.NoError(t, err)
+
+	blob, err := storage.Get(context.Background(), key)
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(blob)
+	require.NoError(t, err)
+	require.NoError(t, blob.Close())
+	require.Equal(t, "second", string(data))
+
+	files, err := ioutil.ReadDir(filepath.Join(storage.Dir, "users", "42"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	err = storage.Delete(context.Background(), key)
+	require.NoError(t, err)
+	err = storage.Delete(context.Background(), key)
+	require.NoError(t, err)
+
+	_, err = storage.Get(context.Background(), key)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLocalStorageInvalidKey(t *testing.T) {
+	storage, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "../secret", "users/../../secret", "/etc/passwd", "users//42", ".hidden"} {
+		err = storage.Put(context.Background(), key, bytes.NewReader(nil))
+		require.ErrorIs(t, err, ErrInvalidKey, key)
+	}
+}
+
+func TestNewLocalStorageWithoutDir(t *testing.T) {
+	_, err := NewLocalStorage("")
+	require.Error(t, err)
+}
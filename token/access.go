@@ -0,0 +1,26 @@
+package token
+
+// Access holds the roles of a user and the permissions they grant, as they were when the token was made
+type Access struct {
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// HasRole tells if the access includes the role
+func (access Access) HasRole(role string) bool {
+	return contains(access.Roles, role)
+}
+
+// HasPermission tells if the access includes the permission
+func (access Access) HasPermission(permission string) bool {
+	return contains(access.Permissions, permission)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
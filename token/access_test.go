@@ -0,0 +1,17 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccess(t *testing.T) {
+	require.True(t, testAccess.HasRole("support"))
+	require.False(t, testAccess.HasRole("admin"))
+	require.True(t, testAccess.HasPermission("users:read"))
+	require.False(t, testAccess.HasPermission("roles:write"))
+
+	require.False(t, Access{}.HasRole("investor"))
+	require.False(t, Access{}.HasPermission(""))
+}
@@ -0,0 +1,231 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+// AlgorithmHS256 is the signing algorithm of the JWTs made with a symmetric key
+const AlgorithmHS256 = "HS256"
+
+const minSecretKeySize = 32
+
+// jwtLeeway is the clock skew tolerated between the servers when the times of a JWT are checked
+const jwtLeeway = 5 * time.Second
+
+// JWTMaker is a JSON Web Token maker. The tokens are signed either with HS256 and a symmetric key,
+// or with EdDSA or RS256 and rotating keys whose ID is set in the `kid` header.
+type JWTMaker struct {
+	secretKey []byte
+	keys      keySet
+	issuer    string
+	audience  string
+}
+
+// NewJWTMaker creates a new JWTMaker signing the tokens with HS256. The tokens it makes are issued
+// by issuer for audience and only the tokens with the same issuer and audience are accepted.
+func NewJWTMaker(secretKey string, issuer string, audience string) (Maker, error) {
+	if len(secretKey) < minSecretKeySize {
+		return nil, fmt.Errorf("invalid key size: must be at least %d characters", minSecretKeySize)
+	}
+
+	maker := &JWTMaker{
+		secretKey: []byte(secretKey),
+		issuer:    issuer,
+		audience:  audience,
+	}
+
+	return maker, nil
+}
+
+// NewJWTPublicMaker creates a new JWTMaker signing the tokens with the given EdDSA or RS256 keys.
+// The tokens it makes are issued by issuer for audience and only the tokens with the same issuer
+// and audience are accepted.
+func NewJWTPublicMaker(keys []Key, issuer string, audience string) (Maker, error) {
+	set, err := newKeySet(keys, AlgorithmEdDSA, AlgorithmRS256)
+	if err != nil {
+		return nil, err
+	}
+
+	maker := &JWTMaker{
+		keys:     set,
+		issuer:   issuer,
+		audience: audience,
+	}
+
+	return maker, nil
+}
+
+// jwtClaims are the claims of the payload, registered ones where they exist
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	SessionID   string   `json:"sid,omitempty"`
+	Type        Type     `json:"token_type"`
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// Valid checks the times of the claims with a leeway of a few seconds. The expiry and issue times
+// are required, the start time is checked when it is set.
+func (claims jwtClaims) Valid() error {
+	now := time.Now()
+	if !claims.VerifyExpiresAt(now.Add(-jwtLeeway), true) {
+		return ErrExpiredToken
+	}
+	if !claims.VerifyIssuedAt(now.Add(jwtLeeway), true) || !claims.VerifyNotBefore(now.Add(jwtLeeway), false) {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// CreateToken creates a new token of a type for a specific userID and duration
+func (maker *JWTMaker) CreateToken(tokenType Type, userID uuid.UUID, access Access, duration time.Duration) (*Payload, string, error) {
+	payload, err := NewPayload(tokenType, userID, access, duration)
+	if err != nil {
+		return &Payload{}, "", err
+	}
+
+	ss, err := maker.signPayload(payload)
+	if err != nil {
+		return &Payload{}, "", err
+	}
+	return payload, ss, nil
+}
+
+// signPayload sets the issuer and the audience of the payload and signs its claims. The times of a JWT
+// are whole seconds, those of the payload are rounded down so that they match the signed ones.
+func (maker *JWTMaker) signPayload(payload *Payload) (string, error) {
+	payload.Issuer = maker.issuer
+	payload.Audience = maker.audience
+	payload.IssuedAt = payload.IssuedAt.Truncate(time.Second)
+	payload.ExpiredAt = payload.ExpiredAt.Truncate(time.Second)
+
+	claims := jwtClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        payload.ID.String(),
+			Subject:   payload.UserID.String(),
+			Issuer:    payload.Issuer,
+			Audience:  jwt.ClaimStrings{payload.Audience},
+			IssuedAt:  jwt.NewNumericDate(payload.IssuedAt),
+			NotBefore: jwt.NewNumericDate(payload.IssuedAt),
+			ExpiresAt: jwt.NewNumericDate(payload.ExpiredAt),
+		},
+		SessionID:   payload.SessionID,
+		Type:        payload.Type,
+		Roles:       payload.Roles,
+		Permissions: payload.Permissions,
+	}
+
+	if maker.secretKey != nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(maker.secretKey)
+	}
+
+	key, err := maker.keys.signingKey(payload.IssuedAt)
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.algorithm), claims)
+	token.Header["kid"] = key.id
+	return token.SignedString(key.private)
+}
+
+// CreateTokenPair creates a new pair of tokens of a session for a specific userID and duration
+func (maker *JWTMaker) CreateTokenPair(userID uuid.UUID, sessionID string, access Access, accessDuration time.Duration, refreshDuration time.Duration) (Payload, string, Payload, string, error) {
+	at, rt, err := newPayloadPair(userID, sessionID, access, accessDuration, refreshDuration)
+	if err != nil {
+		return Payload{}, "", Payload{}, "", err
+	}
+
+	atST, err := maker.signPayload(at)
+	if err != nil {
+		return Payload{}, "", Payload{}, "", err
+	}
+
+	rtST, err := maker.signPayload(rt)
+	if err != nil {
+		return Payload{}, "", Payload{}, "", err
+	}
+	return *at, atST, *rt, rtST, nil
+}
+
+// verificationKey returns the key verifying the token. The algorithm expected for the key is enforced,
+// whatever the algorithm of the header, so that a token cannot pick how it is verified.
+func (maker *JWTMaker) verificationKey(token *jwt.Token) (interface{}, error) {
+	if maker.secretKey != nil {
+		if token.Method.Alg() != AlgorithmHS256 {
+			return nil, ErrInvalidToken
+		}
+		return maker.secretKey, nil
+	}
+
+	keyID, _ := token.Header["kid"].(string)
+	key, ok := maker.keys.verificationKey(keyID, time.Now())
+	if !ok || token.Method.Alg() != key.algorithm {
+		return nil, ErrInvalidToken
+	}
+	return key.public, nil
+}
+
+// VerifyToken checks if the token is valid or not, whatever its type. The issue, start and
+// expiry times are checked with a leeway of a few seconds.
+func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
+	var claims jwtClaims
+	_, err := jwt.ParseWithClaims(
+		token,
+		&claims,
+		maker.verificationKey,
+		jwt.WithValidMethods([]string{AlgorithmHS256, AlgorithmEdDSA, AlgorithmRS256}),
+	)
+	if err != nil {
+		if errors.Is(err, ErrExpiredToken) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	if claims.Issuer != maker.issuer || len(claims.Audience) != 1 || claims.Audience[0] != maker.audience {
+		return nil, ErrInvalidToken
+	}
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	payload := &Payload{
+		ID:        tokenID,
+		Type:      claims.Type,
+		Issuer:    claims.Issuer,
+		Audience:  claims.Audience[0],
+		UserID:    userID,
+		IssuedAt:  claims.IssuedAt.UTC(),
+		ExpiredAt: claims.ExpiresAt.UTC(),
+		SessionID: claims.SessionID,
+		Access: Access{
+			Roles:       claims.Roles,
+			Permissions: claims.Permissions,
+		},
+	}
+	return payload, nil
+}
+
+// VerifyTokenFor checks if the token is valid and has the expected type
+func (maker *JWTMaker) VerifyTokenFor(token string, tokenType Type) (*Payload, error) {
+	payload, err := maker.VerifyToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.Type != tokenType {
+		return nil, ErrInvalidTokenType
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,161 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+var jwtEncoding = base64.RawURLEncoding
+
+// jwtHeader is the header of a forged token
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+// forgeJWT builds a token with the header and the claims of ss, signed with HS256 and key, or unsigned
+func forgeJWT(t *testing.T, ss string, header jwtHeader, key []byte) string {
+	parts := strings.Split(ss, ".")
+	require.Len(t, parts, 3)
+
+	headerJSON, err := json.Marshal(header)
+	require.NoError(t, err)
+	signingInput := jwtEncoding.EncodeToString(headerJSON) + "." + parts[1]
+	if key == nil {
+		return signingInput + "."
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + jwtEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestJWTMakerUnsignedToken(t *testing.T) {
+	maker, err := NewJWTMaker(util.RandomString(32), issuer, audience)
+	require.NoError(t, err)
+
+	userID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	_, ss, err := maker.CreateToken(TypeAccess, userID, Access{}, time.Minute)
+	require.NoError(t, err)
+
+	forged := forgeJWT(t, ss, jwtHeader{Algorithm: "none", Type: "JWT"}, nil)
+	payload, err := maker.VerifyToken(forged)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestJWTPublicMakerAlgorithmConfusion(t *testing.T) {
+	key, err := GenerateRSAKey(util.RandomString(8), 2048, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	maker, err := NewJWTPublicMaker([]Key{key}, issuer, audience)
+	require.NoError(t, err)
+
+	userID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	_, ss, err := maker.CreateToken(TypeAccess, userID, testAccess, time.Minute)
+	require.NoError(t, err)
+
+	// the public key is known to anyone, it must not be usable as an HMAC secret
+	forged := forgeJWT(t, ss, jwtHeader{Algorithm: AlgorithmHS256, Type: "JWT", KeyID: key.ID}, []byte(key.PublicKey))
+	payload, err := maker.VerifyToken(forged)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	forged = forgeJWT(t, ss, jwtHeader{Algorithm: "none", Type: "JWT", KeyID: key.ID}, nil)
+	payload, err = maker.VerifyToken(forged)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestJWTMakerInvalidKey(t *testing.T) {
+	maker, err := NewJWTMaker(util.RandomString(16), issuer, audience)
+	require.Error(t, err)
+	require.Nil(t, maker)
+
+	// RS256 keys are not PASETO v2 keys
+	key, err := GenerateRSAKey(util.RandomString(8), 2048, time.Now())
+	require.NoError(t, err)
+	maker, err = NewPasetoPublicMaker([]Key{key}, issuer, audience)
+	require.Error(t, err)
+	require.Nil(t, maker)
+}
+
+func TestJWTMakerNumericDates(t *testing.T) {
+	maker, err := NewJWTMaker(util.RandomString(32), issuer, audience)
+	require.NoError(t, err)
+
+	userID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	payload, ss, err := maker.CreateToken(TypeAccess, userID, Access{}, time.Minute)
+	require.NoError(t, err)
+
+	claimsJSON, err := jwtEncoding.DecodeString(strings.Split(ss, ".")[1])
+	require.NoError(t, err)
+	var claims map[string]interface{}
+	require.NoError(t, json.Unmarshal(claimsJSON, &claims))
+
+	// NumericDates are integers, the start of validity is the issue time
+	require.Equal(t, float64(payload.IssuedAt.Unix()), claims["iat"])
+	require.Equal(t, float64(payload.IssuedAt.Unix()), claims["nbf"])
+	require.Equal(t, float64(payload.ExpiredAt.Unix()), claims["exp"])
+	require.Zero(t, payload.IssuedAt.Nanosecond())
+}
+
+func TestJWTMakerLeeway(t *testing.T) {
+	secretKey := util.RandomString(32)
+	maker, err := NewJWTMaker(secretKey, issuer, audience)
+	require.NoError(t, err)
+
+	userID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	sign := func(issuedAt time.Time, notBefore time.Time, expiresAt time.Time) string {
+		claims := jwtClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:        uuid.NewString(),
+				Subject:   userID.String(),
+				Issuer:    issuer,
+				Audience:  jwt.ClaimStrings{audience},
+				IssuedAt:  jwt.NewNumericDate(issuedAt),
+				NotBefore: jwt.NewNumericDate(notBefore),
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+			},
+			Type: TypeAccess,
+		}
+		ss, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secretKey))
+		require.NoError(t, err)
+		return ss
+	}
+
+	now := time.Now()
+	skew := jwtLeeway / 2
+
+	// a server whose clock is slightly ahead or behind is tolerated
+	_, err = maker.VerifyToken(sign(now.Add(skew), now.Add(skew), now.Add(time.Minute)))
+	require.NoError(t, err)
+	_, err = maker.VerifyToken(sign(now.Add(-time.Minute), now.Add(-time.Minute), now.Add(-skew)))
+	require.NoError(t, err)
+
+	// beyond the leeway the token is not valid yet or expired
+	_, err = maker.VerifyToken(sign(now, now.Add(time.Minute), now.Add(2*time.Minute)))
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	_, err = maker.VerifyToken(sign(now.Add(time.Minute), now, now.Add(2*time.Minute)))
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	_, err = maker.VerifyToken(sign(now.Add(-time.Minute), now.Add(-time.Minute), now.Add(-2*jwtLeeway)))
+	require.EqualError(t, err, ErrExpiredToken.Error())
+}
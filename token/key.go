@@ -0,0 +1,14 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+)
+
+// DeriveKey derives a symmetric key dedicated to a purpose from the main symmetric key.
+// Tokens made with the derived key cannot be verified with the main key and inversely.
+func DeriveKey(symmetricKey string, purpose string) string {
+	mac := hmac.New(sha256.New, []byte(symmetricKey))
+	mac.Write([]byte(purpose))
+	return string(mac.Sum(nil))
+}
@@ -0,0 +1,248 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrNoSigningKey is returned when no key can sign tokens at the current time
+var ErrNoSigningKey = errors.New("no signing key is active")
+
+// Signing algorithms of the keys
+const (
+	AlgorithmEdDSA = "EdDSA"
+	AlgorithmRS256 = "RS256"
+)
+
+// Key is a signing key as found in the configuration. A key with a private key signs the tokens from
+// NotBefore on, until a key with a later NotBefore takes over. Tokens signed with a key are accepted
+// until its NotAfter, if any. A key without private key only verifies tokens.
+// Ed25519 keys are hex encoded, the private key being the seed. RSA keys are PEM encoded.
+type Key struct {
+	ID         string    `json:"kid"`
+	Algorithm  string    `json:"alg,omitempty"`
+	PrivateKey string    `json:"private_key,omitempty"`
+	PublicKey  string    `json:"public_key,omitempty"`
+	NotBefore  time.Time `json:"not_before"`
+	NotAfter   time.Time `json:"not_after,omitempty"`
+}
+
+// GenerateKey creates a new Ed25519 key which starts signing tokens at notBefore.
+func GenerateKey(id string, notBefore time.Time) (Key, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return Key{}, err
+	}
+
+	key := Key{
+		ID:         id,
+		Algorithm:  AlgorithmEdDSA,
+		PrivateKey: hex.EncodeToString(private.Seed()),
+		PublicKey:  hex.EncodeToString(public),
+		NotBefore:  notBefore,
+	}
+	return key, nil
+}
+
+// GenerateRSAKey creates a new RSA key of bits which starts signing tokens at notBefore.
+func GenerateRSAKey(id string, bits int, notBefore time.Time) (Key, error) {
+	private, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return Key{}, err
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return Key{}, err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	if err != nil {
+		return Key{}, err
+	}
+
+	key := Key{
+		ID:         id,
+		Algorithm:  AlgorithmRS256,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})),
+		PublicKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})),
+		NotBefore:  notBefore,
+	}
+	return key, nil
+}
+
+type signingKey struct {
+	id        string
+	algorithm string
+	private   crypto.Signer
+	public    crypto.PublicKey
+	notBefore time.Time
+	notAfter  time.Time
+}
+
+func (key signingKey) expired(now time.Time) bool {
+	return !key.notAfter.IsZero() && !now.Before(key.notAfter)
+}
+
+// keySet holds the keys of a maker ordered by the time they start signing tokens
+type keySet []signingKey
+
+// newKeySet parses the keys, which must use one of the algorithms. A key without algorithm is an Ed25519 key.
+func newKeySet(keys []Key, algorithms ...string) (keySet, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one key is required")
+	}
+
+	set := make(keySet, 0, len(keys))
+	ids := map[string]bool{}
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, errors.New("key ID is required")
+		}
+		if ids[key.ID] {
+			return nil, fmt.Errorf("duplicate key ID %q", key.ID)
+		}
+		ids[key.ID] = true
+
+		k, err := parseKey(key, algorithms)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", key.ID, err)
+		}
+		set = append(set, k)
+	}
+
+	sort.SliceStable(set, func(i, j int) bool {
+		return set[i].notBefore.Before(set[j].notBefore)
+	})
+	return set, nil
+}
+
+func parseKey(key Key, algorithms []string) (signingKey, error) {
+	k := signingKey{
+		id:        key.ID,
+		algorithm: key.Algorithm,
+		notBefore: key.NotBefore,
+		notAfter:  key.NotAfter,
+	}
+	if k.algorithm == "" {
+		k.algorithm = AlgorithmEdDSA
+	}
+
+	supported := false
+	for _, algorithm := range algorithms {
+		supported = supported || algorithm == k.algorithm
+	}
+	if !supported {
+		return signingKey{}, fmt.Errorf("unsupported algorithm %q", k.algorithm)
+	}
+
+	var err error
+	switch k.algorithm {
+	case AlgorithmEdDSA:
+		err = parseEd25519Key(&k, key)
+	case AlgorithmRS256:
+		err = parseRSAKey(&k, key)
+	}
+	if err != nil {
+		return signingKey{}, err
+	}
+
+	if k.public == nil {
+		return signingKey{}, errors.New("private or public key is required")
+	}
+	return k, nil
+}
+
+func parseEd25519Key(k *signingKey, key Key) error {
+	if key.PrivateKey != "" {
+		seed, err := hex.DecodeString(key.PrivateKey)
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return fmt.Errorf("private key must be a hex encoded seed of %d bytes", ed25519.SeedSize)
+		}
+		private := ed25519.NewKeyFromSeed(seed)
+		k.private = private
+		k.public = private.Public()
+	}
+
+	if key.PublicKey != "" {
+		public, err := hex.DecodeString(key.PublicKey)
+		if err != nil || len(public) != ed25519.PublicKeySize {
+			return fmt.Errorf("public key must be hex encoded on %d bytes", ed25519.PublicKeySize)
+		}
+		if k.public != nil && !k.public.(ed25519.PublicKey).Equal(ed25519.PublicKey(public)) {
+			return errors.New("public key does not match the private key")
+		}
+		k.public = ed25519.PublicKey(public)
+	}
+	return nil
+}
+
+func parseRSAKey(k *signingKey, key Key) error {
+	if key.PrivateKey != "" {
+		block, _ := pem.Decode([]byte(key.PrivateKey))
+		if block == nil {
+			return errors.New("private key must be PEM encoded")
+		}
+		var private *rsa.PrivateKey
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err == nil {
+			private, _ = parsed.(*rsa.PrivateKey)
+		} else {
+			private, _ = x509.ParsePKCS1PrivateKey(block.Bytes)
+		}
+		if private == nil {
+			return errors.New("private key must be an RSA key in PKCS #8 or PKCS #1 form")
+		}
+		k.private = private
+		k.public = &private.PublicKey
+	}
+
+	if key.PublicKey != "" {
+		block, _ := pem.Decode([]byte(key.PublicKey))
+		if block == nil {
+			return errors.New("public key must be PEM encoded")
+		}
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return err
+		}
+		public, ok := parsed.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("public key must be an RSA key")
+		}
+		if k.public != nil && !k.public.(*rsa.PublicKey).Equal(public) {
+			return errors.New("public key does not match the private key")
+		}
+		k.public = public
+	}
+	return nil
+}
+
+// signingKey returns the key with a private key which has signed the tokens the most recently
+func (set keySet) signingKey(now time.Time) (signingKey, error) {
+	for i := len(set) - 1; i >= 0; i-- {
+		key := set[i]
+		if key.private != nil && !key.notBefore.After(now) && !key.expired(now) {
+			return key, nil
+		}
+	}
+	return signingKey{}, ErrNoSigningKey
+}
+
+// verificationKey returns the key with the ID unless it has been retired
+func (set keySet) verificationKey(id string, now time.Time) (signingKey, bool) {
+	for _, key := range set {
+		if key.id == id {
+			return key, !key.expired(now)
+		}
+	}
+	return signingKey{}, false
+}
@@ -8,10 +8,12 @@ import (
 
 // Maker is an interface for managing tokens
 type Maker interface {
-	// CreateToken creates a new token for a specific userID and duration
-	CreateToken(userID uuid.UUID, isAdmin bool, duration time.Duration) (*Payload, string, error)
-	// VerifyToken checks if the token is valid or not
+	// CreateToken creates a new token of a type for a specific userID and duration
+	CreateToken(tokenType Type, userID uuid.UUID, access Access, duration time.Duration) (*Payload, string, error)
+	// VerifyToken checks if the token is valid or not, whatever its type
 	VerifyToken(token string) (*Payload, error)
-	// CreateTokenPair creates fresh access and refresh tokens for the current user
-	CreateTokenPair(userID uuid.UUID, isAdmin bool, accessDuration time.Duration, refreshDuration time.Duration) (Payload, string, Payload, string, error)
+	// VerifyTokenFor checks if the token is valid and has the expected type
+	VerifyTokenFor(token string, tokenType Type) (*Payload, error)
+	// CreateTokenPair creates fresh access and refresh tokens of a session for the current user
+	CreateTokenPair(userID uuid.UUID, sessionID string, access Access, accessDuration time.Duration, refreshDuration time.Duration) (Payload, string, Payload, string, error)
 }
@@ -0,0 +1,215 @@
+package token
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// makerFactory creates makers sharing the same keys, for the issuer and audience given
+type makerFactory func(issuer string, audience string) (Maker, error)
+
+func testMakerFactories(t *testing.T) map[string]makerFactory {
+	symmetricKey := util.RandomString(32)
+	edKey, err := GenerateKey(util.RandomString(8), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	rsaKey, err := GenerateRSAKey(util.RandomString(8), 2048, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+
+	return map[string]makerFactory{
+		"PasetoLocal": func(issuer string, audience string) (Maker, error) {
+			return NewPasetoMaker(symmetricKey, issuer, audience)
+		},
+		"PasetoPublic": func(issuer string, audience string) (Maker, error) {
+			return NewPasetoPublicMaker([]Key{edKey}, issuer, audience)
+		},
+		"JWTHS256": func(issuer string, audience string) (Maker, error) {
+			return NewJWTMaker(symmetricKey, issuer, audience)
+		},
+		"JWTEdDSA": func(issuer string, audience string) (Maker, error) {
+			return NewJWTPublicMaker([]Key{edKey}, issuer, audience)
+		},
+		"JWTRS256": func(issuer string, audience string) (Maker, error) {
+			return NewJWTPublicMaker([]Key{rsaKey}, issuer, audience)
+		},
+	}
+}
+
+func TestMakers(t *testing.T) {
+	factories := testMakerFactories(t)
+
+	for name, newMaker := range factories {
+		newMaker := newMaker
+		t.Run(name, func(t *testing.T) {
+			maker, err := newMaker(issuer, audience)
+			require.NoError(t, err)
+
+			t.Run("Token", func(t *testing.T) {
+				testMakerToken(t, maker)
+			})
+			t.Run("TokenPair", func(t *testing.T) {
+				testMakerTokenPair(t, maker)
+			})
+			t.Run("ExpiredToken", func(t *testing.T) {
+				testMakerExpiredToken(t, maker)
+			})
+			t.Run("TamperedToken", func(t *testing.T) {
+				testMakerTamperedToken(t, maker)
+			})
+			t.Run("IssuerAudience", func(t *testing.T) {
+				testMakerIssuerAudience(t, maker, newMaker)
+			})
+		})
+	}
+}
+
+func TestMakersRejectOtherMakers(t *testing.T) {
+	factories := testMakerFactories(t)
+
+	userID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	for name, newMaker := range factories {
+		maker, err := newMaker(issuer, audience)
+		require.NoError(t, err)
+
+		for otherName, newOtherMaker := range factories {
+			if otherName == name {
+				continue
+			}
+			otherMaker, err := newOtherMaker(issuer, audience)
+			require.NoError(t, err)
+
+			_, ss, err := otherMaker.CreateToken(TypeAccess, userID, Access{}, time.Minute)
+			require.NoError(t, err)
+
+			payload, err := maker.VerifyToken(ss)
+			require.EqualError(t, err, ErrInvalidToken.Error(), "%s accepted a token of %s", name, otherName)
+			require.Nil(t, payload)
+		}
+	}
+}
+
+func testMakerToken(t *testing.T, maker Maker) {
+	userID, err := uuid.NewRandom()
+	require.NoError(t, err)
+	duration := time.Minute
+
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(duration)
+
+	token, ss, err := maker.CreateToken(TypeEmailVerification, userID, testAccess, duration)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, ss)
+
+	payload, err := maker.VerifyTokenFor(ss, TypeEmailVerification)
+	require.NoError(t, err)
+
+	require.Equal(t, token.ID, payload.ID)
+	require.Equal(t, TypeEmailVerification, payload.Type)
+	require.Equal(t, issuer, payload.Issuer)
+	require.Equal(t, audience, payload.Audience)
+	require.Equal(t, userID, payload.UserID)
+	require.Equal(t, testAccess, payload.Access)
+	// the times are compared with the revocation cutoffs, they must not lose precision
+	require.True(t, token.IssuedAt.Equal(payload.IssuedAt))
+	require.True(t, token.ExpiredAt.Equal(payload.ExpiredAt))
+	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+
+	payload, err = maker.VerifyTokenFor(ss, TypePasswordReset)
+	require.EqualError(t, err, ErrInvalidTokenType.Error())
+	require.Nil(t, payload)
+}
+
+func testMakerTokenPair(t *testing.T, maker Maker) {
+	userID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	sessionID := uuid.New().String()
+	at, atST, rt, rtST, err := maker.CreateTokenPair(userID, sessionID, Access{}, time.Minute, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, TypeAccess, at.Type)
+	require.Equal(t, TypeRefresh, rt.Type)
+	require.NotEqual(t, at.ID, rt.ID)
+
+	payload, err := maker.VerifyTokenFor(atST, TypeAccess)
+	require.NoError(t, err)
+	require.Equal(t, at.ID, payload.ID)
+	require.Equal(t, sessionID, payload.SessionID)
+
+	payload, err = maker.VerifyTokenFor(rtST, TypeRefresh)
+	require.NoError(t, err)
+	require.Equal(t, rt.ID, payload.ID)
+	require.Equal(t, sessionID, payload.SessionID)
+
+	_, err = maker.VerifyTokenFor(atST, TypeRefresh)
+	require.EqualError(t, err, ErrInvalidTokenType.Error())
+	_, err = maker.VerifyTokenFor(rtST, TypeAccess)
+	require.EqualError(t, err, ErrInvalidTokenType.Error())
+}
+
+func testMakerExpiredToken(t *testing.T, maker Maker) {
+	userID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	token, ss, err := maker.CreateToken(TypeAccess, userID, Access{}, -time.Minute)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, ss)
+
+	payload, err := maker.VerifyToken(ss)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func testMakerTamperedToken(t *testing.T, maker Maker) {
+	userID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	_, ss, err := maker.CreateToken(TypeAccess, userID, Access{}, time.Minute)
+	require.NoError(t, err)
+
+	// flip a character in the middle of the token
+	i := len(ss) / 2
+	tampered := []byte(ss)
+	if tampered[i] == 'A' {
+		tampered[i] = 'B'
+	} else {
+		tampered[i] = 'A'
+	}
+
+	for _, token := range []string{string(tampered), ss[:len(ss)-4], strings.ToUpper(ss), "", "invalid", "a.b.c"} {
+		payload, err := maker.VerifyToken(token)
+		require.EqualError(t, err, ErrInvalidToken.Error())
+		require.Nil(t, payload)
+	}
+}
+
+func testMakerIssuerAudience(t *testing.T, maker Maker, newMaker makerFactory) {
+	userID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	otherIssuer, err := newMaker("other-issuer", audience)
+	require.NoError(t, err)
+	_, ss, err := otherIssuer.CreateToken(TypeAccess, userID, Access{}, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(ss)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	otherAudience, err := newMaker(issuer, "other-audience")
+	require.NoError(t, err)
+	_, ss, err = otherAudience.CreateToken(TypeAccess, userID, Access{}, time.Minute)
+	require.NoError(t, err)
+
+	payload, err = maker.VerifyToken(ss)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
@@ -13,10 +13,13 @@ import (
 type PasetoMaker struct {
 	paseto       *paseto.V2
 	symmetricKey []byte
+	issuer       string
+	audience     string
 }
 
-// NewPasetoMaker creates a new PasetoMaker
-func NewPasetoMaker(symmetricKey string) (Maker, error) {
+// NewPasetoMaker creates a new PasetoMaker. The tokens it makes are issued by issuer for audience
+// and only the tokens with the same issuer and audience are accepted.
+func NewPasetoMaker(symmetricKey string, issuer string, audience string) (Maker, error) {
 	if len(symmetricKey) != chacha20poly1305.KeySize {
 		return nil, fmt.Errorf("invalid key size: must be %d characters", chacha20poly1305.KeySize)
 	}
@@ -24,45 +27,52 @@ func NewPasetoMaker(symmetricKey string) (Maker, error) {
 	maker := &PasetoMaker{
 		paseto:       paseto.NewV2(),
 		symmetricKey: []byte(symmetricKey),
+		issuer:       issuer,
+		audience:     audience,
 	}
 
 	return maker, nil
 }
 
-// CreateToken creates a new token for a specific userID and duration
-func (maker *PasetoMaker) CreateToken(userID uuid.UUID, isAdmin bool, duration time.Duration) (*Payload, string, error) {
-	payload, err := NewPayload(userID, isAdmin, duration)
+// CreateToken creates a new token of a type for a specific userID and duration
+func (maker *PasetoMaker) CreateToken(tokenType Type, userID uuid.UUID, access Access, duration time.Duration) (*Payload, string, error) {
+	payload, err := NewPayload(tokenType, userID, access, duration)
 	if err != nil {
 		return &Payload{}, "", err
 	}
 
-	st, err := maker.paseto.Encrypt(maker.symmetricKey, payload, nil)
+	st, err := maker.signPayload(payload)
 	return payload, st, err
 }
 
-// CreateTokenPair creates a new pair of tokens for a specific userID and duration
-func (maker *PasetoMaker) CreateTokenPair(userID uuid.UUID, isAdmin bool, accessDuration time.Duration, refreshDuration time.Duration) (Payload, string, Payload, string, error) {
-	at, atST, err := maker.CreateToken(
-		userID,
-		isAdmin,
-		accessDuration,
-	)
+// signPayload sets the issuer and the audience of the payload and encrypts it
+func (maker *PasetoMaker) signPayload(payload *Payload) (string, error) {
+	payload.Issuer = maker.issuer
+	payload.Audience = maker.audience
+
+	return maker.paseto.Encrypt(maker.symmetricKey, payload, nil)
+}
+
+// CreateTokenPair creates a new pair of tokens of a session for a specific userID and duration
+func (maker *PasetoMaker) CreateTokenPair(userID uuid.UUID, sessionID string, access Access, accessDuration time.Duration, refreshDuration time.Duration) (Payload, string, Payload, string, error) {
+	at, rt, err := newPayloadPair(userID, sessionID, access, accessDuration, refreshDuration)
+	if err != nil {
+		return Payload{}, "", Payload{}, "", err
+	}
+
+	atST, err := maker.signPayload(at)
 	if err != nil {
 		return Payload{}, "", Payload{}, "", err
 	}
 
-	rt, rtST, err := maker.CreateToken(
-		userID,
-		isAdmin,
-		refreshDuration,
-	)
+	rtST, err := maker.signPayload(rt)
 	if err != nil {
 		return Payload{}, "", Payload{}, "", err
 	}
 	return *at, atST, *rt, rtST, nil
 }
 
-// VerifyToken checks if the token is valid or not
+// VerifyToken checks if the token is valid or not, whatever its type
 func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
 	payload := &Payload{}
 
@@ -72,6 +82,10 @@ func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
 		return nil, ErrInvalidToken
 	}
 
+	if payload.Issuer != maker.issuer || payload.Audience != maker.audience {
+		return nil, ErrInvalidToken
+	}
+
 	err = payload.Valid()
 	if err != nil {
 		return nil, err
@@ -79,3 +93,17 @@ func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
 
 	return payload, nil
 }
+
+// VerifyTokenFor checks if the token is valid and has the expected type
+func (maker *PasetoMaker) VerifyTokenFor(token string, tokenType Type) (*Payload, error) {
+	payload, err := maker.VerifyToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.Type != tokenType {
+		return nil, ErrInvalidTokenType
+	}
+
+	return payload, nil
+}
@@ -9,19 +9,28 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+const (
+	issuer   = "immoblock-backend"
+	audience = "immoblock"
+)
+
+var testAccess = Access{
+	Roles:       []string{"investor", "support"},
+	Permissions: []string{"users:read", "users:write"},
+}
+
 func TestPasetoMaker(t *testing.T) {
-	maker, err := NewPasetoMaker(util.RandomString(32))
+	maker, err := NewPasetoMaker(util.RandomString(32), issuer, audience)
 	require.NoError(t, err)
 
 	userID, err := uuid.NewRandom()
 	require.NoError(t, err)
-	isAdmin := false
 	duration := time.Minute
 
 	issuedAt := time.Now()
 	expiredAt := issuedAt.Add(duration)
 
-	token, ss, err := maker.CreateToken(userID, isAdmin, duration)
+	token, ss, err := maker.CreateToken(TypeAccess, userID, testAccess, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, ss)
@@ -31,19 +40,21 @@ func TestPasetoMaker(t *testing.T) {
 	require.NotEmpty(t, token)
 
 	require.NotZero(t, payload.ID)
+	require.Equal(t, TypeAccess, payload.Type)
+	require.Equal(t, issuer, payload.Issuer)
+	require.Equal(t, audience, payload.Audience)
 	require.Equal(t, userID, payload.UserID)
 	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
 	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
 }
 
 func TestExpiredPasetoToken(t *testing.T) {
-	maker, err := NewPasetoMaker(util.RandomString(32))
+	maker, err := NewPasetoMaker(util.RandomString(32), issuer, audience)
 	require.NoError(t, err)
 
 	uid, err := uuid.NewRandom()
 	require.NoError(t, err)
-	isAdmin := false
-	token, ss, err := maker.CreateToken(uid, isAdmin, -time.Minute)
+	token, ss, err := maker.CreateToken(TypeAccess, uid, testAccess, -time.Minute)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, ss)
@@ -53,3 +64,87 @@ func TestExpiredPasetoToken(t *testing.T) {
 	require.EqualError(t, err, ErrExpiredToken.Error())
 	require.Nil(t, payload)
 }
+
+func TestDerivedKeyPasetoMaker(t *testing.T) {
+	key := util.RandomString(32)
+	maker, err := NewPasetoMaker(key, issuer, audience)
+	require.NoError(t, err)
+
+	derivedMaker, err := NewPasetoMaker(DeriveKey(key, "email-verification"), issuer, audience)
+	require.NoError(t, err)
+
+	userID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	_, ss, err := derivedMaker.CreateToken(TypeEmailVerification, userID, Access{}, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := derivedMaker.VerifyToken(ss)
+	require.NoError(t, err)
+	require.Equal(t, userID, payload.UserID)
+
+	// a token made for another purpose is not accepted by the main maker
+	payload, err = maker.VerifyToken(ss)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestPasetoTokenType(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32), issuer, audience)
+	require.NoError(t, err)
+
+	userID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	sessionID := uuid.New().String()
+	at, atST, rt, rtST, err := maker.CreateTokenPair(userID, sessionID, Access{}, time.Minute, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, TypeAccess, at.Type)
+	require.Equal(t, TypeRefresh, rt.Type)
+
+	payload, err := maker.VerifyTokenFor(atST, TypeAccess)
+	require.NoError(t, err)
+	require.Equal(t, at.ID, payload.ID)
+	require.Equal(t, sessionID, payload.SessionID)
+
+	payload, err = maker.VerifyTokenFor(rtST, TypeRefresh)
+	require.NoError(t, err)
+	require.Equal(t, rt.ID, payload.ID)
+	require.Equal(t, sessionID, payload.SessionID)
+
+	// an access token is not a refresh token and inversely
+	payload, err = maker.VerifyTokenFor(atST, TypeRefresh)
+	require.EqualError(t, err, ErrInvalidTokenType.Error())
+	require.Nil(t, payload)
+
+	payload, err = maker.VerifyTokenFor(rtST, TypeAccess)
+	require.EqualError(t, err, ErrInvalidTokenType.Error())
+	require.Nil(t, payload)
+}
+
+func TestPasetoIssuerAudience(t *testing.T) {
+	key := util.RandomString(32)
+	maker, err := NewPasetoMaker(key, issuer, audience)
+	require.NoError(t, err)
+
+	userID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	otherIssuer, err := NewPasetoMaker(key, "other-issuer", audience)
+	require.NoError(t, err)
+	_, ss, err := otherIssuer.CreateToken(TypeAccess, userID, Access{}, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(ss)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	otherAudience, err := NewPasetoMaker(key, issuer, "other-audience")
+	require.NoError(t, err)
+	_, ss, err = otherAudience.CreateToken(TypeAccess, userID, Access{}, time.Minute)
+	require.NoError(t, err)
+
+	payload, err = maker.VerifyTokenFor(ss, TypeAccess)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
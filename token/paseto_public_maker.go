@@ -0,0 +1,171 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/o1egl/paseto"
+)
+
+// PublicKey is a verification key published for the services verifying our tokens
+type PublicKey struct {
+	ID        string    `json:"kid"`
+	Version   string    `json:"version"`
+	Purpose   string    `json:"purpose"`
+	PublicKey string    `json:"public_key"`
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after,omitempty"`
+}
+
+// KeyPublisher is implemented by the makers whose tokens can be verified with public keys
+type KeyPublisher interface {
+	// PublicKeys returns the keys which verify the tokens of the maker, including the upcoming ones
+	PublicKeys() []PublicKey
+}
+
+type publicKeyFooter struct {
+	KeyID string `json:"kid"`
+}
+
+// PasetoPublicMaker is a PASETO v2.public token maker. The tokens are signed with Ed25519 keys and carry
+// the ID of their key in the footer, so that keys can be rotated without signing everyone out.
+type PasetoPublicMaker struct {
+	paseto   *paseto.V2
+	keys     keySet
+	issuer   string
+	audience string
+}
+
+// NewPasetoPublicMaker creates a new PasetoPublicMaker with the given keys. The tokens it makes are issued
+// by issuer for audience and only the tokens with the same issuer and audience are accepted.
+func NewPasetoPublicMaker(keys []Key, issuer string, audience string) (Maker, error) {
+	set, err := newKeySet(keys, AlgorithmEdDSA)
+	if err != nil {
+		return nil, err
+	}
+
+	maker := &PasetoPublicMaker{
+		paseto:   paseto.NewV2(),
+		keys:     set,
+		issuer:   issuer,
+		audience: audience,
+	}
+
+	return maker, nil
+}
+
+// CreateToken creates a new token of a type for a specific userID and duration
+func (maker *PasetoPublicMaker) CreateToken(tokenType Type, userID uuid.UUID, access Access, duration time.Duration) (*Payload, string, error) {
+	payload, err := NewPayload(tokenType, userID, access, duration)
+	if err != nil {
+		return &Payload{}, "", err
+	}
+
+	st, err := maker.signPayload(payload)
+	if err != nil {
+		return &Payload{}, "", err
+	}
+	return payload, st, nil
+}
+
+// signPayload sets the issuer and the audience of the payload and signs it with the current key
+func (maker *PasetoPublicMaker) signPayload(payload *Payload) (string, error) {
+	payload.Issuer = maker.issuer
+	payload.Audience = maker.audience
+
+	key, err := maker.keys.signingKey(payload.IssuedAt)
+	if err != nil {
+		return "", err
+	}
+
+	return maker.paseto.Sign(key.private, payload, publicKeyFooter{KeyID: key.id})
+}
+
+// CreateTokenPair creates a new pair of tokens of a session for a specific userID and duration
+func (maker *PasetoPublicMaker) CreateTokenPair(userID uuid.UUID, sessionID string, access Access, accessDuration time.Duration, refreshDuration time.Duration) (Payload, string, Payload, string, error) {
+	at, rt, err := newPayloadPair(userID, sessionID, access, accessDuration, refreshDuration)
+	if err != nil {
+		return Payload{}, "", Payload{}, "", err
+	}
+
+	atST, err := maker.signPayload(at)
+	if err != nil {
+		return Payload{}, "", Payload{}, "", err
+	}
+
+	rtST, err := maker.signPayload(rt)
+	if err != nil {
+		return Payload{}, "", Payload{}, "", err
+	}
+	return *at, atST, *rt, rtST, nil
+}
+
+// VerifyToken checks if the token is valid or not, whatever its type
+func (maker *PasetoPublicMaker) VerifyToken(token string) (*Payload, error) {
+	version, purpose, err := paseto.GetTokenInfo(token)
+	if err != nil || version != paseto.Version2 || purpose != paseto.PUBLIC {
+		return nil, ErrInvalidToken
+	}
+
+	var footer publicKeyFooter
+	if err := paseto.ParseFooter(token, &footer); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	key, ok := maker.keys.verificationKey(footer.KeyID, time.Now())
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	payload := &Payload{}
+	if err := maker.paseto.Verify(token, key.public, payload, nil); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if payload.Issuer != maker.issuer || payload.Audience != maker.audience {
+		return nil, ErrInvalidToken
+	}
+
+	err = payload.Valid()
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// VerifyTokenFor checks if the token is valid and has the expected type
+func (maker *PasetoPublicMaker) VerifyTokenFor(token string, tokenType Type) (*Payload, error) {
+	payload, err := maker.VerifyToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.Type != tokenType {
+		return nil, ErrInvalidTokenType
+	}
+
+	return payload, nil
+}
+
+// PublicKeys returns the keys which verify the tokens of the maker, including the upcoming ones
+func (maker *PasetoPublicMaker) PublicKeys() []PublicKey {
+	now := time.Now()
+	keys := make([]PublicKey, 0, len(maker.keys))
+	for _, key := range maker.keys {
+		if key.expired(now) {
+			continue
+		}
+		keys = append(keys, PublicKey{
+			ID:        key.id,
+			Version:   string(paseto.Version2),
+			Purpose:   "public",
+			PublicKey: hex.EncodeToString(key.public.(ed25519.PublicKey)),
+			NotBefore: key.notBefore,
+			NotAfter:  key.notAfter,
+		})
+	}
+	return keys
+}
@@ -0,0 +1,194 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awakim/immoblock-backend/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func randomKey(t *testing.T, notBefore time.Time) Key {
+	key, err := GenerateKey(util.RandomString(8), notBefore)
+	require.NoError(t, err)
+	return key
+}
+
+func TestPasetoPublicMaker(t *testing.T) {
+	key := randomKey(t, time.Now().Add(-time.Hour))
+	maker, err := NewPasetoPublicMaker([]Key{key}, issuer, audience)
+	require.NoError(t, err)
+
+	userID, err := uuid.NewRandom()
+	require.NoError(t, err)
+	duration := time.Minute
+
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(duration)
+
+	token, ss, err := maker.CreateToken(TypeAccess, userID, testAccess, duration)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, ss)
+
+	payload, err := maker.VerifyTokenFor(ss, TypeAccess)
+	require.NoError(t, err)
+
+	require.Equal(t, token.ID, payload.ID)
+	require.Equal(t, userID, payload.UserID)
+	require.Equal(t, testAccess, payload.Access)
+	require.Equal(t, issuer, payload.Issuer)
+	require.Equal(t, audience, payload.Audience)
+	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+
+	payload, err = maker.VerifyTokenFor(ss, TypeRefresh)
+	require.EqualError(t, err, ErrInvalidTokenType.Error())
+	require.Nil(t, payload)
+}
+
+func TestExpiredPasetoPublicToken(t *testing.T) {
+	maker, err := NewPasetoPublicMaker([]Key{randomKey(t, time.Now().Add(-time.Hour))}, issuer, audience)
+	require.NoError(t, err)
+
+	userID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	_, ss, err := maker.CreateToken(TypeAccess, userID, Access{}, -time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(ss)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestPasetoPublicMakerKeyRotation(t *testing.T) {
+	now := time.Now()
+	oldKey := randomKey(t, now.Add(-2*time.Hour))
+	currentKey := randomKey(t, now.Add(-time.Hour))
+	nextKey := randomKey(t, now.Add(time.Hour))
+
+	oldMaker, err := NewPasetoPublicMaker([]Key{oldKey}, issuer, audience)
+	require.NoError(t, err)
+	maker, err := NewPasetoPublicMaker([]Key{nextKey, oldKey, currentKey}, issuer, audience)
+	require.NoError(t, err)
+
+	userID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	// tokens are signed with the latest key which has started signing
+	_, ss, err := maker.CreateToken(TypeAccess, userID, Access{}, time.Minute)
+	require.NoError(t, err)
+
+	currentMaker, err := NewPasetoPublicMaker([]Key{{ID: currentKey.ID, PublicKey: currentKey.PublicKey}}, issuer, audience)
+	require.NoError(t, err)
+	_, err = currentMaker.VerifyToken(ss)
+	require.NoError(t, err)
+
+	// tokens signed with a previous key are still accepted
+	_, ss, err = oldMaker.CreateToken(TypeAccess, userID, Access{}, time.Minute)
+	require.NoError(t, err)
+	_, err = maker.VerifyToken(ss)
+	require.NoError(t, err)
+
+	// until the key is retired
+	oldKey.NotAfter = now.Add(-time.Minute)
+	maker, err = NewPasetoPublicMaker([]Key{nextKey, oldKey, currentKey}, issuer, audience)
+	require.NoError(t, err)
+	payload, err := maker.VerifyToken(ss)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	// the upcoming key is published ahead of time, the retired one is not
+	keys := maker.(KeyPublisher).PublicKeys()
+	require.Len(t, keys, 2)
+	require.Equal(t, currentKey.ID, keys[0].ID)
+	require.Equal(t, currentKey.PublicKey, keys[0].PublicKey)
+	require.Equal(t, nextKey.ID, keys[1].ID)
+	require.Equal(t, "v2", keys[1].Version)
+	require.Equal(t, "public", keys[1].Purpose)
+}
+
+func TestPasetoPublicMakerUnknownKey(t *testing.T) {
+	maker, err := NewPasetoPublicMaker([]Key{randomKey(t, time.Now().Add(-time.Hour))}, issuer, audience)
+	require.NoError(t, err)
+	otherMaker, err := NewPasetoPublicMaker([]Key{randomKey(t, time.Now().Add(-time.Hour))}, issuer, audience)
+	require.NoError(t, err)
+
+	userID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	_, ss, err := otherMaker.CreateToken(TypeAccess, userID, Access{}, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(ss)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	// a local token is not accepted either
+	localMaker, err := NewPasetoMaker(util.RandomString(32), issuer, audience)
+	require.NoError(t, err)
+	_, ss, err = localMaker.CreateToken(TypeAccess, userID, Access{}, time.Minute)
+	require.NoError(t, err)
+
+	payload, err = maker.VerifyToken(ss)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestPasetoPublicMakerInvalidKeys(t *testing.T) {
+	key := randomKey(t, time.Now())
+	other := randomKey(t, time.Now())
+
+	testCases := []struct {
+		name string
+		keys []Key
+	}{
+		{
+			name: "NoKeys",
+			keys: nil,
+		},
+		{
+			name: "MissingID",
+			keys: []Key{{PrivateKey: key.PrivateKey}},
+		},
+		{
+			name: "DuplicateID",
+			keys: []Key{key, {ID: key.ID, PublicKey: other.PublicKey}},
+		},
+		{
+			name: "MissingKey",
+			keys: []Key{{ID: key.ID}},
+		},
+		{
+			name: "InvalidPrivateKey",
+			keys: []Key{{ID: key.ID, PrivateKey: "invalid"}},
+		},
+		{
+			name: "MismatchingPublicKey",
+			keys: []Key{{ID: key.ID, PrivateKey: key.PrivateKey, PublicKey: other.PublicKey}},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			maker, err := NewPasetoPublicMaker(tc.keys, issuer, audience)
+			require.Error(t, err)
+			require.Nil(t, maker)
+		})
+	}
+}
+
+func TestPasetoPublicMakerNoSigningKey(t *testing.T) {
+	key := randomKey(t, time.Now().Add(time.Hour))
+	maker, err := NewPasetoPublicMaker([]Key{key}, issuer, audience)
+	require.NoError(t, err)
+
+	userID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	_, _, err = maker.CreateToken(TypeAccess, userID, Access{}, time.Minute)
+	require.ErrorIs(t, err, ErrNoSigningKey)
+}
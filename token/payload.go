@@ -9,17 +9,35 @@ import (
 
 // Different types of error returned by the VerifyToken function
 var (
-	ErrInvalidToken = fmt.Errorf("token is invalid")
-	ErrExpiredToken = fmt.Errorf("token has expired")
+	ErrInvalidToken     = fmt.Errorf("token is invalid")
+	ErrExpiredToken     = fmt.Errorf("token has expired")
+	ErrInvalidTokenType = fmt.Errorf("token has the wrong type")
+)
+
+// Type tells what a token is meant for. A token is only accepted for its own purpose.
+type Type string
+
+// Types of tokens
+const (
+	TypeAccess            Type = "access"
+	TypeRefresh           Type = "refresh"
+	TypeEmailVerification Type = "email_verification"
+	TypePasswordReset     Type = "password_reset"
+	TypeMFAChallenge      Type = "mfa_challenge"
 )
 
 // Payload contains the payload data of the token
 type Payload struct {
 	ID        uuid.UUID `json:"id"`
+	Type      Type      `json:"type"`
+	Issuer    string    `json:"issuer"`
+	Audience  string    `json:"audience"`
 	UserID    uuid.UUID `json:"user_id"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
-	IsAdmin   bool      `json:"is_admin"`
+	// SessionID is the session of the access and refresh tokens, empty for the other types
+	SessionID string `json:"session_id,omitempty"`
+	Access
 }
 
 // TokenPair contains the token pair returned at login or refresh
@@ -28,8 +46,9 @@ type TokenPair struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
-// NewPayload creates a new token payload with a specific username and duration
-func NewPayload(userID uuid.UUID, isAdmin bool, duration time.Duration) (*Payload, error) {
+// NewPayload creates a new token payload of a type with a specific username, access and duration.
+// The issuer and the audience are set by the maker.
+func NewPayload(tokenType Type, userID uuid.UUID, access Access, duration time.Duration) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
@@ -37,14 +56,36 @@ func NewPayload(userID uuid.UUID, isAdmin bool, duration time.Duration) (*Payloa
 
 	payload := &Payload{
 		ID:        tokenID,
+		Type:      tokenType,
 		UserID:    userID,
 		IssuedAt:  time.Now().UTC(),
 		ExpiredAt: time.Now().UTC().Add(duration),
-		IsAdmin:   isAdmin,
+		Access:    access,
 	}
 	return payload, nil
 }
 
+// newPayloadPair creates the payloads of an access and a refresh token of the same session.
+func newPayloadPair(userID uuid.UUID, sessionID string, access Access, accessDuration time.Duration, refreshDuration time.Duration) (*Payload, *Payload, error) {
+	at, err := NewPayload(TypeAccess, userID, access, accessDuration)
+	if err != nil {
+		return nil, nil, err
+	}
+	rt, err := NewPayload(TypeRefresh, userID, access, refreshDuration)
+	if err != nil {
+		return nil, nil, err
+	}
+	at.SessionID = sessionID
+	rt.SessionID = sessionID
+	return at, rt, nil
+}
+
+// IssuedBefore tells whether the token was issued before t. The times are compared in whole seconds,
+// the precision of the JWT dates, so a token issued in the same second as t is not issued before it.
+func (payload *Payload) IssuedBefore(t time.Time) bool {
+	return payload.IssuedAt.Unix() < t.Unix()
+}
+
 // Valid checks if the token payload is valid or not
 func (payload *Payload) Valid() error {
 	if time.Now().After(payload.ExpiredAt) {